@@ -15,6 +15,11 @@ func main() {
 		cmd.NewBuildCommand(build.Run),
 		cmd.NewGenerateCommand(build.Generate),
 		cmd.NewValidateCommand(build.Validate),
+		cmd.NewInitCommand(build.Init),
+		cmd.NewMigrateCommand(build.Migrate),
+		cmd.NewSchemaCommand(build.Schema),
+		cmd.NewVerifyCommand(build.Verify),
+		cmd.NewTestCommand(build.Test),
 		cmd.NewVersionCommand(build.Version),
 	}
 
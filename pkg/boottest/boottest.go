@@ -0,0 +1,270 @@
+package boottest
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const (
+	qemuImgExec               = "qemu-img"
+	kubernetesPollingInterval = 10 * time.Second
+)
+
+type Options struct {
+	// ImagePath is the uncompressed RAW, qcow2 or ISO image to boot.
+	ImagePath string
+	ImageType string
+	Arch      image.Arch
+	// Firmware overrides the firmware the virtual machine is booted with.
+	Firmware string
+	// Memory of the virtual machine in MB.
+	Memory int
+	CPUs   int
+	// DiskInterface determines the device name of the disk, e.g. '/dev/sda' for SATA disks.
+	DiskInterface string
+	// ScratchDiskSize is the size of the disk ISO images are installed to.
+	ScratchDiskSize string
+	// Kubernetes enables waiting for the Kubernetes API server once combustion completed.
+	Kubernetes bool
+	Timeout    time.Duration
+	// LogPath is where the serial console of the virtual machine is written to.
+	LogPath string
+}
+
+type Result struct {
+	Passed bool
+	// Reason describes why the test failed.
+	Reason          string
+	Scripts         []Script
+	KubernetesReady bool
+}
+
+// Run boots the image in a virtual machine and waits for combustion to complete. A failed boot is
+// reported in the result, while errors are only returned if the virtual machine could not be run.
+func Run(opts *Options) (*Result, error) {
+	firmware, err := findFirmware(opts.Arch, opts.Firmware)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "eib-test-")
+	if err != nil {
+		return nil, fmt.Errorf("creating working directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	diskPath, err := createDisk(opts, workDir)
+	if err != nil {
+		return nil, fmt.Errorf("creating virtual machine disk: %w", err)
+	}
+
+	var apiPort int
+	if opts.Kubernetes {
+		if apiPort, err = freePort(); err != nil {
+			return nil, fmt.Errorf("reserving Kubernetes API port: %w", err)
+		}
+	}
+
+	logFile, err := os.Create(opts.LogPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating log file: %w", err)
+	}
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("Failed to close boot test log file properly: %s", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, qemuExec(opts.Arch), qemuArgs(opts, diskPath, firmware, apiPort)...)
+	cmd.Stderr = logFile
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the serial console: %w", err)
+	}
+
+	zap.S().Infof("Starting virtual machine: %s", cmd.String())
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting virtual machine: %w", err)
+	}
+
+	t := &tracker{}
+	finished := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go followConsole(stdout, logFile, t, finished, stopped)
+
+	defer func() {
+		cancel()
+		_ = cmd.Wait()
+		<-stopped
+	}()
+
+	result := &Result{}
+
+	select {
+	case <-finished:
+	case <-stopped:
+	case <-ctx.Done():
+	}
+
+	// The tracker is no longer updated once combustion finished or the console was closed
+	switch {
+	case isClosed(finished):
+		result.Scripts = t.scripts
+		if err = t.verify(); err != nil {
+			result.Reason = err.Error()
+			return result, nil
+		}
+	case isClosed(stopped):
+		result.Scripts = t.scripts
+		result.Reason = "virtual machine stopped before combustion completed"
+		return result, nil
+	default:
+		result.Reason = fmt.Sprintf("combustion did not complete within %s", opts.Timeout)
+		return result, nil
+	}
+
+	if opts.Kubernetes {
+		if err = waitForKubernetes(ctx, apiPort, stopped); err != nil {
+			result.Reason = err.Error()
+			return result, nil
+		}
+
+		result.KubernetesReady = true
+	}
+
+	result.Passed = true
+	return result, nil
+}
+
+// followConsole writes the serial console to the log and tracks the progress of combustion.
+// The finished channel is closed as soon as combustion completed or failed, after which the
+// tracker is left untouched. The stopped channel is closed once the console is closed.
+func followConsole(console io.Reader, log io.Writer, t *tracker, finished, stopped chan struct{}) {
+	defer close(stopped)
+
+	scanner := bufio.NewScanner(console)
+	tracking := true
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if _, err := fmt.Fprintln(log, line); err != nil {
+			zap.S().Warnf("Failed to write to boot test log: %s", err)
+		}
+
+		if !tracking {
+			continue
+		}
+
+		t.process(line)
+		if t.finished() {
+			tracking = false
+			close(finished)
+		}
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// createDisk creates the disk the virtual machine boots from. RAW and qcow2 images are only used as
+// the backing file of an overlay so that they are not modified by the test.
+func createDisk(opts *Options, workDir string) (string, error) {
+	diskPath := filepath.Join(workDir, "disk.qcow2")
+	args := []string{"create", "-f", "qcow2"}
+
+	switch opts.ImageType {
+	case image.TypeISO:
+		args = append(args, diskPath, opts.ScratchDiskSize)
+	default:
+		imagePath, err := filepath.Abs(opts.ImagePath)
+		if err != nil {
+			return "", fmt.Errorf("resolving image path: %w", err)
+		}
+
+		format := image.TypeRAW
+		if opts.ImageType == image.TypeQCOW2 {
+			format = image.TypeQCOW2
+		}
+
+		args = append(args, "-b", imagePath, "-F", format, diskPath)
+	}
+
+	output, err := exec.Command(qemuImgExec, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w: %s", qemuImgExec, err, output)
+	}
+
+	return diskPath, nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitForKubernetes polls the readiness endpoint of the Kubernetes API server forwarded to the given port.
+// The server is considered ready once it responds, even if it rejects the anonymous request.
+func waitForKubernetes(ctx context.Context, port int, stopped <-chan struct{}) error {
+	client := &http.Client{
+		Timeout: kubernetesPollingInterval,
+		Transport: &http.Transport{
+			// The certificates of the cluster are generated at boot and can not be known in advance
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+	}
+
+	url := fmt.Sprintf("https://127.0.0.1:%d/readyz", port)
+
+	ticker := time.NewTicker(kubernetesPollingInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+
+			switch resp.StatusCode {
+			case http.StatusOK, http.StatusUnauthorized, http.StatusForbidden:
+				return nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stopped:
+			return fmt.Errorf("virtual machine stopped before the Kubernetes API server was ready")
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the Kubernetes API server")
+		}
+	}
+}
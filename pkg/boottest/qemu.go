@@ -0,0 +1,101 @@
+package boottest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	DiskInterfaceSATA   = "sata"
+	DiskInterfaceVirtIO = "virtio"
+	DiskInterfaceNVMe   = "nvme"
+
+	kubernetesAPIPort = 6443
+)
+
+// Locations of the UEFI firmware required to boot aarch64 virtual machines on the supported distributions.
+var aarch64Firmware = []string{
+	"/usr/share/qemu/aavmf-aarch64-code.bin",
+	"/usr/share/AAVMF/AAVMF_CODE.fd",
+	"/usr/share/edk2/aarch64/QEMU_EFI.fd",
+}
+
+func qemuExec(arch image.Arch) string {
+	if arch == image.ArchTypeARM {
+		return "qemu-system-aarch64"
+	}
+
+	return "qemu-system-x86_64"
+}
+
+// findFirmware returns the firmware to boot the virtual machine with. An empty path boots x86_64
+// machines with the default BIOS of QEMU.
+func findFirmware(arch image.Arch, firmware string) (string, error) {
+	if firmware != "" || arch != image.ArchTypeARM {
+		return firmware, nil
+	}
+
+	for _, path := range aarch64Firmware {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no UEFI firmware found for %s, please specify one explicitly", arch)
+}
+
+// qemuArgs assembles the arguments of a virtual machine using software emulation, so that no
+// hardware virtualization is required. The serial console is written to the standard output.
+//
+// The disk is preferred over the ISO in the boot order. An empty scratch disk falls back to
+// booting the installer, while the installed system is booted once the installer reboots.
+func qemuArgs(opts *Options, diskPath, firmware string, apiPort int) []string {
+	args := []string{
+		"-accel", "tcg",
+		"-cpu", "max",
+		"-m", strconv.Itoa(opts.Memory),
+		"-smp", strconv.Itoa(opts.CPUs),
+		"-display", "none",
+		"-serial", "stdio",
+		"-monitor", "none",
+	}
+
+	if opts.Arch == image.ArchTypeARM {
+		args = append(args, "-machine", "virt")
+	} else {
+		args = append(args, "-machine", "q35")
+	}
+
+	if firmware != "" {
+		args = append(args, "-bios", firmware)
+	}
+
+	netdev := "user,id=net0"
+	if apiPort != 0 {
+		netdev += fmt.Sprintf(",hostfwd=tcp:127.0.0.1:%d-:%d", apiPort, kubernetesAPIPort)
+	}
+	args = append(args, "-netdev", netdev, "-device", "virtio-net-pci,netdev=net0")
+
+	args = append(args, "-device", "ahci,id=ahci0")
+	args = append(args, "-drive", fmt.Sprintf("file=%s,format=qcow2,if=none,id=disk0", diskPath))
+
+	switch opts.DiskInterface {
+	case DiskInterfaceVirtIO:
+		args = append(args, "-device", "virtio-blk-pci,drive=disk0,bootindex=1")
+	case DiskInterfaceNVMe:
+		args = append(args, "-device", "nvme,drive=disk0,serial=eib-test,bootindex=1")
+	default:
+		args = append(args, "-device", "ide-hd,drive=disk0,bus=ahci0.0,bootindex=1")
+	}
+
+	if opts.ImageType == image.TypeISO {
+		args = append(args,
+			"-drive", fmt.Sprintf("file=%s,format=raw,media=cdrom,readonly=on,if=none,id=cd0", opts.ImagePath),
+			"-device", "ide-cd,drive=cd0,bus=ahci0.1,bootindex=2")
+	}
+
+	return args
+}
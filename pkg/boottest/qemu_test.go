@@ -0,0 +1,80 @@
+package boottest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestQEMUArgs_RAW(t *testing.T) {
+	opts := &Options{
+		ImagePath:     "eib-image.raw",
+		ImageType:     image.TypeRAW,
+		Arch:          image.ArchTypeX86,
+		Memory:        4096,
+		CPUs:          2,
+		DiskInterface: DiskInterfaceVirtIO,
+	}
+
+	args := qemuArgs(opts, "/tmp/disk.qcow2", "", 0)
+
+	assert.Equal(t, []string{
+		"-accel", "tcg",
+		"-cpu", "max",
+		"-m", "4096",
+		"-smp", "2",
+		"-display", "none",
+		"-serial", "stdio",
+		"-monitor", "none",
+		"-machine", "q35",
+		"-netdev", "user,id=net0",
+		"-device", "virtio-net-pci,netdev=net0",
+		"-device", "ahci,id=ahci0",
+		"-drive", "file=/tmp/disk.qcow2,format=qcow2,if=none,id=disk0",
+		"-device", "virtio-blk-pci,drive=disk0,bootindex=1",
+	}, args)
+}
+
+func TestQEMUArgs_ISO(t *testing.T) {
+	opts := &Options{
+		ImagePath:     "eib-image.iso",
+		ImageType:     image.TypeISO,
+		Arch:          image.ArchTypeARM,
+		Memory:        8192,
+		CPUs:          4,
+		DiskInterface: DiskInterfaceSATA,
+		Kubernetes:    true,
+	}
+
+	args := qemuArgs(opts, "/tmp/disk.qcow2", "/usr/share/qemu/aavmf-aarch64-code.bin", 40000)
+
+	assert.Equal(t, []string{
+		"-accel", "tcg",
+		"-cpu", "max",
+		"-m", "8192",
+		"-smp", "4",
+		"-display", "none",
+		"-serial", "stdio",
+		"-monitor", "none",
+		"-machine", "virt",
+		"-bios", "/usr/share/qemu/aavmf-aarch64-code.bin",
+		"-netdev", "user,id=net0,hostfwd=tcp:127.0.0.1:40000-:6443",
+		"-device", "virtio-net-pci,netdev=net0",
+		"-device", "ahci,id=ahci0",
+		"-drive", "file=/tmp/disk.qcow2,format=qcow2,if=none,id=disk0",
+		"-device", "ide-hd,drive=disk0,bus=ahci0.0,bootindex=1",
+		"-drive", "file=eib-image.iso,format=raw,media=cdrom,readonly=on,if=none,id=cd0",
+		"-device", "ide-cd,drive=cd0,bus=ahci0.1,bootindex=2",
+	}, args)
+}
+
+func TestFindFirmware(t *testing.T) {
+	firmware, err := findFirmware(image.ArchTypeX86, "")
+	assert.NoError(t, err)
+	assert.Empty(t, firmware)
+
+	firmware, err = findFirmware(image.ArchTypeARM, "/custom/firmware.fd")
+	assert.NoError(t, err)
+	assert.Equal(t, "/custom/firmware.fd", firmware)
+}
@@ -0,0 +1,95 @@
+package boottest
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The markers are written to the console by the combustion script assembled from script-base.sh.tpl.
+var (
+	scriptStartedRegexp   = regexp.MustCompile(`(?:^|\s)Running (\S+)$`)
+	scriptCompletedRegexp = regexp.MustCompile(`(?:^|\s)Completed (\S+)$`)
+	combustionDoneRegexp  = regexp.MustCompile(`(?:^|\s)Completed (\d+) combustion scripts$`)
+)
+
+// Console messages indicating that the boot failed and waiting any longer is pointless.
+var failureMarkers = []string{
+	"Kernel panic",
+	"You are in emergency mode",
+	"Failed to start Combustion",
+	"combustion.service: Failed with result",
+}
+
+type Script struct {
+	Name      string
+	Completed bool
+}
+
+// tracker follows the progress of combustion from the console output of the virtual machine.
+type tracker struct {
+	scripts  []Script
+	expected int
+	done     bool
+	failure  string
+}
+
+func (t *tracker) process(line string) {
+	line = strings.TrimSpace(line)
+
+	for _, marker := range failureMarkers {
+		if strings.Contains(line, marker) {
+			t.failure = fmt.Sprintf("the virtual machine reported '%s'", line)
+			return
+		}
+	}
+
+	if m := combustionDoneRegexp.FindStringSubmatch(line); m != nil {
+		// The pattern only matches digits, so the conversion can not fail
+		t.expected, _ = strconv.Atoi(m[1])
+		t.done = true
+		return
+	}
+
+	if m := scriptStartedRegexp.FindStringSubmatch(line); m != nil {
+		t.scripts = append(t.scripts, Script{Name: m[1]})
+		return
+	}
+
+	if m := scriptCompletedRegexp.FindStringSubmatch(line); m != nil {
+		for i := range t.scripts {
+			if t.scripts[i].Name == m[1] {
+				t.scripts[i].Completed = true
+			}
+		}
+	}
+}
+
+func (t *tracker) finished() bool {
+	return t.done || t.failure != ""
+}
+
+// verify checks that combustion finished and that every script it started has completed.
+func (t *tracker) verify() error {
+	if t.failure != "" {
+		return errors.New(t.failure)
+	}
+
+	for _, script := range t.scripts {
+		if !script.Completed {
+			return fmt.Errorf("combustion script '%s' did not complete", script.Name)
+		}
+	}
+
+	if !t.done {
+		return fmt.Errorf("combustion did not complete")
+	}
+
+	if len(t.scripts) != t.expected {
+		return fmt.Errorf("expected %d combustion scripts to run, found %d", t.expected, len(t.scripts))
+	}
+
+	return nil
+}
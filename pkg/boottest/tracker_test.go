@@ -0,0 +1,88 @@
+package boottest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tests := map[string]struct {
+		console          string
+		expectedFinished bool
+		expectedScripts  []Script
+		expectedError    string
+	}{
+		"Completed": {
+			console: `[    4.120000] systemd[1]: Running in initrd.
+Running 05-configure-network.sh
+[   12.500000] combustion[512]: Completed 05-configure-network.sh
+Running 10-rpm-install.sh
+Completed 10-rpm-install.sh
+Completed 2 combustion scripts
+`,
+			expectedFinished: true,
+			expectedScripts: []Script{
+				{Name: "05-configure-network.sh", Completed: true},
+				{Name: "10-rpm-install.sh", Completed: true},
+			},
+		},
+		"Script failed": {
+			console: `Running 05-configure-network.sh
+Completed 05-configure-network.sh
+Running 10-rpm-install.sh
+[FAILED] Failed to start Combustion.
+`,
+			expectedFinished: true,
+			expectedScripts: []Script{
+				{Name: "05-configure-network.sh", Completed: true},
+				{Name: "10-rpm-install.sh"},
+			},
+			expectedError: "the virtual machine reported '[FAILED] Failed to start Combustion.'",
+		},
+		"Still running": {
+			console: `Running 05-configure-network.sh
+`,
+			expectedScripts: []Script{
+				{Name: "05-configure-network.sh"},
+			},
+			expectedError: "combustion script '05-configure-network.sh' did not complete",
+		},
+		"Missing scripts": {
+			console: `Running 10-rpm-install.sh
+Completed 10-rpm-install.sh
+Completed 2 combustion scripts
+`,
+			expectedFinished: true,
+			expectedScripts: []Script{
+				{Name: "10-rpm-install.sh", Completed: true},
+			},
+			expectedError: "expected 2 combustion scripts to run, found 1",
+		},
+		"Kernel panic": {
+			console:          "[    1.200000] Kernel panic - not syncing: VFS: Unable to mount root fs\n",
+			expectedFinished: true,
+			expectedError:    "the virtual machine reported '[    1.200000] Kernel panic - not syncing: VFS: Unable to mount root fs'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tr := &tracker{}
+			for _, line := range strings.Split(test.console, "\n") {
+				tr.process(line + "\r")
+			}
+
+			assert.Equal(t, test.expectedFinished, tr.finished())
+			assert.Equal(t, test.expectedScripts, tr.scripts)
+
+			err := tr.verify()
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
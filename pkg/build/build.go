@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/containerdisk"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 )
@@ -16,6 +19,8 @@ type imageConfigurator interface {
 type Builder struct {
 	context           *image.Context
 	imageConfigurator imageConfigurator
+	// calculatedDiskSize is set when the disk size of RAW and qcow2 images is determined during the build
+	calculatedDiskSize image.DiskSize
 }
 
 func NewBuilder(ctx *image.Context, imageConfigurator imageConfigurator) *Builder {
@@ -26,11 +31,21 @@ func NewBuilder(ctx *image.Context, imageConfigurator imageConfigurator) *Builde
 }
 
 func (b *Builder) Build() error {
-	log.Audit("Generating image customization components...")
+	startedOn := time.Now()
 
-	if err := b.imageConfigurator.Configure(b.context); err != nil {
-		log.Audit("Error configuring customization components.")
-		return fmt.Errorf("configuring image: %w", err)
+	if b.context.Checkpoints.Completed(checkpoint.StageCombustionConfigured) {
+		log.Audit("Skipping image customization components, they were generated before the build was interrupted.")
+	} else {
+		log.Audit("Generating image customization components...")
+
+		if err := b.imageConfigurator.Configure(b.context); err != nil {
+			log.Audit("Error configuring customization components.")
+			return fmt.Errorf("configuring image: %w", err)
+		}
+
+		if err := b.checkpoint(checkpoint.StageCombustionConfigured); err != nil {
+			return err
+		}
 	}
 
 	switch b.context.ImageDefinition.Image.ImageType {
@@ -46,13 +61,89 @@ func (b *Builder) Build() error {
 			log.Audit("Error building RAW image.")
 			return err
 		}
+	case image.TypeQCOW2:
+		log.Audit("Building qcow2 image...")
+		if err := b.buildQCOW2Image(); err != nil {
+			log.Audit("Error building qcow2 image.")
+			return err
+		}
+	case image.TypePXE:
+		log.Audit("Building PXE artefacts...")
+		if err := b.buildPXEArtefacts(); err != nil {
+			log.Audit("Error building PXE artefacts.")
+			return err
+		}
 	default:
-		return fmt.Errorf("invalid imageType value specified, must be either \"%s\" or \"%s\"",
-			image.TypeISO, image.TypeRAW)
+		return fmt.Errorf("invalid imageType value specified, must be one of \"%s\", \"%s\", \"%s\" or \"%s\"",
+			image.TypeISO, image.TypeRAW, image.TypeQCOW2, image.TypePXE)
+	}
+
+	if err := b.checkpoint(checkpoint.StageImageBuilt); err != nil {
+		return err
+	}
+
+	// The containerDisk is created from the uncompressed output, which is removed once compressed
+	if b.context.ImageDefinition.Image.ContainerDisk.Format != "" && !b.context.Checkpoints.Completed(checkpoint.StageOutputCompressed) {
+		log.Audit("Creating containerDisk...")
+		if err := b.createContainerDisk(); err != nil {
+			log.Audit("Error creating containerDisk.")
+			return err
+		}
+	}
+
+	outputPath := b.context.OutputPath()
+
+	// PXE artefacts are written to a directory which is served as is
+	if b.context.ImageDefinition.Image.ImageType != image.TypePXE {
+		var err error
+		if outputPath, err = finalizeOutput(b.context); err != nil {
+			log.Audit("Error finalizing the image.")
+			return err
+		}
+
+		if err = signOutput(b.context, outputPath); err != nil {
+			log.Audit("Error signing the image.")
+			return err
+		}
+	}
+
+	if err := writeSBOM(b.context); err != nil {
+		log.Audit("Error writing the SBOM.")
+		return err
+	}
+
+	if err := writeProvenance(b.context, outputPath, startedOn); err != nil {
+		log.Audit("Error writing the provenance attestation.")
+		return err
+	}
+
+	if b.calculatedDiskSize != "" {
+		log.Auditf("Disk size automatically set to %s.", b.calculatedDiskSize)
+	}
+
+	log.Auditf("Build complete, the image can be found at: %s", filepath.Base(outputPath))
+	return nil
+}
+
+func (b *Builder) createContainerDisk() error {
+	img := &b.context.ImageDefinition.Image
+	outputPath := containerdisk.OutputPath(b.context.OutputPath(), img.ContainerDisk.Format)
+
+	if err := containerdisk.Create(b.context.BuildContext(), b.context.OutputPath(), outputPath, img.ContainerDisk.Format, img.ContainerDisk.Tag, img.Arch, b.context.BuildDir); err != nil {
+		return fmt.Errorf("creating containerDisk: %w", err)
+	}
+
+	log.Auditf("The containerDisk can be found at: %s", filepath.Base(outputPath))
+	return nil
+}
+
+// checkpoint records the completed stage, so that it is skipped if the build is resumed.
+func (b *Builder) checkpoint(stage checkpoint.Stage) error {
+	if err := b.context.Checkpoints.Complete(stage); err != nil {
+		log.Audit("Error recording the build progress.")
+		return fmt.Errorf("recording %s checkpoint: %w", stage, err)
 	}
 
-	log.Auditf("Build complete, the image can be found at: %s",
-		b.context.ImageDefinition.Image.OutputImageName)
 	return nil
 }
 
@@ -1,13 +1,18 @@
 package build
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"go.uber.org/zap"
 )
 
@@ -21,30 +26,37 @@ func (g *Generator) generateCombustionISO() error {
 		return fmt.Errorf("deleting existing combustion iso: %w", err)
 	}
 
-	if err := g.createCombustionISO(); err != nil {
+	if err := createCombustionISO(g.context, g.context.OutputPath()); err != nil {
 		return fmt.Errorf("building combustion ISO: %w", err)
 	}
 
 	return nil
 }
 
-func (g *Generator) createCombustionISO() error {
-	combustionPath := filepath.Join(g.context.BuildDir, combustionTmpDir)
+// createCombustionISO creates a config drive containing the combustion and artefacts directories.
+func createCombustionISO(ctx *image.Context, outputPath string) error {
+	combustionPath := filepath.Join(ctx.BuildDir, combustionTmpDir)
 	if err := os.MkdirAll(combustionPath, 0o755); err != nil {
 		return fmt.Errorf("creating temp directory %s: %w", combustionPath, err)
 	}
 
-	combustionDestPath := filepath.Join(combustionPath, filepath.Base(g.context.CombustionDir))
-	if err := fileio.CopyFiles(g.context.CombustionDir, combustionDestPath, "", true, nil); err != nil {
+	combustionDestPath := filepath.Join(combustionPath, filepath.Base(ctx.CombustionDir))
+	if err := fileio.CopyFiles(ctx.CombustionDir, combustionDestPath, "", true, nil); err != nil {
 		return fmt.Errorf("copying combustion directory: %w", err)
 	}
 
-	artefactsDestPath := filepath.Join(combustionPath, filepath.Base(g.context.ArtefactsDir))
-	if err := fileio.CopyFiles(g.context.ArtefactsDir, artefactsDestPath, "", true, nil); err != nil {
+	artefactsDestPath := filepath.Join(combustionPath, filepath.Base(ctx.ArtefactsDir))
+	if err := fileio.CopyFiles(ctx.ArtefactsDir, artefactsDestPath, "", true, nil); err != nil {
 		return fmt.Errorf("copying artefacts directory: %w", err)
 	}
 
-	logFilename := filepath.Join(g.context.BuildDir, combustionScriptLogFile)
+	if ctx.SourceDateEpoch != nil {
+		if err := normalizeModTimes(combustionPath, *ctx.SourceDateEpoch); err != nil {
+			return fmt.Errorf("normalizing file timestamps: %w", err)
+		}
+	}
+
+	logFilename := filepath.Join(ctx.BuildDir, combustionScriptLogFile)
 	logFile, err := os.Create(logFilename)
 	if err != nil {
 		return fmt.Errorf("opening log file: %w", err)
@@ -56,20 +68,53 @@ func (g *Generator) createCombustionISO() error {
 		}
 	}()
 
-	if err := g.createISO(combustionPath, logFile); err != nil {
+	if err := createISO(ctx.BuildContext(), combustionPath, outputPath, ctx.SourceDateEpoch, logFile); err != nil {
 		return fmt.Errorf("creating ISO: %w", err)
 	}
 
 	return nil
 }
 
-func (g *Generator) createISO(sourcePath string, logFile io.Writer) error {
-	outputPath := g.context.OutputPath()
+func createISO(ctx context.Context, sourcePath, outputPath string, sourceDateEpoch *time.Time, logFile io.Writer) error {
+	cmd := process.Command(ctx, "mkisofs", "-J", "-o", outputPath, "-V", "COMBUSTION", sourcePath)
 
-	cmd := exec.Command("mkisofs", "-J", "-o", outputPath, "-V", "COMBUSTION", sourcePath)
+	// xorriso derives the volume timestamps and UUID from the source date epoch instead of the current time
+	if sourceDateEpoch != nil {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("SOURCE_DATE_EPOCH=%d", sourceDateEpoch.Unix()))
+	}
 
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
 	return cmd.Run()
 }
+
+// normalizeModTimes sets the modification time of every file and directory under the given path.
+// Directories are updated after their contents, since modifying the contents changes their timestamps.
+func normalizeModTimes(root string, modTime time.Time) error {
+	var paths []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Changing the timestamps of symbolic links would follow them
+		if d.Type()&fs.ModeSymlink == 0 {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walking %s: %w", root, err)
+	}
+
+	for _, path := range slices.Backward(paths) {
+		if err = os.Chtimes(path, modTime, modTime); err != nil {
+			return fmt.Errorf("setting timestamps of %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
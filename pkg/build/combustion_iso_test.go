@@ -0,0 +1,33 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeModTimes(t *testing.T) {
+	root := t.TempDir()
+
+	nestedDir := filepath.Join(root, "combustion", "nested")
+	require.NoError(t, os.MkdirAll(nestedDir, 0o700))
+
+	file := filepath.Join(nestedDir, "script")
+	require.NoError(t, os.WriteFile(file, []byte("#!/bin/bash"), 0o600))
+
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Symlink("/does/not/exist", link))
+
+	modTime := time.Unix(1700000000, 0)
+	require.NoError(t, normalizeModTimes(root, modTime))
+
+	for _, path := range []string{root, filepath.Join(root, "combustion"), nestedDir, file} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, modTime.Equal(info.ModTime()), path)
+	}
+}
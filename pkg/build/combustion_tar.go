@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 func (g *Generator) generateTarball() error {
@@ -31,6 +32,8 @@ func (g *Generator) createCompressedTarball() error {
 	}
 	defer file.Close()
 
+	// The gzip header is left without a name and modification time,
+	// so that it does not differ between runs
 	gw := gzip.NewWriter(file)
 	defer gw.Close()
 
@@ -38,7 +41,7 @@ func (g *Generator) createCompressedTarball() error {
 	defer tw.Close()
 
 	for _, dir := range directories {
-		err = addDirectoryToTar(tw, dir)
+		err = addDirectoryToTar(tw, dir, g.context.SourceDateEpoch)
 		if err != nil {
 			return fmt.Errorf("could not add '%s' directory to tarball: %w", dir, err)
 		}
@@ -46,7 +49,9 @@ func (g *Generator) createCompressedTarball() error {
 	return nil
 }
 
-func addDirectoryToTar(tw *tar.Writer, basePath string) error {
+// addDirectoryToTar adds the directory and its contents to the tarball in lexical order.
+// The headers are normalized if a source date epoch is provided.
+func addDirectoryToTar(tw *tar.Writer, basePath string, sourceDateEpoch *time.Time) error {
 	baseDir := filepath.Dir(basePath)
 
 	return filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
@@ -74,6 +79,10 @@ func addDirectoryToTar(tw *tar.Writer, basePath string) error {
 			header.Linkname = target
 		}
 
+		if sourceDateEpoch != nil {
+			normalizeTarHeader(header, *sourceDateEpoch)
+		}
+
 		if err = tw.WriteHeader(header); err != nil {
 			return fmt.Errorf("writing tar header for '%s': %w", path, err)
 		}
@@ -94,3 +103,29 @@ func addDirectoryToTar(tw *tar.Writer, basePath string) error {
 		return nil
 	})
 }
+
+// normalizeTarHeader strips the metadata which depends on the environment the tarball
+// is created in. Files are owned by root and only keep whether they are executable.
+func normalizeTarHeader(header *tar.Header, sourceDateEpoch time.Time) {
+	header.ModTime = sourceDateEpoch.UTC()
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+
+	header.Uid = 0
+	header.Gid = 0
+	header.Uname = "root"
+	header.Gname = "root"
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		header.Mode = 0o755
+	case tar.TypeSymlink:
+		header.Mode = 0o777
+	default:
+		if header.Mode&0o111 != 0 {
+			header.Mode = 0o755
+		} else {
+			header.Mode = 0o644
+		}
+	}
+}
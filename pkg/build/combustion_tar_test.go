@@ -0,0 +1,143 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func setupTarballContext(t *testing.T, modTime time.Time, scriptMode os.FileMode) *image.Context {
+	buildDir := t.TempDir()
+
+	combustionDir := filepath.Join(buildDir, "combustion")
+	artefactsDir := filepath.Join(buildDir, "artefacts")
+	require.NoError(t, os.MkdirAll(filepath.Join(artefactsDir, "rpms"), 0o700))
+	require.NoError(t, os.MkdirAll(combustionDir, 0o700))
+
+	require.NoError(t, os.WriteFile(filepath.Join(combustionDir, "script"), []byte("#!/bin/bash"), scriptMode))
+	require.NoError(t, os.WriteFile(filepath.Join(combustionDir, "b.sh"), []byte("echo b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(artefactsDir, "rpms", "a.rpm"), []byte("rpm"), 0o664))
+	require.NoError(t, os.Symlink("a.rpm", filepath.Join(artefactsDir, "rpms", "latest.rpm")))
+
+	for _, path := range []string{
+		filepath.Join(combustionDir, "script"),
+		filepath.Join(combustionDir, "b.sh"),
+		filepath.Join(artefactsDir, "rpms", "a.rpm"),
+		filepath.Join(artefactsDir, "rpms"),
+		combustionDir,
+		artefactsDir,
+	} {
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	return &image.Context{
+		BuildDir:      buildDir,
+		CombustionDir: combustionDir,
+		ArtefactsDir:  artefactsDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeTar,
+				OutputImageName: "combustion.tar.gz",
+			},
+		},
+		ImageConfigDir: buildDir,
+	}
+}
+
+func readTarHeaders(t *testing.T, path string) []*tar.Header {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	assert.Empty(t, gr.Name)
+	assert.True(t, gr.ModTime.IsZero())
+
+	var headers []*tar.Header
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+func TestCreateCompressedTarball_Reproducible(t *testing.T) {
+	sourceDateEpoch := time.Unix(1700000000, 0).UTC()
+
+	first := setupTarballContext(t, time.Unix(1000, 0), 0o744)
+	first.SourceDateEpoch = &sourceDateEpoch
+
+	second := setupTarballContext(t, time.Unix(2000, 0), 0o700)
+	second.SourceDateEpoch = &sourceDateEpoch
+
+	require.NoError(t, NewGenerator(first, nil).createCompressedTarball())
+	require.NoError(t, NewGenerator(second, nil).createCompressedTarball())
+
+	firstContents, err := os.ReadFile(first.OutputPath())
+	require.NoError(t, err)
+
+	secondContents, err := os.ReadFile(second.OutputPath())
+	require.NoError(t, err)
+
+	assert.Equal(t, firstContents, secondContents)
+
+	type entry struct {
+		name     string
+		mode     int64
+		linkname string
+	}
+
+	var entries []entry
+	for _, header := range readTarHeaders(t, first.OutputPath()) {
+		entries = append(entries, entry{name: header.Name, mode: header.Mode, linkname: header.Linkname})
+
+		assert.Equal(t, sourceDateEpoch, header.ModTime.UTC())
+		assert.Equal(t, 0, header.Uid)
+		assert.Equal(t, 0, header.Gid)
+		assert.Equal(t, "root", header.Uname)
+		assert.Equal(t, "root", header.Gname)
+	}
+
+	assert.Equal(t, []entry{
+		{name: "combustion", mode: 0o755},
+		{name: "combustion/b.sh", mode: 0o644},
+		{name: "combustion/script", mode: 0o755},
+		{name: "artefacts", mode: 0o755},
+		{name: "artefacts/rpms", mode: 0o755},
+		{name: "artefacts/rpms/a.rpm", mode: 0o644},
+		{name: "artefacts/rpms/latest.rpm", mode: 0o777, linkname: "a.rpm"},
+	}, entries)
+}
+
+func TestCreateCompressedTarball_PreservesMetadata(t *testing.T) {
+	modTime := time.Unix(1000, 0).UTC()
+	ctx := setupTarballContext(t, modTime, 0o744)
+
+	require.NoError(t, NewGenerator(ctx, nil).createCompressedTarball())
+
+	headers := readTarHeaders(t, ctx.OutputPath())
+	require.Len(t, headers, 7)
+
+	assert.Equal(t, "combustion/script", headers[2].Name)
+	assert.Equal(t, int64(0o744), headers[2].Mode)
+	assert.Equal(t, modTime, headers[2].ModTime.UTC())
+	assert.Equal(t, os.Getuid(), headers[2].Uid)
+}
@@ -2,6 +2,8 @@ package build
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
@@ -20,6 +22,8 @@ func NewGenerator(ctx *image.Context, imageConfigurator imageConfigurator) *Gene
 }
 
 func (g *Generator) Generate() error {
+	startedOn := time.Now()
+
 	log.Audit("Generating combustion customization components...")
 
 	if err := g.imageConfigurator.Configure(g.context); err != nil {
@@ -45,7 +49,27 @@ func (g *Generator) Generate() error {
 			image.TypeISO, image.TypeTar)
 	}
 
-	log.Auditf("Config drive generation complete, it can be found at: %s",
-		g.context.ImageDefinition.Image.OutputImageName)
+	outputPath, err := finalizeOutput(g.context)
+	if err != nil {
+		log.Audit("Error finalizing the config drive.")
+		return err
+	}
+
+	if err = signOutput(g.context, outputPath); err != nil {
+		log.Audit("Error signing the config drive.")
+		return err
+	}
+
+	if err = writeSBOM(g.context); err != nil {
+		log.Audit("Error writing the SBOM.")
+		return err
+	}
+
+	if err = writeProvenance(g.context, outputPath, startedOn); err != nil {
+		log.Audit("Error writing the provenance attestation.")
+		return err
+	}
+
+	log.Auditf("Config drive generation complete, it can be found at: %s", filepath.Base(outputPath))
 	return nil
 }
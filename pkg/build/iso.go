@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -29,10 +31,34 @@ var extractIsoTemplate string
 var rebuildIsoTemplate string
 
 func (b *Builder) buildIsoImage() error {
+	if b.context.Checkpoints.Completed(checkpoint.StageImageBuilt) {
+		zap.S().Info("Skipping ISO image build, the image was rebuilt before the build was interrupted")
+		return nil
+	}
+
 	if err := deleteFile(b.context.OutputPath()); err != nil {
 		return fmt.Errorf("deleting existing ISO image: %w", err)
 	}
 
+	if err := b.modifyExtractedRawImage(); err != nil {
+		return err
+	}
+
+	if err := b.rebuildIso(); err != nil {
+		return fmt.Errorf("building the ISO image: %w", err)
+	}
+
+	return nil
+}
+
+// modifyExtractedRawImage extracts the ISO and modifies the RAW image inside of it, unless this
+// was completed before the build was interrupted.
+func (b *Builder) modifyExtractedRawImage() error {
+	if b.context.Checkpoints.Completed(checkpoint.StageRawModified) {
+		zap.S().Info("Skipping RAW image modification, the image was modified before the build was interrupted")
+		return nil
+	}
+
 	if err := b.extractIso(); err != nil {
 		return fmt.Errorf("extracting the ISO image: %w", err)
 	}
@@ -41,15 +67,12 @@ func (b *Builder) buildIsoImage() error {
 	if err != nil {
 		return fmt.Errorf("unable to find extracted raw image: %w", err)
 	}
-	if err = b.modifyRawImage(extractedRawImage, false, false); err != nil {
-		return fmt.Errorf("modifying the raw image inside of the ISO: %w", err)
-	}
 
-	if err := b.rebuildIso(); err != nil {
-		return fmt.Errorf("building the ISO image: %w", err)
+	if err = b.modifyRawImage(extractedRawImage, imageFormatRaw, false, false); err != nil {
+		return fmt.Errorf("modifying the raw image inside of the ISO: %w", err)
 	}
 
-	return nil
+	return b.checkpoint(checkpoint.StageRawModified)
 }
 
 func (b *Builder) extractIso() error {
@@ -141,7 +164,7 @@ func (b *Builder) createIsoCommand(logFilename, scriptName string) (*exec.Cmd, *
 	}
 
 	scriptFilename := filepath.Join(b.context.BuildDir, scriptName)
-	cmd := exec.Command(scriptFilename)
+	cmd := process.Command(b.context.BuildContext(), scriptFilename)
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
@@ -0,0 +1,46 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+)
+
+// finalizeOutput compresses the output if requested and writes its checksum and metadata sidecars.
+// The path to the final output is returned.
+func finalizeOutput(ctx *image.Context) (string, error) {
+	outputPath := ctx.OutputPath()
+	compression := ctx.ImageDefinition.Image.Compression
+
+	switch {
+	case compression.Type != "" && ctx.Checkpoints.Completed(checkpoint.StageOutputCompressed):
+		// The uncompressed output is removed once compressed
+		outputPath += output.Extension(compression.Type)
+	case compression.Type != "":
+		log.Auditf("Compressing output using %s...", compression.Type)
+
+		if err := deleteFile(outputPath + output.Extension(compression.Type)); err != nil {
+			return "", fmt.Errorf("deleting existing compressed output: %w", err)
+		}
+
+		compressedPath, err := output.Compress(ctx.BuildContext(), outputPath, compression)
+		if err != nil {
+			return "", fmt.Errorf("compressing output: %w", err)
+		}
+
+		outputPath = compressedPath
+
+		if err = ctx.Checkpoints.Complete(checkpoint.StageOutputCompressed); err != nil {
+			return "", fmt.Errorf("recording %s checkpoint: %w", checkpoint.StageOutputCompressed, err)
+		}
+	}
+
+	if _, err := output.WriteSidecars(ctx, outputPath); err != nil {
+		return "", fmt.Errorf("writing output sidecars: %w", err)
+	}
+
+	return outputPath, nil
+}
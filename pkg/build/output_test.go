@@ -0,0 +1,81 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestFinalizeOutput(t *testing.T) {
+	tests := map[string]struct {
+		compression  image.Compression
+		expectedName string
+	}{
+		"Uncompressed": {
+			expectedName: "image.raw",
+		},
+		"Compressed": {
+			compression:  image.Compression{Type: image.CompressionGzip},
+			expectedName: "image.raw.gz",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.DefinitionFile = filepath.Join(ctx.ImageConfigDir, "definition.yaml")
+			require.NoError(t, os.WriteFile(ctx.DefinitionFile, []byte("apiVersion: 1.4"), 0o600))
+
+			ctx.ImageDefinition.Image = image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeX86,
+				OutputImageName: "image.raw",
+				Compression:     test.compression,
+			}
+			require.NoError(t, os.WriteFile(ctx.OutputPath(), []byte("image"), 0o600))
+
+			outputPath, err := finalizeOutput(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, filepath.Join(ctx.ImageConfigDir, test.expectedName), outputPath)
+			assert.FileExists(t, outputPath)
+			assert.FileExists(t, outputPath+".sha256")
+			assert.FileExists(t, outputPath+".metadata.json")
+		})
+	}
+}
+
+func TestFinalizeOutput_Resumed(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.DefinitionFile = filepath.Join(ctx.ImageConfigDir, "definition.yaml")
+	require.NoError(t, os.WriteFile(ctx.DefinitionFile, []byte("apiVersion: 1.4"), 0o600))
+
+	ctx.Checkpoints = checkpoint.New(ctx.BuildDir, "hash")
+	ctx.ImageDefinition.Image = image.Image{
+		ImageType:       image.TypeRAW,
+		Arch:            image.ArchTypeX86,
+		OutputImageName: "image.raw",
+		Compression:     image.Compression{Type: image.CompressionGzip},
+	}
+	require.NoError(t, os.WriteFile(ctx.OutputPath(), []byte("image"), 0o600))
+
+	outputPath, err := finalizeOutput(ctx)
+	require.NoError(t, err)
+	assert.True(t, ctx.Checkpoints.Completed(checkpoint.StageOutputCompressed))
+	assert.NoFileExists(t, ctx.OutputPath())
+
+	// Finalizing the output again, e.g. after signing it failed, reuses the compressed output
+	resumedPath, err := finalizeOutput(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, outputPath, resumedPath)
+	assert.FileExists(t, resumedPath+".sha256")
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/provenance"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
+)
+
+// writeProvenance writes the in-toto provenance attestation of the output next to it.
+// The attestation is signed if a provenance key is configured.
+func writeProvenance(ctx *image.Context, outputPath string, startedOn time.Time) error {
+	statement, err := provenance.NewStatement(ctx, outputPath, startedOn, time.Now())
+	if err != nil {
+		return fmt.Errorf("assembling provenance: %w", err)
+	}
+
+	var key crypto.Signer
+	if ctx.ProvenanceKey != "" {
+		if key, err = signing.LoadPrivateKey(ctx.ProvenanceKey); err != nil {
+			return fmt.Errorf("loading provenance key: %w", err)
+		}
+	}
+
+	envelope, err := provenance.NewEnvelope(statement, key)
+	if err != nil {
+		return fmt.Errorf("creating provenance envelope: %w", err)
+	}
+
+	if err = envelope.Write(outputPath + provenance.Extension); err != nil {
+		return fmt.Errorf("writing provenance: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package build
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/provenance"
+)
+
+func TestWriteProvenance(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+
+	ctx.ProvenanceKey = filepath.Join(ctx.BuildDir, "provenance.pem")
+	require.NoError(t, os.WriteFile(ctx.ProvenanceKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	ctx.DefinitionFile = filepath.Join(ctx.ImageConfigDir, "definition.yaml")
+	require.NoError(t, os.WriteFile(ctx.DefinitionFile, []byte("apiVersion: 1.4"), 0o600))
+
+	ctx.ImageDefinition.Image = image.Image{
+		ImageType:       image.TypeRAW,
+		OutputImageName: "image.raw",
+	}
+	require.NoError(t, os.WriteFile(ctx.OutputPath(), []byte("image"), 0o600))
+
+	require.NoError(t, writeProvenance(ctx, ctx.OutputPath(), time.Now()))
+
+	data, err := os.ReadFile(ctx.OutputPath() + provenance.Extension)
+	require.NoError(t, err)
+
+	var envelope provenance.Envelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+
+	statement, err := envelope.Verify(public)
+	require.NoError(t, err)
+
+	require.Len(t, statement.Subject, 1)
+	assert.Equal(t, "image.raw", statement.Subject[0].Name)
+}
@@ -0,0 +1,193 @@
+package build
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	pxeScriptName     = "pxe-build.sh"
+	pxeLogFile        = "pxe-build.log"
+	pxeKernelName     = "linux"
+	pxeInitrdName     = "initrd"
+	pxeConfigImage    = "config.iso"
+	pxeIPXEScriptName = "boot.ipxe"
+	pxeGRUBConfigName = "grub.cfg"
+)
+
+//go:embed templates/pxe/build-pxe.sh.tpl
+var buildPXETemplate string
+
+//go:embed templates/pxe/boot.ipxe.tpl
+var ipxeScriptTemplate string
+
+//go:embed templates/pxe/grub.cfg.tpl
+var pxeGRUBConfigTemplate string
+
+// buildPXEArtefacts extracts the SelfInstall ISO and writes the installer kernel and initrd, the modified
+// RAW image, the combustion config drive and the iPXE and GRUB network boot configurations into the
+// output directory.
+func (b *Builder) buildPXEArtefacts() error {
+	if b.context.Checkpoints.Completed(checkpoint.StageImageBuilt) {
+		zap.S().Info("Skipping PXE artefacts build, the artefacts were built before the build was interrupted")
+		return nil
+	}
+
+	outputDir := b.context.OutputPath()
+
+	if err := os.RemoveAll(outputDir); err != nil {
+		return fmt.Errorf("deleting existing PXE artefacts: %w", err)
+	}
+
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating PXE output directory: %w", err)
+	}
+
+	if err := b.modifyExtractedRawImage(); err != nil {
+		return err
+	}
+
+	extractedRawImage, err := b.findExtractedRawImage()
+	if err != nil {
+		return fmt.Errorf("unable to find extracted raw image: %w", err)
+	}
+
+	rootfsImage := filepath.Base(extractedRawImage) + ".xz"
+
+	if err = b.writePXEScript(rootfsImage); err != nil {
+		return fmt.Errorf("creating the PXE build script: %w", err)
+	}
+
+	cmd, logFile, err := b.createIsoCommand(pxeLogFile, pxeScriptName)
+	if err != nil {
+		return fmt.Errorf("preparing to build the PXE artefacts: %w", err)
+	}
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("failed to close PXE build log file properly: %s", err)
+		}
+	}()
+
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("building the PXE artefacts: %w", err)
+	}
+
+	if err = createCombustionISO(b.context, filepath.Join(outputDir, pxeConfigImage)); err != nil {
+		return fmt.Errorf("building the config image: %w", err)
+	}
+
+	if err = b.writeNetbootConfigs(rootfsImage); err != nil {
+		return fmt.Errorf("writing network boot configurations: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Builder) writePXEScript(rootfsImage string) error {
+	values := struct {
+		IsoExtractDir string
+		RawExtractDir string
+		OutputDir     string
+		RootfsImage   string
+		KernelName    string
+		InitrdName    string
+	}{
+		IsoExtractDir: filepath.Join(b.context.BuildDir, isoExtractDir),
+		RawExtractDir: filepath.Join(b.context.BuildDir, rawExtractDir),
+		OutputDir:     b.context.OutputPath(),
+		RootfsImage:   rootfsImage,
+		KernelName:    pxeKernelName,
+		InitrdName:    pxeInitrdName,
+	}
+
+	contents, err := template.Parse(pxeScriptName, buildPXETemplate, &values)
+	if err != nil {
+		return fmt.Errorf("parsing %s template: %w", pxeScriptName, err)
+	}
+
+	if err = os.WriteFile(b.generateBuildDirFilename(pxeScriptName), []byte(contents), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing PXE build script %s: %w", pxeScriptName, err)
+	}
+
+	return nil
+}
+
+func (b *Builder) writeNetbootConfigs(rootfsImage string) error {
+	directory := b.context.ImageDefinition.Image.OutputImageName
+
+	configs := []struct {
+		name           string
+		contents       string
+		rootfsImageURL string
+	}{
+		{
+			name:           pxeIPXEScriptName,
+			contents:       ipxeScriptTemplate,
+			rootfsImageURL: fmt.Sprintf("${base-url}/%s", rootfsImage),
+		},
+		{
+			name:           pxeGRUBConfigName,
+			contents:       pxeGRUBConfigTemplate,
+			rootfsImageURL: fmt.Sprintf("tftp://${net_default_server}${pxe_dir}/%s", rootfsImage),
+		},
+	}
+
+	for _, config := range configs {
+		values := struct {
+			Name       string
+			Directory  string
+			KernelName string
+			InitrdName string
+			KernelArgs string
+		}{
+			Name:       strings.TrimSuffix(directory, filepath.Ext(directory)),
+			Directory:  directory,
+			KernelName: pxeKernelName,
+			InitrdName: pxeInitrdName,
+			KernelArgs: b.pxeKernelArgs(config.rootfsImageURL),
+		}
+
+		data, err := template.Parse(config.name, config.contents, &values)
+		if err != nil {
+			return fmt.Errorf("parsing %s template: %w", config.name, err)
+		}
+
+		filename := filepath.Join(b.context.OutputPath(), config.name)
+		if err = os.WriteFile(filename, []byte(data), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("writing %s: %w", config.name, err)
+		}
+	}
+
+	return nil
+}
+
+// pxeKernelArgs assembles the kernel command line instructing the kiwi installer to deploy the
+// RAW image fetched from the given URL. The kernel args from the definition are passed on to the
+// first boot of the installed system.
+func (b *Builder) pxeKernelArgs(rootfsImageURL string) string {
+	args := []string{
+		"rd.neednet=1",
+		"ip=dhcp",
+		"rd.kiwi.install.pxe",
+		fmt.Sprintf("rd.kiwi.install.image=%s", rootfsImageURL),
+	}
+
+	if installDevice := b.context.ImageDefinition.OperatingSystem.IsoConfiguration.InstallDevice; installDevice != "" {
+		args = append(args, fmt.Sprintf("rd.kiwi.oem.installdevice=%s", installDevice))
+	}
+
+	if kernelArgs := b.context.ImageDefinition.OperatingSystem.KernelArgs; len(kernelArgs) != 0 {
+		args = append(args, "rd.kiwi.install.pass.bootparam")
+		args = append(args, kernelArgs...)
+	}
+
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,124 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWritePXEScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	builder := Builder{context: ctx}
+
+	ctx.ImageDefinition.Image.OutputImageName = "eib-pxe"
+
+	// Test
+	err := builder.writePXEScript("SL-Micro.x86_64-6.0.raw.xz")
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.BuildDir, pxeScriptName))
+	require.NoError(t, err)
+	found := string(foundBytes)
+
+	assert.Contains(t, found, fmt.Sprintf("ISO_EXTRACT_DIR=%s", filepath.Join(ctx.BuildDir, isoExtractDir)))
+	assert.Contains(t, found, fmt.Sprintf("RAW_EXTRACT_DIR=%s", filepath.Join(ctx.BuildDir, rawExtractDir)))
+	assert.Contains(t, found, fmt.Sprintf("OUTPUT_DIR=%s", filepath.Join(ctx.ImageConfigDir, "eib-pxe")))
+	assert.Contains(t, found, "ROOTFS_IMAGE=${OUTPUT_DIR}/SL-Micro.x86_64-6.0.raw.xz")
+	assert.Contains(t, found, "cp ${KERNEL_FILE} ${OUTPUT_DIR}/linux")
+	assert.Contains(t, found, "cp ${INITRD_FILE} ${OUTPUT_DIR}/initrd")
+}
+
+func TestWriteNetbootConfigs(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	builder := Builder{context: ctx}
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			ImageType:       image.TypePXE,
+			OutputImageName: "eib-pxe",
+		},
+		OperatingSystem: image.OperatingSystem{
+			KernelArgs: []string{"alpha", "beta=1"},
+			IsoConfiguration: image.IsoConfiguration{
+				InstallDevice: "/dev/vda",
+			},
+		},
+	}
+
+	outputDir := filepath.Join(ctx.ImageConfigDir, "eib-pxe")
+	require.NoError(t, os.MkdirAll(outputDir, os.ModePerm))
+
+	// Test
+	err := builder.writeNetbootConfigs("image.raw.xz")
+
+	// Verify
+	require.NoError(t, err)
+
+	ipxeScript, err := os.ReadFile(filepath.Join(outputDir, pxeIPXEScriptName))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(ipxeScript), "#!ipxe")
+	assert.Contains(t, string(ipxeScript), "kernel ${base-url}/linux initrd=initrd rd.neednet=1 ip=dhcp rd.kiwi.install.pxe "+
+		"rd.kiwi.install.image=${base-url}/image.raw.xz rd.kiwi.oem.installdevice=/dev/vda rd.kiwi.install.pass.bootparam alpha beta=1")
+	assert.Contains(t, string(ipxeScript), "initrd ${base-url}/initrd")
+
+	grubConfig, err := os.ReadFile(filepath.Join(outputDir, pxeGRUBConfigName))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(grubConfig), "set pxe_dir=/eib-pxe")
+	assert.Contains(t, string(grubConfig), "menuentry \"Install eib-pxe\"")
+	assert.Contains(t, string(grubConfig), "linux ${pxe_dir}/linux rd.neednet=1 ip=dhcp rd.kiwi.install.pxe "+
+		"rd.kiwi.install.image=tftp://${net_default_server}${pxe_dir}/image.raw.xz rd.kiwi.oem.installdevice=/dev/vda "+
+		"rd.kiwi.install.pass.bootparam alpha beta=1")
+	assert.Contains(t, string(grubConfig), "initrd ${pxe_dir}/initrd")
+}
+
+func TestPXEKernelArgs(t *testing.T) {
+	tests := map[string]struct {
+		operatingSystem image.OperatingSystem
+		expectedArgs    string
+	}{
+		"No Additional Arguments": {
+			expectedArgs: "rd.neednet=1 ip=dhcp rd.kiwi.install.pxe rd.kiwi.install.image=http://server/image.raw.xz",
+		},
+		"Install Device": {
+			operatingSystem: image.OperatingSystem{
+				IsoConfiguration: image.IsoConfiguration{InstallDevice: "/dev/sda"},
+			},
+			expectedArgs: "rd.neednet=1 ip=dhcp rd.kiwi.install.pxe rd.kiwi.install.image=http://server/image.raw.xz " +
+				"rd.kiwi.oem.installdevice=/dev/sda",
+		},
+		"Kernel Arguments": {
+			operatingSystem: image.OperatingSystem{
+				KernelArgs: []string{"console=ttyS0"},
+			},
+			expectedArgs: "rd.neednet=1 ip=dhcp rd.kiwi.install.pxe rd.kiwi.install.image=http://server/image.raw.xz " +
+				"rd.kiwi.install.pass.bootparam console=ttyS0",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				context: &image.Context{
+					ImageDefinition: &image.Definition{
+						OperatingSystem: test.operatingSystem,
+					},
+				},
+			}
+
+			assert.Equal(t, test.expectedArgs, builder.pxeKernelArgs("http://server/image.raw.xz"))
+		})
+	}
+}
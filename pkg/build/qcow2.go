@@ -0,0 +1,106 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
+	"go.uber.org/zap"
+)
+
+const (
+	qemuImgExec          = "/usr/bin/qemu-img"
+	qcow2SourceImageName = "qcow2-source.qcow2"
+	qcow2ConvertLogFile  = "qcow2-convert.log"
+)
+
+// buildQCOW2Image converts the RAW base image into a qcow2 image in the build directory, which is
+// resized and modified in place, and then compresses it into the output image. The intermediate
+// image only allocates the space which is written to, rather than the full disk size.
+func (b *Builder) buildQCOW2Image() error {
+	if err := b.checkRawDiskSpace(); err != nil {
+		return err
+	}
+
+	// The disk space is checked regardless, as it determines the disk size recorded in the output metadata
+	if b.context.Checkpoints.Completed(checkpoint.StageImageBuilt) {
+		zap.S().Info("Skipping qcow2 image build, the image was built before the build was interrupted")
+		return nil
+	}
+
+	if err := deleteFile(b.context.OutputPath()); err != nil {
+		return fmt.Errorf("deleting existing qcow2 image: %w", err)
+	}
+
+	sourceImagePath := b.generateBuildDirFilename(qcow2SourceImageName)
+
+	if b.context.Checkpoints.Completed(checkpoint.StageRawModified) {
+		zap.S().Info("Skipping RAW image modification, the image was modified before the build was interrupted")
+	} else {
+		if err := b.convertToQCOW2(b.generateBaseImageFilename(), imageFormatRaw, sourceImagePath, false); err != nil {
+			return fmt.Errorf("converting the base image %s to qcow2: %w", b.context.ImageDefinition.Image.BaseImage, err)
+		}
+
+		if err := b.modifyRawImage(sourceImagePath, imageFormatQCOW2, true, true); err != nil {
+			return fmt.Errorf("modifying the raw image: %w", err)
+		}
+
+		if err := b.checkpoint(checkpoint.StageRawModified); err != nil {
+			return err
+		}
+	}
+
+	if err := b.convertToQCOW2(sourceImagePath, imageFormatQCOW2, b.context.OutputPath(), true); err != nil {
+		return fmt.Errorf("compressing the qcow2 image: %w", err)
+	}
+
+	// The intermediate image is only kept if the compression fails, so that the build can be resumed from it
+	if err := deleteFile(sourceImagePath); err != nil {
+		zap.S().Warnf("Failed to remove intermediate qcow2 image: %s", err)
+	}
+
+	return nil
+}
+
+// convertToQCOW2 converts the source image of the given format into a qcow2 image, which is optionally compressed.
+func (b *Builder) convertToQCOW2(sourceImagePath, sourceFormat, outputImagePath string, compress bool) error {
+	logFile, err := os.OpenFile(b.generateBuildDirFilename(qcow2ConvertLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileio.NonExecutablePerms)
+	if err != nil {
+		return fmt.Errorf("creating log file: %w", err)
+	}
+
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("Failed to close qcow2 conversion log file properly: %s", err)
+		}
+	}()
+
+	cmd := b.createQCOW2ConvertCommand(sourceImagePath, sourceFormat, outputImagePath, compress, logFile)
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("running qemu-img: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Builder) createQCOW2ConvertCommand(sourceImagePath, sourceFormat, outputImagePath string, compress bool, writer io.Writer) *exec.Cmd {
+	args := []string{"convert"}
+	if compress {
+		args = append(args, "-c")
+	}
+	args = append(args,
+		"-f", sourceFormat,
+		"-O", imageFormatQCOW2,
+		sourceImagePath,
+		outputImagePath)
+
+	cmd := process.Command(b.context.BuildContext(), qemuImgExec, args...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	return cmd
+}
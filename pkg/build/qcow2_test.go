@@ -0,0 +1,81 @@
+package build
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestCreateQCOW2ConvertCommand(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			ImageConfigDir: "config-dir",
+			BuildDir:       "build-dir",
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					BaseImage:       "base-image.raw",
+					OutputImageName: "build-image.qcow2",
+				},
+			},
+		},
+	}
+	sourceImagePath := builder.generateBuildDirFilename(qcow2SourceImageName)
+
+	tests := map[string]struct {
+		sourceImagePath string
+		sourceFormat    string
+		outputImagePath string
+		compress        bool
+		expectedArgs    []string
+	}{
+		`base image conversion`: {
+			sourceImagePath: builder.generateBaseImageFilename(),
+			sourceFormat:    imageFormatRaw,
+			outputImagePath: sourceImagePath,
+			expectedArgs: []string{
+				qemuImgExec,
+				"convert",
+				"-f", "raw",
+				"-O", "qcow2",
+				"config-dir/base-images/base-image.raw",
+				"build-dir/qcow2-source.qcow2",
+			},
+		},
+		`output compression`: {
+			sourceImagePath: sourceImagePath,
+			sourceFormat:    imageFormatQCOW2,
+			outputImagePath: builder.context.OutputPath(),
+			compress:        true,
+			expectedArgs: []string{
+				qemuImgExec,
+				"convert",
+				"-c",
+				"-f", "qcow2",
+				"-O", "qcow2",
+				"build-dir/qcow2-source.qcow2",
+				"config-dir/build-image.qcow2",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var writer bytes.Buffer
+
+			// Test
+			cmd := builder.createQCOW2ConvertCommand(test.sourceImagePath, test.sourceFormat, test.outputImagePath, test.compress, &writer)
+
+			// Verify
+			require.NotNil(t, cmd)
+
+			assert.Equal(t, qemuImgExec, cmd.Path)
+			assert.Equal(t, test.expectedArgs, cmd.Args)
+			assert.Equal(t, &writer, cmd.Stdout)
+			assert.Equal(t, &writer, cmd.Stderr)
+		})
+	}
+}
@@ -2,13 +2,19 @@ package build
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -18,12 +24,48 @@ const (
 	modifyScriptName        = "modify-raw-image.sh"
 	rawBuildLogFile         = "raw-build.log"
 	availableRawDiskSpaceMB = 150
+	// Compressed artefacts such as container images and release tarballs are extracted on the node,
+	// taking up roughly this many times their size in addition to the archives themselves
+	compressedArtefactExpansion = 3
+
+	imageFormatRaw   = "raw"
+	imageFormatQCOW2 = "qcow2"
 )
 
+var compressedArtefactExtensions = []string{".gz", ".tgz", ".zst", ".xz", ".bz2"}
+
 //go:embed templates/modify-raw-image.sh.tpl
 var modifyRawImageTemplate string
 
 func (b *Builder) buildRawImage() error {
+	if err := b.checkRawDiskSpace(); err != nil {
+		return err
+	}
+
+	// The disk space is checked regardless, as it determines the disk size recorded in the output metadata
+	if b.context.Checkpoints.Completed(checkpoint.StageRawModified) {
+		zap.S().Info("Skipping RAW image modification, the image was modified before the build was interrupted")
+		return nil
+	}
+
+	if err := deleteFile(b.context.OutputPath()); err != nil {
+		return fmt.Errorf("deleting existing RAW image: %w", err)
+	}
+
+	cmd := b.createRawImageCopyCommand(b.context.OutputPath())
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("copying the base image %s to the output image location %s: %w",
+			b.context.ImageDefinition.Image.BaseImage, b.context.OutputPath(), err)
+	}
+
+	if err := b.modifyRawImage(b.context.OutputPath(), imageFormatRaw, true, true); err != nil {
+		return err
+	}
+
+	return b.checkpoint(checkpoint.StageRawModified)
+}
+
+func (b *Builder) checkRawDiskSpace() error {
 	requiredSpace, err := b.calculateMinimumRequiredSpace()
 	if err != nil {
 		return fmt.Errorf("calculating minimum required space: %w", err)
@@ -34,7 +76,27 @@ func (b *Builder) buildRawImage() error {
 		return fmt.Errorf("retrieving RAW base image size: %w", err)
 	}
 
-	diskSize := b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize.ToMB()
+	rawConfig := &b.context.ImageDefinition.OperatingSystem.RawConfiguration
+	if rawConfig.DiskSize.IsAuto() {
+		compressedSize, err := compressedFilesSize(b.context.ArtefactsDir)
+		if err != nil {
+			return fmt.Errorf("calculating compressed artefacts size: %w", err)
+		}
+
+		headroom := image.DefaultDiskSizeHeadroom
+		if rawConfig.DiskSizeHeadroom != nil {
+			headroom = *rawConfig.DiskSizeHeadroom
+		}
+
+		rawConfig.DiskSize = calculateDiskSize(imageSize, requiredSpace, compressedSize, headroom, rawConfig.MinimumDiskSize)
+		b.calculatedDiskSize = rawConfig.DiskSize
+
+		zap.S().Infof("Calculated disk size %s from base image size %d MB, required space %d MB and compressed artefacts size %d MB",
+			rawConfig.DiskSize, imageSize, requiredSpace, compressedSize)
+		return nil
+	}
+
+	diskSize := rawConfig.DiskSize.ToMB()
 	if diskSize <= imageSize+requiredSpace && requiredSpace >= availableRawDiskSpaceMB {
 		zap.S().Warnf("Insufficient available disk space. The build artifacts require an expansion of the base image by least %d MB. "+
 			"Please specify an appropriate disk size taking into consideration that some of the artifacts may be compressed.",
@@ -42,21 +104,13 @@ func (b *Builder) buildRawImage() error {
 		return fmt.Errorf("insufficient available disk space on the RAW image")
 	}
 
-	if err = deleteFile(b.context.OutputPath()); err != nil {
-		return fmt.Errorf("deleting existing RAW image: %w", err)
-	}
-
-	cmd := b.createRawImageCopyCommand()
-	if err = cmd.Run(); err != nil {
-		return fmt.Errorf("copying the base image %s to the output image location %s: %w",
-			b.context.ImageDefinition.Image.BaseImage, b.context.OutputPath(), err)
-	}
-
-	return b.modifyRawImage(b.context.OutputPath(), true, true)
+	return nil
 }
 
-func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFilesystem bool) error {
-	if err := b.writeModifyScript(imagePath, includeCombustion, renameFilesystem); err != nil {
+func (b *Builder) modifyRawImage(imagePath, format string, includeCombustion, renameFilesystem bool) error {
+	defer b.removeLUKSKeyFiles()
+
+	if err := b.writeModifyScript(imagePath, format, includeCombustion, renameFilesystem); err != nil {
 		return fmt.Errorf("writing the image modification script: %w", err)
 	}
 
@@ -81,15 +135,14 @@ func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFile
 	return nil
 }
 
-func (b *Builder) createRawImageCopyCommand() *exec.Cmd {
+func (b *Builder) createRawImageCopyCommand(outputImagePath string) *exec.Cmd {
 	baseImagePath := b.generateBaseImageFilename()
-	outputImagePath := b.context.OutputPath()
 
-	cmd := exec.Command(copyExec, baseImagePath, outputImagePath)
+	cmd := process.Command(b.context.BuildContext(), copyExec, baseImagePath, outputImagePath)
 	return cmd
 }
 
-func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, renameFilesystem bool) error {
+func (b *Builder) writeModifyScript(imageFilename, format string, includeCombustion, renameFilesystem bool) error {
 	// There is no need to check the returned results from this call. If there is no configuration,
 	// it will be an empty string, which is safe to pass into the template.
 	grubConfiguration, err := b.generateGRUBGuestfishCommands()
@@ -101,9 +154,15 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 
 	luksKey := b.context.ImageDefinition.OperatingSystem.RawConfiguration.LUKSKey
 
+	partitions, err := b.rawPartitions()
+	if err != nil {
+		return fmt.Errorf("preparing the additional partitions: %w", err)
+	}
+
 	// Assemble the template values
 	values := struct {
 		ImagePath                string
+		Format                   string
 		CombustionDir            string
 		ArtefactsDir             string
 		ConfigureGRUB            string
@@ -113,8 +172,11 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		Arch                     string
 		LUKSKey                  string
 		ExpandEncryptedPartition bool
+		RootSize                 string
+		Partitions               []rawPartition
 	}{
 		ImagePath:                imageFilename,
+		Format:                   format,
 		CombustionDir:            b.context.CombustionDir,
 		ArtefactsDir:             b.context.ArtefactsDir,
 		ConfigureGRUB:            grubConfiguration,
@@ -124,6 +186,8 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		Arch:                     string(b.context.ImageDefinition.Image.Arch),
 		LUKSKey:                  luksKey,
 		ExpandEncryptedPartition: expandEncryptedPartition,
+		RootSize:                 string(b.context.ImageDefinition.OperatingSystem.RawConfiguration.RootSize),
+		Partitions:               partitions,
 	}
 
 	data, err := template.Parse(modifyScriptName, modifyRawImageTemplate, &values)
@@ -139,10 +203,63 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 	return nil
 }
 
+type rawPartition struct {
+	Label       string
+	Size        string
+	Filesystem  string
+	MountPoint  string
+	LUKSKeyFile string
+}
+
+// rawPartitions assembles the additional partitions to create. The LUKS keys of encrypted partitions are
+// written to files in the build directory so that they are neither part of the script nor its arguments.
+// The files are removed by removeLUKSKeyFiles once the modification script has run.
+func (b *Builder) rawPartitions() ([]rawPartition, error) {
+	var partitions []rawPartition
+
+	for _, p := range b.context.ImageDefinition.OperatingSystem.RawConfiguration.Partitions {
+		partition := rawPartition{
+			Label:      p.Label,
+			Size:       string(p.Size),
+			Filesystem: p.Filesystem,
+			MountPoint: p.MountPoint,
+		}
+
+		if p.LUKSKey != "" {
+			partition.LUKSKeyFile = b.luksKeyFilename(p.Label)
+			if err := os.WriteFile(partition.LUKSKeyFile, []byte(p.LUKSKey), 0o600); err != nil {
+				return nil, fmt.Errorf("writing LUKS key of partition %s: %w", p.Label, err)
+			}
+		}
+
+		partitions = append(partitions, partition)
+	}
+
+	return partitions, nil
+}
+
+func (b *Builder) luksKeyFilename(label string) string {
+	return b.generateBuildDirFilename(fmt.Sprintf("luks-%s.key", label))
+}
+
+// removeLUKSKeyFiles deletes the key files written by rawPartitions so that no plaintext keys
+// are left behind in the build directory.
+func (b *Builder) removeLUKSKeyFiles() {
+	for _, p := range b.context.ImageDefinition.OperatingSystem.RawConfiguration.Partitions {
+		if p.LUKSKey == "" {
+			continue
+		}
+
+		if err := os.Remove(b.luksKeyFilename(p.Label)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			zap.S().Warnf("Failed to remove LUKS key file of partition %s: %s", p.Label, err)
+		}
+	}
+}
+
 func (b *Builder) createModifyCommand(writer io.Writer) *exec.Cmd {
 	scriptPath := filepath.Join(b.context.BuildDir, modifyScriptName)
 
-	cmd := exec.Command(scriptPath)
+	cmd := process.Command(b.context.BuildContext(), scriptPath)
 	cmd.Stdout = writer
 	cmd.Stderr = writer
 
@@ -178,6 +295,41 @@ func (b *Builder) calculateMinimumRequiredSpace() (int64, error) {
 	return requiredSpace, nil
 }
 
+// calculateDiskSize determines the size of a disk holding the base image and all artefacts, including the
+// contents of compressed artefacts once extracted. The headroom percentage is added on top and the result
+// is rounded up to whole gigabytes.
+func calculateDiskSize(imageSize, requiredSpace, compressedSize int64, headroom int, minimum image.DiskSize) image.DiskSize {
+	size := imageSize + requiredSpace + compressedSize*compressedArtefactExpansion
+	size += size * int64(headroom) / 100
+	size = max(size, minimum.ToMB())
+
+	return image.DiskSize(fmt.Sprintf("%dG", (size+1023)/1024))
+}
+
+// Traverse a directory and all of its subdirectories
+// returning the total size of the compressed files in MB.
+func compressedFilesSize(path string) (int64, error) {
+	var size int64
+
+	calculateSize := func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && slices.Contains(compressedArtefactExtensions, filepath.Ext(p)) {
+			size += info.Size()
+		}
+
+		return nil
+	}
+
+	if err := filepath.Walk(path, calculateSize); err != nil {
+		return 0, err
+	}
+
+	return size / (1024 * 1024), nil
+}
+
 // Traverse a directory and all of its subdirectories
 // returning the total size of their contents in MB.
 func dirSize(path string) (int64, error) {
@@ -28,7 +28,7 @@ func TestCreateRawImageCopyCommand(t *testing.T) {
 	}
 
 	// Test
-	cmd := builder.createRawImageCopyCommand()
+	cmd := builder.createRawImageCopyCommand(builder.context.OutputPath())
 
 	// Verify
 	require.NotNil(t, cmd)
@@ -88,8 +88,31 @@ func TestWriteModifyScript(t *testing.T) {
 		},
 	}
 
+	partitionedRaw := image.OperatingSystem{
+		RawConfiguration: image.RawConfiguration{
+			DiskSize: "64G",
+			RootSize: "20G",
+			Partitions: []image.RawPartition{
+				{
+					Label:      "rancher",
+					Size:       "30G",
+					Filesystem: image.FilesystemXFS,
+					MountPoint: "/var/lib/rancher",
+					LUKSKey:    "secret",
+				},
+				{
+					Label:      "data",
+					Filesystem: image.FilesystemExt4,
+					MountPoint: "/data",
+				},
+			},
+		},
+	}
+	rancherKeyFile := filepath.Join(ctx.BuildDir, "luks-rancher.key")
+
 	tests := []struct {
 		name              string
+		format            string
 		includeCombustion bool
 		renameFilesystem  bool
 		operatingSystem   *image.OperatingSystem
@@ -98,6 +121,7 @@ func TestWriteModifyScript(t *testing.T) {
 	}{
 		{
 			name:              "RAW Image Usage",
+			format:            imageFormatRaw,
 			includeCombustion: true,
 			renameFilesystem:  true,
 			operatingSystem:   &raw,
@@ -106,7 +130,7 @@ func TestWriteModifyScript(t *testing.T) {
 				fmt.Sprintf("copy-in %s", builder.context.CombustionDir),
 				"btrfs filesystem label / INSTALL",
 				"truncate -s 64G",
-				"virt-resize --expand $ROOT_PART",
+				"virt-resize --format=raw --output-format=raw --expand $ROOT_PART",
 			},
 			expectedMissing: []string{
 				"btrfs filesystem resize max /",
@@ -114,6 +138,7 @@ func TestWriteModifyScript(t *testing.T) {
 		},
 		{
 			name:              "ISO Image Usage",
+			format:            imageFormatRaw,
 			includeCombustion: false,
 			renameFilesystem:  false,
 			operatingSystem:   &iso,
@@ -128,6 +153,7 @@ func TestWriteModifyScript(t *testing.T) {
 		},
 		{
 			name:              "Encrypted RAW Image Usage",
+			format:            imageFormatRaw,
 			includeCombustion: true,
 			renameFilesystem:  true,
 			operatingSystem:   &encryptedRaw,
@@ -136,7 +162,7 @@ func TestWriteModifyScript(t *testing.T) {
 				fmt.Sprintf("copy-in %s", builder.context.CombustionDir),
 				"btrfs filesystem label / INSTALL",
 				"truncate -s 64G",
-				"virt-resize --expand $ROOT_PART",
+				"virt-resize --format=raw --output-format=raw --expand $ROOT_PART",
 				fmt.Sprintf("LUKSFLAG=\"--key all:key:%s\"", luksKey),
 			},
 			expectedMissing: []string{
@@ -145,6 +171,7 @@ func TestWriteModifyScript(t *testing.T) {
 		},
 		{
 			name:              "Encrypted RAW Image Usage With Expansion",
+			format:            imageFormatRaw,
 			includeCombustion: true,
 			renameFilesystem:  true,
 			operatingSystem:   &encryptedRawExpand,
@@ -153,18 +180,64 @@ func TestWriteModifyScript(t *testing.T) {
 				fmt.Sprintf("copy-in %s", builder.context.CombustionDir),
 				"btrfs filesystem label / INSTALL",
 				"truncate -s 64G",
-				"virt-resize --expand $ROOT_PART",
+				"virt-resize --format=raw --output-format=raw --expand $ROOT_PART",
 				fmt.Sprintf("LUKSFLAG=\"--key all:key:%s\"", luksKey),
 				"btrfs filesystem resize max /",
 			},
 		},
+		{
+			name:              "Partitioned RAW Image Usage",
+			format:            imageFormatRaw,
+			includeCombustion: true,
+			renameFilesystem:  true,
+			operatingSystem:   &partitionedRaw,
+			expectedContains: []string{
+				"truncate -s 64G",
+				"virt-resize --format=raw --output-format=raw --resize $ROOT_PART=20G --no-extra-partition",
+				"PART_END=$(( PART_START * BLOCKSIZE + $(numfmt --from=iec 30G) - 1 ))",
+				"PART_LAST_SECTOR=-$(( 1048576 / BLOCKSIZE ))",
+				`PART_ADD="part-add /dev/sda p $PART_START $PART_LAST_SECTOR : part-set-name /dev/sda $PARTNUM rancher :`,
+				`PART_ADD="part-add /dev/sda p $PART_START $PART_LAST_SECTOR : part-set-name /dev/sda $PARTNUM data :`,
+				fmt.Sprintf("printf '%%s\\n%%s\\n' \"$(cat %[1]s)\" \"$(cat %[1]s)\"", rancherKeyFile),
+				"run : $PART_ADD : luks-format /dev/sda$PARTNUM 0 : luks-open /dev/sda$PARTNUM rancher",
+				"mkfs xfs /dev/mapper/rancher",
+				"run : $PART_ADD : mkfs ext4 /dev/sda$PARTNUM : set-label /dev/sda$PARTNUM data",
+				"mkdir-p /var/lib/rancher",
+				fmt.Sprintf("upload %s /etc/cryptsetup-keys.d/rancher.key", rancherKeyFile),
+				`write-append /etc/crypttab "rancher PARTLABEL=rancher /etc/cryptsetup-keys.d/rancher.key luks\n"`,
+				`write-append /etc/fstab "/dev/mapper/rancher /var/lib/rancher xfs defaults 0 0\n"`,
+				`write-append /etc/fstab "PARTLABEL=data /data ext4 defaults 0 0\n"`,
+			},
+			expectedMissing: []string{
+				"virt-resize --format=raw --output-format=raw --expand $ROOT_PART",
+				"secret",
+			},
+		},
+		{
+			name:              "Partitioned qcow2 Image Usage",
+			format:            imageFormatQCOW2,
+			includeCombustion: true,
+			renameFilesystem:  true,
+			operatingSystem:   &partitionedRaw,
+			expectedContains: []string{
+				fmt.Sprintf("guestfish --blocksize=$BLOCKSIZE --format=qcow2 --rw -a %s $LUKSFLAG", outputImageFilename),
+				fmt.Sprintf("qemu-img create -f qcow2 %[1]s.expanded 64G", outputImageFilename),
+				"virt-resize --format=qcow2 --output-format=qcow2 --resize $ROOT_PART=20G --no-extra-partition",
+				fmt.Sprintf("guestfish --blocksize=$BLOCKSIZE --format=qcow2 --ro -a %s run : part-list /dev/sda", outputImageFilename),
+				fmt.Sprintf("guestfish --blocksize=$BLOCKSIZE --format=qcow2 --rw --keys-from-stdin -a %s", outputImageFilename),
+			},
+			expectedMissing: []string{
+				"truncate",
+				"--format=raw",
+			},
+		},
 	}
 
 	// Test
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			ctx.ImageDefinition.OperatingSystem = *test.operatingSystem
-			err := builder.writeModifyScript(outputImageFilename, test.includeCombustion, test.renameFilesystem)
+			err := builder.writeModifyScript(outputImageFilename, test.format, test.includeCombustion, test.renameFilesystem)
 			require.NoError(t, err)
 
 			expectedFilename := filepath.Join(ctx.BuildDir, modifyScriptName)
@@ -184,6 +257,10 @@ func TestWriteModifyScript(t *testing.T) {
 			}
 		})
 	}
+
+	key, err := os.ReadFile(rancherKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(key))
 }
 
 func TestCreateModifyCommand(t *testing.T) {
@@ -204,3 +281,131 @@ func TestCreateModifyCommand(t *testing.T) {
 	assert.Equal(t, io.Discard, cmd.Stdout)
 	assert.Equal(t, io.Discard, cmd.Stderr)
 }
+
+func TestRemoveLUKSKeyFiles(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			RawConfiguration: image.RawConfiguration{
+				Partitions: []image.RawPartition{
+					{Label: "rancher", LUKSKey: "secret"},
+					{Label: "data"},
+					{Label: "missing", LUKSKey: "secret"},
+				},
+			},
+		},
+	}
+
+	builder := Builder{context: ctx}
+
+	rancherKeyFile := createFile(t, filepath.Join(ctx.BuildDir, "luks-rancher.key"))
+	otherFile := createFile(t, filepath.Join(ctx.BuildDir, "luks-other.key"))
+
+	// Test
+	builder.removeLUKSKeyFiles()
+
+	// Verify
+	assert.NoFileExists(t, rancherKeyFile)
+	assert.FileExists(t, otherFile)
+}
+
+func TestCalculateDiskSize(t *testing.T) {
+	tests := map[string]struct {
+		imageSize        int64
+		requiredSpace    int64
+		compressedSize   int64
+		headroom         int
+		minimum          image.DiskSize
+		expectedDiskSize image.DiskSize
+	}{
+		"Default headroom": {
+			imageSize:        4096,
+			requiredSpace:    1024,
+			headroom:         image.DefaultDiskSizeHeadroom,
+			expectedDiskSize: "6G",
+		},
+		"No headroom": {
+			imageSize:        4096,
+			requiredSpace:    1024,
+			expectedDiskSize: "5G",
+		},
+		"Compressed artefacts": {
+			imageSize:        4096,
+			requiredSpace:    1024,
+			compressedSize:   1024,
+			headroom:         20,
+			expectedDiskSize: "10G",
+		},
+		"Minimum disk size": {
+			imageSize:        4096,
+			requiredSpace:    1024,
+			minimum:          "32G",
+			expectedDiskSize: "32G",
+		},
+		"Minimum disk size in MB": {
+			imageSize:        1024,
+			minimum:          "2500M",
+			expectedDiskSize: "3G",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diskSize := calculateDiskSize(test.imageSize, test.requiredSpace, test.compressedSize, test.headroom, test.minimum)
+			assert.Equal(t, test.expectedDiskSize, diskSize)
+		})
+	}
+}
+
+func TestCheckRawDiskSpace_Auto(t *testing.T) {
+	configDir := t.TempDir()
+	buildDir := t.TempDir()
+
+	combustionDir := filepath.Join(buildDir, "combustion")
+	artefactsDir := filepath.Join(buildDir, "artefacts")
+	require.NoError(t, os.MkdirAll(combustionDir, 0o700))
+	require.NoError(t, os.MkdirAll(artefactsDir, 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o700))
+
+	require.NoError(t, os.Truncate(createFile(t, filepath.Join(configDir, "base-images", "base.raw")), 3072*1024*1024))
+	require.NoError(t, os.Truncate(createFile(t, filepath.Join(artefactsDir, "images.tar.zst")), 512*1024*1024))
+
+	headroom := 50
+	builder := Builder{
+		context: &image.Context{
+			ImageConfigDir: configDir,
+			BuildDir:       buildDir,
+			CombustionDir:  combustionDir,
+			ArtefactsDir:   artefactsDir,
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+					BaseImage: "base.raw",
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize:         image.DiskSizeAuto,
+						DiskSizeHeadroom: &headroom,
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, builder.checkRawDiskSpace())
+
+	// (3072 + 512 + 3 * 512) * 1.5 = 7680 MB
+	assert.Equal(t, image.DiskSize("8G"), builder.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize)
+	assert.Equal(t, image.DiskSize("8G"), builder.calculatedDiskSize)
+}
+
+func createFile(t *testing.T, path string) string {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	return path
+}
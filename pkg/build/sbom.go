@@ -0,0 +1,44 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+// writeSBOM writes the SPDX and CycloneDX documents listing the components collected while
+// configuring the image next to the output. Images also list the base image they are built from.
+func writeSBOM(ctx *image.Context) error {
+	if !ctx.IsConfigDrive {
+		baseImage := ctx.ImageDefinition.Image.BaseImage
+
+		checksum, err := output.FileSHA256(filepath.Join(ctx.ImageConfigDir, "base-images", baseImage))
+		if err != nil {
+			return fmt.Errorf("calculating base image checksum: %w", err)
+		}
+
+		ctx.SBOM.Add(sbom.Component{
+			Type:   sbom.TypeBaseImage,
+			Name:   baseImage,
+			SHA256: checksum,
+		})
+	}
+
+	doc := &sbom.Document{
+		Name:        ctx.ImageDefinition.Image.OutputImageName,
+		ToolVersion: version.GetEibVersion(),
+		Created:     time.Now(),
+		Components:  ctx.SBOM.Components(),
+	}
+
+	if err := sbom.Write(ctx.OutputPath(), doc); err != nil {
+		return fmt.Errorf("writing SBOM: %w", err)
+	}
+
+	return nil
+}
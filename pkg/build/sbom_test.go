@@ -0,0 +1,70 @@
+package build
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+)
+
+func TestWriteSBOM(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image = image.Image{
+		ImageType:       image.TypeRAW,
+		BaseImage:       "base.raw",
+		OutputImageName: "image.raw",
+	}
+
+	baseImagesDir := filepath.Join(ctx.ImageConfigDir, "base-images")
+	require.NoError(t, os.MkdirAll(baseImagesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "base.raw"), []byte("image"), 0o600))
+
+	ctx.SBOM.Add(sbom.Component{Type: sbom.TypePackage, Name: "git", Version: "2.43.0-1.1", Arch: "x86_64"})
+
+	require.NoError(t, writeSBOM(ctx))
+
+	data, err := os.ReadFile(ctx.OutputPath() + sbom.SPDXExtension)
+	require.NoError(t, err)
+
+	var document struct {
+		Name     string `json:"name"`
+		Packages []struct {
+			Name      string `json:"name"`
+			Checksums []struct {
+				ChecksumValue string `json:"checksumValue"`
+			} `json:"checksums"`
+		} `json:"packages"`
+	}
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	assert.Equal(t, "image.raw", document.Name)
+	require.Len(t, document.Packages, 3)
+	assert.Equal(t, "base.raw", document.Packages[1].Name)
+	assert.Equal(t, "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d", document.Packages[1].Checksums[0].ChecksumValue)
+	assert.Equal(t, "git", document.Packages[2].Name)
+
+	assert.FileExists(t, ctx.OutputPath()+sbom.CycloneDXExtension)
+}
+
+func TestWriteSBOM_ConfigDrive(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.IsConfigDrive = true
+	ctx.ImageDefinition.Image = image.Image{
+		ImageType:       image.TypeTar,
+		OutputImageName: "combustion.tar",
+	}
+
+	require.NoError(t, writeSBOM(ctx))
+
+	assert.FileExists(t, ctx.OutputPath()+sbom.SPDXExtension)
+	assert.FileExists(t, ctx.OutputPath()+sbom.CycloneDXExtension)
+}
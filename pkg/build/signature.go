@@ -0,0 +1,27 @@
+package build
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
+)
+
+// signOutput creates a detached signature of the output if a signing key is configured.
+func signOutput(ctx *image.Context, outputPath string) error {
+	if ctx.SigningKey == "" {
+		return nil
+	}
+
+	log.Audit("Signing output...")
+
+	signaturePath, err := signing.SignFile(ctx.SigningKey, outputPath)
+	if err != nil {
+		return fmt.Errorf("signing output: %w", err)
+	}
+
+	log.Auditf("The signature can be found at: %s", filepath.Base(signaturePath))
+	return nil
+}
@@ -0,0 +1,56 @@
+package build
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
+)
+
+func TestSignOutput(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	ctx.SigningKey = filepath.Join(ctx.BuildDir, "signing.pem")
+	require.NoError(t, os.WriteFile(ctx.SigningKey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDER}), 0o600))
+
+	publicKey := filepath.Join(ctx.BuildDir, "signing.pub")
+	require.NoError(t, os.WriteFile(publicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	outputPath := filepath.Join(ctx.ImageConfigDir, "image.raw")
+	require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+
+	require.NoError(t, signOutput(ctx, outputPath))
+
+	signaturePath, err := signing.VerifyFile(publicKey, outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, outputPath+signing.CosignSignatureExtension, signaturePath)
+}
+
+func TestSignOutput_NoKey(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	outputPath := filepath.Join(ctx.ImageConfigDir, "image.raw")
+	require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+
+	require.NoError(t, signOutput(ctx, outputPath))
+	assert.NoFileExists(t, outputPath+signing.CosignSignatureExtension)
+}
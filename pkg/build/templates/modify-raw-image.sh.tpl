@@ -3,6 +3,7 @@ set -euo pipefail
 
 #  Template Fields
 #  ImagePath                 - Full path to the image to modify
+#  Format                    - The format of the image to modify, either raw or qcow2
 #  CombustionDir             - Full path to the combustion directory
 #  ArtefactsDir              - Full path to the artefacts directory
 #  ConfigureGRUB             - Contains the guestfish command lines to run to manipulate GRUB configuration.
@@ -13,6 +14,9 @@ set -euo pipefail
 #  Arch                      - The architecture of the image to be built
 #  LUKSKey                   - The key necessary for modifying encrypted raw images
 #  ExpandEncryptedPartition  - If true, expands the encrypted partition during the build process
+#  RootSize                  - If set, the root partition is resized to this size instead of taking up the whole disk
+#  Partitions                - Additional partitions to create after the root partition, each of them containing
+#                              its Label, Size, Filesystem, MountPoint and the LUKSKeyFile to encrypt it with
 #
 # Guestfish Command Documentation: https://libguestfs.org/guestfish.1.html
 
@@ -35,7 +39,7 @@ LUKSFLAG="--key all:key:{{ .LUKSKey }}"
 
 # Test the block size of the base image and adapt to suit either 512/4096 byte images
 BLOCKSIZE=512
-if ! guestfish -i --blocksize=$BLOCKSIZE -a {{.ImagePath}} $LUKSFLAG echo "[INFO] 512 byte sector check successful."; then
+if ! guestfish -i --blocksize=$BLOCKSIZE --format={{.Format}} -a {{.ImagePath}} $LUKSFLAG echo "[INFO] 512 byte sector check successful."; then
         echo "[WARN] Failed to access image with 512 byte sector size, trying 4096 bytes."
         BLOCKSIZE=4096
 fi
@@ -43,15 +47,55 @@ fi
 # Resize the raw disk image to accommodate the users desired raw disk image size
 # This is also required if embedding content into /combustion, especially for airgap.
 # Should *only* execute if the user is building a raw disk image.
+# qcow2 images are resized into a new qcow2 image, which only allocates the space written to.
 {{ if ne .DiskSize "" -}}
+{{ if eq .Format "qcow2" -}}
+qemu-img create -f qcow2 {{.ImagePath}}.expanded {{.DiskSize}}
+{{ else -}}
 truncate -r {{.ImagePath}} {{.ImagePath}}.expanded
 truncate -s {{.DiskSize}} {{.ImagePath}}.expanded
-virt-resize --expand $ROOT_PART {{.ImagePath}} {{.ImagePath}}.expanded
+{{ end -}}
+{{ if ne .RootSize "" -}}
+virt-resize --format={{.Format}} --output-format={{.Format}} --resize $ROOT_PART={{.RootSize}} --no-extra-partition {{.ImagePath}} {{.ImagePath}}.expanded
+{{ else -}}
+virt-resize --format={{.Format}} --output-format={{.Format}} --expand $ROOT_PART {{.ImagePath}} {{.ImagePath}}.expanded
+{{ end -}}
 cp {{.ImagePath}}.expanded {{.ImagePath}}
 rm -f {{.ImagePath}}.expanded
 {{ end }}
 
-guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} $LUKSFLAG -i <<'EOF'
+# Create and format the additional partitions in the space left after the root partition.
+# They are identified by their partition label, which is also used as filesystem label and LUKS mapping name.
+# The partitions are created through guestfish, which supports qcow2 images as well, aligned to 1 MiB.
+# A partition without a size takes up the rest of the disk, leaving 1 MiB for the backup GPT.
+{{ if .Partitions -}}
+PARTNUM=${ROOT_PART#/dev/sda}
+PART_END=$(guestfish --blocksize=$BLOCKSIZE --format={{.Format}} --ro -a {{.ImagePath}} run : part-list /dev/sda | awk '/part_end:/ {print $2}' | tail -n 1)
+{{ range .Partitions -}}
+PARTNUM=$((PARTNUM + 1))
+PART_START=$(( (PART_END / 1048576 + 1) * 1048576 / BLOCKSIZE ))
+{{ if ne .Size "" -}}
+PART_END=$(( PART_START * BLOCKSIZE + $(numfmt --from=iec {{ .Size }}) - 1 ))
+PART_LAST_SECTOR=$(( PART_END / BLOCKSIZE ))
+{{ else -}}
+PART_LAST_SECTOR=-$(( 1048576 / BLOCKSIZE ))
+{{ end -}}
+PART_ADD="part-add /dev/sda p $PART_START $PART_LAST_SECTOR : part-set-name /dev/sda $PARTNUM {{ .Label }} : part-set-gpt-type /dev/sda $PARTNUM 0FC63DAF-8483-4772-8E79-3D69D8477DE4"
+{{ if .LUKSKeyFile -}}
+# The key is read twice, once for formatting the partition and once for opening it
+printf '%s\n%s\n' "$(cat {{ .LUKSKeyFile }})" "$(cat {{ .LUKSKeyFile }})" | \
+  guestfish --blocksize=$BLOCKSIZE --format={{ $.Format }} --rw --keys-from-stdin -a {{ $.ImagePath }} \
+  run : $PART_ADD : luks-format /dev/sda$PARTNUM 0 : luks-open /dev/sda$PARTNUM {{ .Label }} : \
+  mkfs {{ .Filesystem }} /dev/mapper/{{ .Label }} : set-label /dev/mapper/{{ .Label }} {{ .Label }} : \
+  luks-close /dev/mapper/{{ .Label }}
+{{ else -}}
+guestfish --blocksize=$BLOCKSIZE --format={{ $.Format }} --rw -a {{ $.ImagePath }} \
+  run : $PART_ADD : mkfs {{ .Filesystem }} /dev/sda$PARTNUM : set-label /dev/sda$PARTNUM {{ .Label }}
+{{ end -}}
+{{ end -}}
+{{ end }}
+
+guestfish --blocksize=$BLOCKSIZE --format={{.Format}} --rw -a {{.ImagePath}} $LUKSFLAG -i <<'EOF'
   # Enables write access to the read only filesystem
   sh "btrfs property set / ro false"
 
@@ -59,6 +103,20 @@ guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} $LUKSFLAG -
   sh "btrfs filesystem resize max /"
   {{ end }}
 
+  {{ range .Partitions }}
+  # Register the {{ .Label }} partition to be mounted at boot
+  mkdir-p {{ .MountPoint }}
+  {{ if .LUKSKeyFile }}
+  mkdir-p /etc/cryptsetup-keys.d
+  upload {{ .LUKSKeyFile }} /etc/cryptsetup-keys.d/{{ .Label }}.key
+  chmod 0400 /etc/cryptsetup-keys.d/{{ .Label }}.key
+  write-append /etc/crypttab "{{ .Label }} PARTLABEL={{ .Label }} /etc/cryptsetup-keys.d/{{ .Label }}.key luks\n"
+  write-append /etc/fstab "/dev/mapper/{{ .Label }} {{ .MountPoint }} {{ .Filesystem }} defaults 0 0\n"
+  {{ else }}
+  write-append /etc/fstab "PARTLABEL={{ .Label }} {{ .MountPoint }} {{ .Filesystem }} defaults 0 0\n"
+  {{ end }}
+  {{ end }}
+
   {{ if ne .ConfigureGRUB "" }}
   {{ .ConfigureGRUB }}
   {{ end }}
@@ -0,0 +1,8 @@
+#!ipxe
+
+# The PXE artefacts are fetched from the location this script was loaded from
+set base-url ${cwduri}
+
+kernel ${base-url}/{{.KernelName}} initrd={{.InitrdName}} {{.KernelArgs}}
+initrd ${base-url}/{{.InitrdName}}
+boot
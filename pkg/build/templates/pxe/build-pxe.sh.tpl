@@ -0,0 +1,46 @@
+#!/bin/bash
+set -euo pipefail
+
+#  Template Fields
+#  IsoExtractDir - Full path to the directory where the ISO was extracted
+#  RawExtractDir - Full path to the directory where the RAW image was extracted
+#  OutputDir - Full path to the directory the PXE artefacts should be written to
+#  RootfsImage - Name of the compressed RAW image to create in the output directory
+#  KernelName - Name of the kernel to create in the output directory
+#  InitrdName - Name of the initrd to create in the output directory
+
+ISO_EXTRACT_DIR={{.IsoExtractDir}}
+RAW_EXTRACT_DIR={{.RawExtractDir}}
+OUTPUT_DIR={{.OutputDir}}
+ROOTFS_IMAGE=${OUTPUT_DIR}/{{.RootfsImage}}
+
+# The kiwi installer kernel and initrd are located under /boot/<arch>/loader in the SelfInstall ISO
+KERNEL_FILE=`find ${ISO_EXTRACT_DIR}/boot -path "*/loader/linux"`
+INITRD_FILE=`find ${ISO_EXTRACT_DIR}/boot -path "*/loader/initrd"`
+if [ `wc -w <<< $KERNEL_FILE` -ne 1 ] || [ `wc -w <<< $INITRD_FILE` -ne 1 ]; then
+	echo "Unexpected kernel or initrd files: $KERNEL_FILE $INITRD_FILE"
+	exit 1
+fi
+
+cp ${KERNEL_FILE} ${OUTPUT_DIR}/{{.KernelName}}
+cp ${INITRD_FILE} ${OUTPUT_DIR}/{{.InitrdName}}
+
+RAW_IMAGE_FILE=`find ${RAW_EXTRACT_DIR} -name "*.raw"`
+
+# The kiwi installer verifies the deployed image against a checksum file next to it, containing
+# the checksum along with the block count and block size of the uncompressed image
+MD5_CHECKSUM_FILE=`find "${RAW_EXTRACT_DIR}" -name "*.md5"`
+SHA256_CHECKSUM_FILE=`find "${RAW_EXTRACT_DIR}" -name "*.sha256"`
+
+if [[ -n "$SHA256_CHECKSUM_FILE" ]]; then
+  BLK_CONF=$(awk '{print $2 " " $3;}' "$SHA256_CHECKSUM_FILE")
+  echo "$(sha256sum "${RAW_IMAGE_FILE}" | awk '{print $1;}') $BLK_CONF" > "${ROOTFS_IMAGE%.xz}.sha256"
+elif [[ -n "$MD5_CHECKSUM_FILE" ]]; then
+  BLK_CONF=$(awk '{print $2 " " $3;}' "$MD5_CHECKSUM_FILE")
+  echo "$(md5sum "${RAW_IMAGE_FILE}" | awk '{print $1;}') $BLK_CONF" > "${ROOTFS_IMAGE%.xz}.md5"
+else
+  echo "Error: No MD5 or SHA256 checksum file found in ${RAW_EXTRACT_DIR}" >&2
+  exit 1
+fi
+
+xz --threads=0 --keep --stdout ${RAW_IMAGE_FILE} > ${ROOTFS_IMAGE}
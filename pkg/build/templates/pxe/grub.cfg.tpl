@@ -0,0 +1,11 @@
+# The PXE artefacts are expected to be served from the '{{.Directory}}' directory of the TFTP server
+set pxe_dir=/{{.Directory}}
+set timeout=3
+set timeout_style=menu
+
+menuentry "Install {{.Name}}" --id eib-install {
+	echo "Loading kernel..."
+	linux ${pxe_dir}/{{.KernelName}} {{.KernelArgs}}
+	echo "Loading initrd..."
+	initrd ${pxe_dir}/{{.InitrdName}}
+}
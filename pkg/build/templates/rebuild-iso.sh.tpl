@@ -48,6 +48,14 @@ SQUASH_IMAGE_FILE=`find ${ISO_EXTRACT_DIR} -name "*.squashfs"`
 SQUASH_BASENAME=`basename ${SQUASH_IMAGE_FILE}`
 NEW_SQUASH_FILE=${RAW_EXTRACT_DIR}/${SQUASH_BASENAME}
 
+# Keep the original GRUB configuration, so that it is not modified twice if the build is resumed
+GRUB_CONFIG=${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
+if [[ -f ${RAW_EXTRACT_DIR}/grub.cfg.orig ]]; then
+  cp ${RAW_EXTRACT_DIR}/grub.cfg.orig ${GRUB_CONFIG}
+elif [[ -f ${GRUB_CONFIG} ]]; then
+  cp ${GRUB_CONFIG} ${RAW_EXTRACT_DIR}/grub.cfg.orig
+fi
+
 # Select the desired install device - assumes data destruction and makes the installation fully unattended by enabling GRUB timeout
 {{ if ne .InstallDevice "" -}}
 echo -e "set timeout=3\nset timeout_style=menu\n$(cat ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg)" > ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
@@ -60,6 +68,7 @@ sed -i '/root=install:CDLABEL=INSTALL/ s|$| rd.kiwi.install.pass.bootparam {{.Ke
 {{ end -}}
 
 cd ${RAW_EXTRACT_DIR}
+rm -f ${NEW_SQUASH_FILE}
 mksquashfs ${RAW_IMAGE_FILE} ${CHECKSUM_FILE} ${NEW_SQUASH_FILE}
 
 # Rebuild the previously extracted ISO with the new squashed raw image
@@ -0,0 +1,203 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	fileName      = "checkpoints.json"
+	baseImagesDir = "base-images"
+)
+
+type Stage string
+
+// Stages of a build in the order they are completed. Stages which do not apply to the image type are not recorded.
+const (
+	StageDefinitionParsed     Stage = "definition-parsed"
+	StageCombustionConfigured Stage = "combustion-configured"
+	// StageRawModified is completed once the RAW image, or the one extracted from the ISO, has been modified.
+	StageRawModified Stage = "raw-modified"
+	// StageImageBuilt is completed once the output is written, e.g. the ISO has been rebuilt.
+	StageImageBuilt       Stage = "image-built"
+	StageOutputCompressed Stage = "output-compressed"
+)
+
+// Checkpoints tracks the stages a build has completed, so that an interrupted build can be resumed.
+// A nil Checkpoints never reports a stage as completed and records nothing.
+type Checkpoints struct {
+	path string
+	// InputsHash identifies the inputs the build was started with.
+	InputsHash string  `json:"inputsHash"`
+	Stages     []Stage `json:"stages"`
+}
+
+// New starts tracking the stages of a build in the given build directory.
+func New(buildDir, inputsHash string) *Checkpoints {
+	return &Checkpoints{
+		path:       filepath.Join(buildDir, fileName),
+		InputsHash: inputsHash,
+	}
+}
+
+// Load reads the stages completed by the build in the given build directory.
+func Load(buildDir string) (*Checkpoints, error) {
+	path := filepath.Join(buildDir, fileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoints: %w", err)
+	}
+
+	c := &Checkpoints{path: path}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("decoding checkpoints: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c *Checkpoints) Completed(stage Stage) bool {
+	return c != nil && slices.Contains(c.Stages, stage)
+}
+
+// Last returns the most recently completed stage, or an empty stage if none has been completed.
+func (c *Checkpoints) Last() Stage {
+	if c == nil || len(c.Stages) == 0 {
+		return ""
+	}
+
+	return c.Stages[len(c.Stages)-1]
+}
+
+// Complete records the stage as completed in the build directory.
+func (c *Checkpoints) Complete(stage Stage) error {
+	if c == nil || c.Completed(stage) {
+		return nil
+	}
+
+	c.Stages = append(c.Stages, stage)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding checkpoints: %w", err)
+	}
+
+	if err = os.WriteFile(c.path, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing checkpoints: %w", err)
+	}
+
+	return nil
+}
+
+// HashInputs calculates a digest of the resolved image definition, the files under the image configuration
+// directory and the given additional files, e.g. the artifact sources. The resolved definition covers the
+// values which are not part of the directory, such as the secrets read from environment variables. Base
+// images are only identified by their name, size and modification time, as hashing them would take longer
+// than some of the stages. The excluded paths are skipped, such as the root build directory and the output
+// image along with its sidecar files, since they are written by the build itself.
+func HashInputs(configDir string, definition any, excluded []string, files ...string) (string, error) {
+	h := sha256.New()
+
+	if _, err := fmt.Fprintln(h, version.GetEibVersion()); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(definition)
+	if err != nil {
+		return "", fmt.Errorf("encoding image definition: %w", err)
+	}
+
+	if _, err = h.Write(data); err != nil {
+		return "", err
+	}
+
+	baseImages := filepath.Join(configDir, baseImagesDir)
+
+	err = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(excluded, func(p string) bool {
+			return isExcluded(path, filepath.Clean(p))
+		}) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(configDir, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(h, "%s %s\n", rel, info.Mode()); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if filepath.Dir(path) == baseImages {
+			_, err = fmt.Fprintf(h, "%d %d\n", info.Size(), info.ModTime().UnixNano())
+			return err
+		}
+
+		return hashFile(h, path)
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashing image configuration directory: %w", err)
+	}
+
+	for _, file := range files {
+		if _, err = fmt.Fprintln(h, filepath.Base(file)); err != nil {
+			return "", err
+		}
+
+		if err = hashFile(h, file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("hashing %s: %w", file, err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isExcluded reports whether the path is the excluded path, located under it or one of its sidecar files,
+// which are named after it with an additional extension. Only whole path components are compared, so that
+// e.g. "_build2" is not excluded along with "_build".
+func isExcluded(path, excluded string) bool {
+	return path == excluded ||
+		strings.HasPrefix(path, excluded+string(filepath.Separator)) ||
+		strings.HasPrefix(path, excluded+".")
+}
+
+func hashFile(h io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(h, file)
+	return err
+}
@@ -0,0 +1,109 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoints(t *testing.T) {
+	buildDir := t.TempDir()
+
+	checkpoints := New(buildDir, "abc")
+	assert.False(t, checkpoints.Completed(StageDefinitionParsed))
+	assert.Empty(t, checkpoints.Last())
+
+	require.NoError(t, checkpoints.Complete(StageDefinitionParsed))
+	require.NoError(t, checkpoints.Complete(StageCombustionConfigured))
+	require.NoError(t, checkpoints.Complete(StageCombustionConfigured))
+
+	loaded, err := Load(buildDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "abc", loaded.InputsHash)
+	assert.Equal(t, []Stage{StageDefinitionParsed, StageCombustionConfigured}, loaded.Stages)
+	assert.True(t, loaded.Completed(StageCombustionConfigured))
+	assert.False(t, loaded.Completed(StageRawModified))
+	assert.Equal(t, StageCombustionConfigured, loaded.Last())
+
+	require.NoError(t, loaded.Complete(StageRawModified))
+
+	reloaded, err := Load(buildDir)
+	require.NoError(t, err)
+	assert.Equal(t, StageRawModified, reloaded.Last())
+}
+
+func TestCheckpoints_Nil(t *testing.T) {
+	var checkpoints *Checkpoints
+
+	assert.NoError(t, checkpoints.Complete(StageDefinitionParsed))
+	assert.False(t, checkpoints.Completed(StageDefinitionParsed))
+	assert.Empty(t, checkpoints.Last())
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestHashInputs(t *testing.T) {
+	configDir := t.TempDir()
+	buildDir := filepath.Join(configDir, "_build")
+	outputPath := filepath.Join(configDir, "eib-image.iso")
+	artifactsFile := filepath.Join(t.TempDir(), "artifacts.yaml")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o755))
+	require.NoError(t, os.MkdirAll(buildDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "definition.yaml"), []byte("apiVersion: 1.4"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base-images", "base.iso"), []byte("iso"), 0o600))
+	require.NoError(t, os.WriteFile(artifactsFile, []byte("metallb: {}"), 0o600))
+
+	definition := map[string]string{"password": "alpha"}
+
+	hash := func() string {
+		h, err := HashInputs(configDir, definition, []string{buildDir, outputPath}, artifactsFile)
+		require.NoError(t, err)
+		return h
+	}
+
+	original := hash()
+
+	// Files written by the build itself are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(buildDir, "eib-build.log"), []byte("log"), 0o600))
+	require.NoError(t, os.WriteFile(outputPath, []byte("output"), 0o600))
+	require.NoError(t, os.WriteFile(outputPath+".sha256", []byte("checksum"), 0o600))
+	assert.Equal(t, original, hash())
+
+	// Siblings sharing a prefix with the excluded paths are inputs
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "_build2"), 0o755))
+	assert.NotEqual(t, original, hash())
+
+	original = hash()
+	require.NoError(t, os.WriteFile(outputPath+"-files", []byte("input"), 0o600))
+	assert.NotEqual(t, original, hash())
+
+	original = hash()
+
+	// Base images are identified by their size and modification time
+	baseImage := filepath.Join(configDir, "base-images", "base.iso")
+	modTime := time.Unix(1000, 0)
+	require.NoError(t, os.Chtimes(baseImage, modTime, modTime))
+	assert.NotEqual(t, original, hash())
+
+	original = hash()
+	require.NoError(t, os.WriteFile(artifactsFile, []byte("metallb: {chart: x}"), 0o600))
+	assert.NotEqual(t, original, hash())
+
+	original = hash()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "definition.yaml"), []byte("apiVersion: 1.3"), 0o600))
+	assert.NotEqual(t, original, hash())
+
+	// Values resolved from outside the directory, such as secrets from environment variables
+	original = hash()
+	definition["password"] = "beta"
+	assert.NotEqual(t, original, hash())
+}
@@ -1,17 +1,26 @@
 package build
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
 	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
 	"github.com/suse-edge/edge-image-builder/pkg/version"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
@@ -21,9 +30,12 @@ import (
 const (
 	buildLogFilename     = "eib-build.log"
 	checkBuildLogMessage = "Please check the eib-build.log file under the build directory for more information."
+	artifactsConfigFile  = "artifacts.yaml"
 )
 
-func Run(_ *cli.Context) error {
+var errExitedOnSignal = errors.New("exited immediately after a second signal")
+
+func Run(c *cli.Context) error {
 	args := &cmd.CommonArgs
 
 	rootBuildDir := args.RootBuildDir
@@ -31,20 +43,35 @@ func Run(_ *cli.Context) error {
 		const defaultBuildDir = "_build"
 
 		rootBuildDir = filepath.Join(args.ConfigDir, defaultBuildDir)
-		if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
-			log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
-			return err
-		}
 	}
 
-	buildDir, err := eib.SetupBuildDirectory(rootBuildDir)
-	if err != nil {
-		log.Audit("The build directory could not be set up.")
+	// A dry run leaves the image configuration directory untouched
+	if c.Bool("dry-run") {
+		return dryRun(c, rootBuildDir)
+	}
+
+	if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
+		log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
 		return err
 	}
 
+	resumeDir := c.String("resume")
+
+	buildDir := resumeDir
+	if buildDir == "" {
+		var err error
+		if buildDir, err = eib.SetupBuildDirectory(rootBuildDir); err != nil {
+			log.Audit("The build directory could not be set up.")
+			return err
+		}
+	} else if !fileio.DirExists(buildDir) {
+		log.Auditf("The build directory '%s' to resume could not be found.", buildDir)
+		os.Exit(1)
+	}
+
 	var cacheDir string
 	if args.Cache {
+		var err error
 		cacheDir, err = eib.SetupCacheDirectory(rootBuildDir, args.CacheDir)
 		if err != nil {
 			log.Audit("The cache directory could not be set up.")
@@ -55,16 +82,7 @@ func Run(_ *cli.Context) error {
 	// This needs to occur as early as possible so that the subsequent calls can use the log
 	log.ConfigureGlobalLogger(filepath.Join(buildDir, buildLogFilename))
 
-	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
-		cmd.LogError(cmdErr, checkBuildLogMessage)
-		os.Exit(1)
-	}
-
-	imageDefinition, cmdErr := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
-	if cmdErr != nil {
-		cmd.LogError(cmdErr, checkBuildLogMessage)
-		os.Exit(1)
-	}
+	ctx := loadBuildContext(c, buildDir, cacheDir, checkBuildLogMessage)
 
 	combustionDir, artefactsDir, err := eib.SetupCombustionDirectory(buildDir)
 	if err != nil {
@@ -72,18 +90,29 @@ func Run(_ *cli.Context) error {
 		zap.S().Fatalf("Failed to create combustion directories: %s", err)
 	}
 
-	artifactSources, err := parseArtifactSources()
-	if err != nil {
-		log.Auditf("Loading artifact sources metadata failed. %s", checkBuildLogMessage)
-		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
-	}
-
-	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, imageDefinition, artifactSources)
+	ctx.CombustionDir = combustionDir
+	ctx.ArtefactsDir = artefactsDir
 
-	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
+	checkpoints, cmdErr := setupCheckpoints(ctx, rootBuildDir, resumeDir != "")
+	if cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
 		os.Exit(1)
 	}
+	ctx.Checkpoints = checkpoints
+
+	if err = eib.ClearBuildAborted(buildDir); err != nil {
+		zap.S().Warnf("Clearing the aborted marker of the resumed build failed: %s", err)
+	}
+
+	buildCtx, cancel := cancelOnSignal()
+	defer cancel()
+
+	ctx.Context = buildCtx
+
+	abortOnExit := process.RegisterCleanup(func() {
+		markBuildAborted(buildDir, errExitedOnSignal)
+	})
+	defer abortOnExit.Release()
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -93,6 +122,12 @@ func Run(_ *cli.Context) error {
 	}()
 
 	if err = eib.Run(ctx, rootBuildDir); err != nil {
+		if buildCtx.Err() != nil {
+			markBuildAborted(buildDir, err)
+			log.Auditf("Build aborted. It can be resumed with '--resume %s'.", buildDir)
+			zap.S().Fatalf("Build aborted: %s", err)
+		}
+
 		log.Audit(checkBuildLogMessage)
 		zap.S().Fatalf("An error occurred building the image: %s", err)
 	}
@@ -100,6 +135,22 @@ func Run(_ *cli.Context) error {
 	return nil
 }
 
+func unknownFieldsMessage(definitionFilePath, definitionFile string, err *image.UnknownFieldsError) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("The image definition file '%s' contains unknown fields:\n", definitionFilePath))
+
+	for _, f := range err.Fields {
+		builder.WriteString(fmt.Sprintf("  %s: '%s'", sourcePosition(definitionFile, f.Position), f.Path))
+		if f.Suggestion != "" {
+			builder.WriteString(fmt.Sprintf(", did you mean '%s'?", f.Suggestion))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
 func imageConfigDirExists(configDir string) *cmd.Error {
 	_, err := os.Stat(configDir)
 	if err == nil {
@@ -119,6 +170,20 @@ func imageConfigDirExists(configDir string) *cmd.Error {
 }
 
 func parseDefinitionFile(configDir, definitionFile string) (*image.Definition, *cmd.Error) {
+	configData, cmdErr := readDefinitionFile(configDir, definitionFile)
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	imageDefinition, err := parseDefinition(configDir, configData)
+	if err != nil {
+		return nil, definitionParseError(configDir, definitionFile, err)
+	}
+
+	return imageDefinition, nil
+}
+
+func readDefinitionFile(configDir, definitionFile string) ([]byte, *cmd.Error) {
 	definitionFilePath := filepath.Join(configDir, definitionFile)
 
 	configData, err := os.ReadFile(definitionFilePath)
@@ -135,29 +200,54 @@ func parseDefinitionFile(configDir, definitionFile string) (*image.Definition, *
 		}
 	}
 
-	imageDefinition, err := image.ParseDefinition(configData)
+	return configData, nil
+}
+
+func parseDefinition(configDir string, configData []byte) (*image.Definition, error) {
+	imageDefinition, err := image.ParseDefinition(configData, configDir)
 	if err != nil {
-		if errors.Is(err, image.ErrorInvalidSchemaVersion) {
-			m := "Invalid schema version specified. This version of Edge Image Builder supports the following schema versions: %s"
-			msg := fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", "))
-			return nil, &cmd.Error{
-				UserMessage: msg,
-				LogMessage:  msg,
-			}
+		return nil, err
+	}
+
+	log.RegisterSecrets(imageDefinition.Secrets()...)
+
+	return imageDefinition, nil
+}
+
+func definitionParseError(configDir, definitionFile string, err error) *cmd.Error {
+	definitionFilePath := filepath.Join(configDir, definitionFile)
+
+	if errors.Is(err, image.ErrorInvalidSchemaVersion) {
+		m := "Invalid schema version specified. This version of Edge Image Builder supports the following schema versions: %s"
+		msg := fmt.Sprintf(m, strings.Join(version.SupportedSchemaVersions, ", "))
+		return &cmd.Error{
+			UserMessage: msg,
+			LogMessage:  msg,
 		}
+	}
 
-		return nil, &cmd.Error{
-			UserMessage: fmt.Sprintf("The image definition file '%s' could not be parsed.", definitionFilePath),
+	var unknownFieldsErr *image.UnknownFieldsError
+	if errors.As(err, &unknownFieldsErr) {
+		return &cmd.Error{
+			UserMessage: unknownFieldsMessage(definitionFilePath, definitionFile, unknownFieldsErr),
 			LogMessage:  fmt.Sprintf("Parsing definition file failed: %v", err),
 		}
 	}
 
-	return imageDefinition, nil
+	if errors.Is(err, image.ErrorUnresolvedSecret) {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition file '%s' references a secret which could not be resolved.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Resolving definition secrets failed: %v", err),
+		}
+	}
+
+	return &cmd.Error{
+		UserMessage: fmt.Sprintf("The image definition file '%s' could not be parsed.", definitionFilePath),
+		LogMessage:  fmt.Sprintf("Parsing definition file failed: %v", err),
+	}
 }
 
 func parseArtifactSources() (*image.ArtifactSources, error) {
-	const artifactsConfigFile = "artifacts.yaml"
-
 	b, err := os.ReadFile(artifactsConfigFile)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -176,15 +266,232 @@ func parseArtifactSources() (*image.ArtifactSources, error) {
 }
 
 // Assembles the image build context with user-provided values and implementation defaults.
-func buildContext(buildDir, combustionDir, artefactsDir, configDir, cacheDir string, imageDefinition *image.Definition, artifactSources *image.ArtifactSources) *image.Context {
+// loadBuildContext parses the image definition and the artifact sources and validates the inputs of the
+// build, exiting if any of them is invalid. The combustion directories are left to the caller.
+func loadBuildContext(c *cli.Context, buildDir, cacheDir, checkLogMessage string) *image.Context {
+	args := &cmd.CommonArgs
+
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	imageDefinition, cmdErr := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	artifactSources, err := parseArtifactSources()
+	if err != nil {
+		log.Auditf("Loading artifact sources metadata failed. %s", checkLogMessage)
+		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
+	}
+
+	ctx := buildContext(buildDir, "", "", args.ConfigDir, args.DefinitionFile, cacheDir, imageDefinition, artifactSources)
+	ctx.ProvenanceKey = args.ProvenanceKey
+	ctx.SigningKey = args.SigningKey
+	ctx.Preflight = c.Bool("preflight")
+	ctx.ReuseBuildDir = c.String("reuse-build")
+
+	if cmdErr = validateImageDefinition(ctx, args.DefinitionFile, args.Strict); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr = validateProvenanceKey(args.ProvenanceKey); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr = validateSigningKey(args.SigningKey, imageDefinition.Image.ImageType); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr = validateReuseBuildDir(ctx.ReuseBuildDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	return ctx
+}
+
+func buildContext(buildDir, combustionDir, artefactsDir, configDir, definitionFile, cacheDir string, imageDefinition *image.Definition, artifactSources *image.ArtifactSources) *image.Context {
 	ctx := &image.Context{
-		ImageConfigDir:  configDir,
-		BuildDir:        buildDir,
-		CombustionDir:   combustionDir,
-		ArtefactsDir:    artefactsDir,
-		CacheDir:        cacheDir,
-		ImageDefinition: imageDefinition,
-		ArtifactSources: artifactSources,
+		ImageConfigDir:      configDir,
+		DefinitionFile:      filepath.Join(configDir, definitionFile),
+		BuildDir:            buildDir,
+		CombustionDir:       combustionDir,
+		ArtefactsDir:        artefactsDir,
+		CacheDir:            cacheDir,
+		ImageDefinition:     imageDefinition,
+		ArtifactSources:     artifactSources,
+		ArtifactSourcesFile: artifactsConfigFile,
 	}
 	return ctx
 }
+
+// validateProvenanceKey ensures that the provenance signing key is usable before starting the build.
+func validateProvenanceKey(keyPath string) *cmd.Error {
+	if keyPath == "" {
+		return nil
+	}
+
+	if _, err := signing.LoadPrivateKey(keyPath); err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The provenance signing key '%s' could not be loaded.", keyPath),
+			LogMessage:  fmt.Sprintf("Loading provenance signing key failed: %v", err),
+		}
+	}
+
+	return nil
+}
+
+// validateSigningKey ensures that the output signing key is usable before starting the build.
+func validateSigningKey(keyPath, imageType string) *cmd.Error {
+	if keyPath == "" {
+		return nil
+	}
+
+	if imageType == image.TypePXE {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("Signing the output is not supported for the '%s' image type.", image.TypePXE),
+		}
+	}
+
+	if err := signing.CheckSigningKey(keyPath); err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The output signing key '%s' could not be loaded.", keyPath),
+			LogMessage:  fmt.Sprintf("Loading output signing key failed: %v", err),
+		}
+	}
+
+	return nil
+}
+
+// validateReuseBuildDir ensures that the output of the previous build can be reused before starting the build.
+func validateReuseBuildDir(dir string) *cmd.Error {
+	if dir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &cmd.Error{
+				UserMessage: fmt.Sprintf("The build directory '%s' to reuse could not be found.", dir),
+			}
+		}
+
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The build directory '%s' to reuse could not be read.", dir),
+			LogMessage:  fmt.Sprintf("Reading build dir to reuse failed: %v", err),
+		}
+	}
+
+	if !info.IsDir() {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The build directory '%s' to reuse is not a directory.", dir),
+		}
+	}
+
+	return nil
+}
+
+// setupCheckpoints starts tracking the stages of the build, or loads the stages completed by the build being resumed.
+// A build can only be resumed if its inputs are unchanged.
+func setupCheckpoints(ctx *image.Context, rootBuildDir string, resume bool) (*checkpoint.Checkpoints, *cmd.Error) {
+	excluded := []string{
+		rootBuildDir,
+		ctx.OutputPath(),
+		filepath.Join(combustion.GPGKeysPath(ctx), kubernetes.SELinuxSigningKeyName),
+	}
+
+	inputsHash, err := checkpoint.HashInputs(ctx.ImageConfigDir, ctx.ImageDefinition, excluded, ctx.ArtifactSourcesFile)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: "The build inputs could not be read.",
+			LogMessage:  fmt.Sprintf("Hashing build inputs failed: %v", err),
+		}
+	}
+
+	if !resume {
+		checkpoints := checkpoint.New(ctx.BuildDir, inputsHash)
+		if err = checkpoints.Complete(checkpoint.StageDefinitionParsed); err != nil {
+			return nil, &cmd.Error{
+				UserMessage: "The build progress could not be recorded.",
+				LogMessage:  fmt.Sprintf("Recording checkpoint failed: %v", err),
+			}
+		}
+
+		return checkpoints, nil
+	}
+
+	checkpoints, err := checkpoint.Load(ctx.BuildDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The build directory '%s' does not contain any checkpoints to resume from.", ctx.BuildDir),
+			}
+		}
+
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The checkpoints of the build directory '%s' could not be read.", ctx.BuildDir),
+			LogMessage:  fmt.Sprintf("Loading checkpoints failed: %v", err),
+		}
+	}
+
+	if checkpoints.InputsHash != inputsHash {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The build in '%s' can not be resumed, as the image definition, the image configuration "+
+				"directory or the artifact sources have changed since it was started. Please start a new build instead.", ctx.BuildDir),
+		}
+	}
+
+	log.Auditf("Resuming the build in '%s' after the '%s' stage.", ctx.BuildDir, checkpoints.Last())
+	return checkpoints, nil
+}
+
+// cancelOnSignal returns a context which is cancelled once SIGINT or SIGTERM is received, so that the running
+// commands are terminated and the build can clean up after itself. Should the cleanup get stuck, a second signal
+// kills the process groups of the terminated commands, runs the registered cleanups and exits immediately.
+func cancelOnSignal() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			log.Auditf("Received %s, cancelling the build. Send it again to exit immediately.", sig)
+			zap.S().Infof("Cancelling the build after receiving %s", sig)
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signals)
+			return
+		}
+
+		sig := <-signals
+		log.Auditf("Received %s again, exiting immediately.", sig)
+		zap.S().Infof("Killing the cancelled commands after receiving %s again", sig)
+		process.KillCancelled()
+		process.RunCleanups()
+
+		// Exit the way the signal would have terminated the process
+		signal.Reset(os.Interrupt, syscall.SIGTERM)
+		if err := syscall.Kill(os.Getpid(), sig.(syscall.Signal)); err != nil {
+			os.Exit(1)
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// markBuildAborted records that the build was interrupted in the build directory.
+func markBuildAborted(buildDir string, reason error) {
+	if err := eib.MarkBuildAborted(buildDir, reason); err != nil {
+		zap.S().Warnf("Marking the build directory as aborted failed: %s", err)
+	}
+}
@@ -1,9 +1,12 @@
 package build
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
 	"github.com/suse-edge/edge-image-builder/pkg/eib"
@@ -13,6 +16,8 @@ import (
 	"go.uber.org/zap"
 )
 
+const sourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
 func Generate(c *cli.Context) error {
 	args := &cmd.CommonArgs
 
@@ -73,10 +78,32 @@ func Generate(c *cli.Context) error {
 		zap.S().Fatalf("Parsing artifact sources failed: %v", err)
 	}
 
-	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, cacheDir, configDriveDefinition, artifactSources)
+	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, args.DefinitionFile, cacheDir, configDriveDefinition, artifactSources)
 	ctx.IsConfigDrive = true
+	ctx.ProvenanceKey = args.ProvenanceKey
+	ctx.SigningKey = args.SigningKey
+
+	if c.Bool("reproducible") {
+		sourceDateEpoch, cmdErr := parseSourceDateEpoch(os.Getenv(sourceDateEpochEnv))
+		if cmdErr != nil {
+			cmd.LogError(cmdErr, checkBuildLogMessage)
+			os.Exit(1)
+		}
+
+		ctx.SourceDateEpoch = &sourceDateEpoch
+	}
+
+	if cmdErr = validateImageDefinition(ctx, args.DefinitionFile, args.Strict); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr = validateProvenanceKey(args.ProvenanceKey); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
 
-	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
+	if cmdErr = validateSigningKey(args.SigningKey, outputType); cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
 		os.Exit(1)
 	}
@@ -91,6 +118,11 @@ func Generate(c *cli.Context) error {
 		ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
 	}
 
+	generateCtx, cancel := cancelOnSignal()
+	defer cancel()
+
+	ctx.Context = generateCtx
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.Auditf("Build failed unexpectedly. %s", checkBuildLogMessage)
@@ -99,9 +131,33 @@ func Generate(c *cli.Context) error {
 	}()
 
 	if err = eib.Run(ctx, rootBuildDir); err != nil {
+		if generateCtx.Err() != nil {
+			markBuildAborted(buildDir, err)
+			log.Audit("Config drive generation aborted.")
+			zap.S().Fatalf("Config drive generation aborted: %s", err)
+		}
+
 		log.Audit(checkBuildLogMessage)
 		zap.S().Fatalf("An error occurred generating the config drive: %s", err)
 	}
 
 	return nil
 }
+
+// parseSourceDateEpoch parses the timestamp of reproducible config drives as specified by
+// https://reproducible-builds.org/specs/source-date-epoch/. The Unix epoch is used if it is not set.
+func parseSourceDateEpoch(value string) (time.Time, *cmd.Error) {
+	if value == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, &cmd.Error{
+			UserMessage: fmt.Sprintf("The %s environment variable must be a non-negative number of seconds, found '%s'.",
+				sourceDateEpochEnv, value),
+		}
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
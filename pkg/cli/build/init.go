@@ -0,0 +1,71 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+// optionalDirectories are the configuration directories which are only created once they have contents,
+// since most components treat the presence of their directory as being configured.
+var optionalDirectories = []string{
+	"custom/scripts, custom/files - Additional combustion scripts and files",
+	"network - nmstate configuration files for each node",
+	"os-files - Files copied to the root of the file system",
+	"rpms, rpms/gpg-keys - Side-loaded RPM packages and their signing keys",
+	"elemental - Elemental registration configuration",
+	"certificates - Certificates added to the system trust store",
+	"kubernetes/manifests - Kubernetes manifests deployed on the cluster",
+}
+
+func Init(c *cli.Context) error {
+	configDir := cmd.CommonArgs.ConfigDir
+	preset := c.String("preset")
+
+	if !slices.Contains(scaffold.Presets(), preset) {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("A valid preset must be specified through the '--preset' flag, one of: %s.",
+				strings.Join(scaffold.Presets(), ", ")),
+		}, "")
+		os.Exit(1)
+	}
+
+	if scaffold.RequiresBaseImage(preset) && c.String("base-image") == "" {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("A base image must be specified through the '--base-image' flag for the '%s' preset.", preset),
+		}, "")
+		os.Exit(1)
+	}
+
+	result, err := scaffold.Create(configDir, &scaffold.Options{
+		Preset:         preset,
+		DefinitionFile: c.String("definition-file"),
+		Arch:           image.Arch(c.String("arch")),
+		BaseImage:      c.String("base-image"),
+	})
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The configuration directory '%s' could not be created: %v", configDir, err),
+		}, "")
+		os.Exit(1)
+	}
+
+	log.Auditf("Created the '%s' configuration directory:", configDir)
+	for _, created := range result.Created {
+		log.Auditf("  %s", created)
+	}
+
+	log.Audit("\nThe following optional directories may be added when needed:")
+	for _, dir := range optionalDirectories {
+		log.Auditf("  %s", dir)
+	}
+
+	return nil
+}
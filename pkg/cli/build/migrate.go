@@ -0,0 +1,132 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	checkMigrationLogMessage = "Please check the log file under the migration directory for more information."
+)
+
+func Migrate(c *cli.Context) error {
+	args := &cmd.CommonArgs
+
+	migrationDir := filepath.Join(args.ConfigDir, "_migration")
+	if err := os.MkdirAll(migrationDir, os.ModePerm); err != nil {
+		log.Auditf("The migration directory could not be setup under the configuration directory '%s'.", args.ConfigDir)
+		return err
+	}
+
+	// This needs to occur as early as possible so that the subsequent calls can use the log
+	timestamp := time.Now().Format("Jan02_15-04-05")
+	logFilename := filepath.Join(migrationDir, fmt.Sprintf("eib-migrate-%s.log", timestamp))
+	log.ConfigureGlobalLogger(logFilename)
+
+	if err := imageConfigDirExists(args.ConfigDir); err != nil {
+		cmd.LogError(err, checkMigrationLogMessage)
+		os.Exit(1)
+	}
+
+	// Parse the definition first for the detailed error reporting
+	imageDefinition, err := parseUnresolvedDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if err != nil {
+		cmd.LogError(err, checkMigrationLogMessage)
+		os.Exit(1)
+	}
+
+	migrated, err := migrateDefinitionFile(args.ConfigDir, args.DefinitionFile, imageDefinition.APIVersion)
+	if err != nil {
+		cmd.LogError(err, checkMigrationLogMessage)
+		os.Exit(1)
+	}
+
+	output := c.String("output")
+	if output == "" {
+		log.Audit("Migrated image definition:")
+		log.Audit(string(migrated))
+		return nil
+	}
+
+	outputPath := filepath.Join(args.ConfigDir, output)
+	if err := os.WriteFile(outputPath, migrated, fileio.NonExecutablePerms); err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The migrated definition could not be written to '%s'.", outputPath),
+			LogMessage:  fmt.Sprintf("Writing migrated definition failed: %v", err),
+		}, checkMigrationLogMessage)
+		os.Exit(1)
+	}
+
+	log.Auditf("The migrated image definition has been written to '%s'.", outputPath)
+
+	return nil
+}
+
+// parseUnresolvedDefinitionFile parses the definition the same way it is migrated,
+// keeping its secret references unresolved.
+func parseUnresolvedDefinitionFile(configDir, definitionFile string) (*image.Definition, *cmd.Error) {
+	configData, cmdErr := readDefinitionFile(configDir, definitionFile)
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	imageDefinition, err := image.ParseUnresolvedDefinition(configData, configDir)
+	if err != nil {
+		return nil, definitionParseError(configDir, definitionFile, err)
+	}
+
+	return imageDefinition, nil
+}
+
+func migrateDefinitionFile(configDir, definitionFile, apiVersion string) ([]byte, *cmd.Error) {
+	definitionFilePath := filepath.Join(configDir, definitionFile)
+
+	configData, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Reading definition file failed: %v", err),
+		}
+	}
+
+	migrated, steps, err := image.MigrateDefinition(configData, configDir)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition file '%s' could not be migrated.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Migrating definition file failed: %v", err),
+		}
+	}
+
+	if len(steps) == 0 {
+		log.Auditf("The image definition is already at the latest API version %s.", apiVersion)
+		return migrated, nil
+	}
+
+	log.Auditf("Migrated image definition from API version %s to %s:", steps[0].From, steps[len(steps)-1].To)
+
+	for _, step := range steps {
+		log.Auditf("  %s -> %s", step.From, step.To)
+		zap.S().Infof("Migration step %s -> %s: %d changes", step.From, step.To, len(step.Changes))
+
+		if len(step.Changes) == 0 {
+			log.Audit("    No changes required")
+			continue
+		}
+
+		for _, change := range step.Changes {
+			log.Audit("    " + change)
+			zap.S().Info(change)
+		}
+	}
+
+	return migrated, nil
+}
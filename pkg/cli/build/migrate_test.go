@@ -0,0 +1,36 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDefinitionFile_UnresolvedSecrets(t *testing.T) {
+	configDir := t.TempDir()
+
+	definition := `apiVersion: 1.3
+image:
+  imageType: raw
+  arch: x86_64
+  baseImage: base.raw
+  outputImageName: out.raw
+operatingSystem:
+  users:
+    - username: alpha
+      encryptedPassword: ${env:EIB_TEST_MISSING_VARIABLE}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "definition.yaml"), []byte(definition), 0o600))
+
+	imageDefinition, cmdErr := parseUnresolvedDefinitionFile(configDir, "definition.yaml")
+	require.Nil(t, cmdErr)
+	assert.Equal(t, "1.3", imageDefinition.APIVersion)
+
+	migrated, cmdErr := migrateDefinitionFile(configDir, "definition.yaml", imageDefinition.APIVersion)
+	require.Nil(t, cmdErr)
+	assert.Contains(t, string(migrated), "apiVersion: 1.4")
+	assert.Contains(t, string(migrated), "encryptedPassword: ${env:EIB_TEST_MISSING_VARIABLE}")
+}
@@ -0,0 +1,160 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+// dryRun validates the inputs of the build and prints its plan. Neither a build directory nor the default cache
+// directory are set up, the log is written to a temporary file instead.
+func dryRun(c *cli.Context, rootBuildDir string) error {
+	args := &cmd.CommonArgs
+
+	logFile, err := os.CreateTemp("", "eib-dry-run-*.log")
+	if err != nil {
+		log.Audit("The log file of the dry run could not be set up.")
+		return err
+	}
+
+	if err = logFile.Close(); err != nil {
+		log.Audit("The log file of the dry run could not be set up.")
+		return err
+	}
+
+	log.ConfigureGlobalLogger(logFile.Name())
+	checkLogMessage := fmt.Sprintf("Please check the log file '%s' for more information.", logFile.Name())
+
+	var cacheDir string
+	if args.Cache {
+		if cacheDir, err = eib.CacheDirectory(rootBuildDir, args.CacheDir); err != nil {
+			log.Audit("The cache directory could not be found.")
+			return err
+		}
+	}
+
+	ctx := loadBuildContext(c, "", cacheDir, checkLogMessage)
+
+	plan, err := eib.Plan(ctx)
+	if err != nil {
+		log.Auditf("Planning the build failed. %s", checkLogMessage)
+		zap.S().Fatalf("An error occurred planning the build: %s", err)
+	}
+
+	printBuildPlan(plan)
+	return nil
+}
+
+func printBuildPlan(plan *eib.BuildPlan) {
+	log.Audit(formatBuildPlan(plan))
+}
+
+func formatBuildPlan(plan *eib.BuildPlan) string {
+	var builder strings.Builder
+
+	builder.WriteString("Build plan:\n")
+
+	var width int
+	for _, component := range plan.Components {
+		width = max(width, len(component.Name))
+	}
+
+	builder.WriteString("  Combustion components:\n")
+	for _, component := range plan.Components {
+		status := "run"
+		if component.Skipped {
+			status = "skip"
+		}
+		builder.WriteString(fmt.Sprintf("    %-*s  %s\n", width, component.Name, status))
+	}
+
+	if plan.Packages != nil {
+		builder.WriteString("  RPM resolution:\n")
+		writeList(&builder, "Packages", plan.Packages.Packages)
+		writeList(&builder, "Repositories", plan.Packages.Repositories)
+		writeList(&builder, "Side-loaded RPMs", plan.Packages.SideLoadedRPMs)
+		builder.WriteString(fmt.Sprintf("    SUSE registration code provided: %t\n", plan.Packages.RegCodeProvided))
+	}
+
+	if plan.Kubernetes != nil {
+		builder.WriteString(fmt.Sprintf("  Kubernetes artefacts (%s):\n", plan.Kubernetes.Version))
+		for _, artefact := range plan.Kubernetes.Artefacts {
+			status := "download"
+			if artefact.CachedSize != 0 {
+				status = "cached"
+			}
+			builder.WriteString(fmt.Sprintf("    %s (%s)\n", artefact.Name, status))
+		}
+		writeList(&builder, "Manifest URLs", plan.Kubernetes.ManifestURLs)
+	}
+
+	if len(plan.HelmCharts) != 0 {
+		builder.WriteString("  Helm charts:\n")
+		for _, chart := range plan.HelmCharts {
+			builder.WriteString(fmt.Sprintf("    %s %s from %s\n", chart.Name, chart.Version, chart.Repository))
+		}
+	}
+
+	if len(plan.ContainerImages) != 0 {
+		builder.WriteString("  Embedded container images:\n")
+		for _, img := range plan.ContainerImages {
+			builder.WriteString(fmt.Sprintf("    %s\n", img))
+		}
+		if len(plan.HelmCharts) != 0 || (plan.Kubernetes != nil && len(plan.Kubernetes.ManifestURLs) != 0) {
+			builder.WriteString("    Images referenced by Helm charts and remote manifests are determined during the build\n")
+		}
+	}
+
+	builder.WriteString("  Estimated sizes:\n")
+	for _, size := range plan.Sizes {
+		builder.WriteString(fmt.Sprintf("    %-35s %s\n", size.Description+":", formatEstimatedSize(size)))
+	}
+
+	return builder.String()
+}
+
+func writeList(builder *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	builder.WriteString(fmt.Sprintf("    %s:\n", title))
+	for _, item := range items {
+		builder.WriteString(fmt.Sprintf("      %s\n", item))
+	}
+}
+
+func formatEstimatedSize(size eib.SizeEstimate) string {
+	switch {
+	case size.Unknown && size.Size == 0:
+		return "unknown until downloaded"
+	case size.Unknown:
+		return fmt.Sprintf("at least %s", formatSize(size.Size))
+	default:
+		return formatSize(size.Size)
+	}
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value := float64(bytes)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+
+	var i int
+	for value /= unit; value >= unit && i < len(suffixes)-1; i++ {
+		value /= unit
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
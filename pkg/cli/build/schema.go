@@ -0,0 +1,52 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image/schema"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/urfave/cli/v2"
+)
+
+func Schema(c *cli.Context) error {
+	schemaVersion := c.String("schema-version")
+	if schemaVersion == "" {
+		schemaVersion = version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1]
+	}
+
+	s, err := schema.Generate(schemaVersion)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The specified schema version '%s' is not supported. Supported versions: %s.",
+				schemaVersion, strings.Join(version.SupportedSchemaVersions, ", ")),
+		}, "")
+		os.Exit(1)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding schema: %w", err)
+	}
+
+	output := c.String("output")
+	if output == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err = os.WriteFile(output, append(data, '\n'), fileio.NonExecutablePerms); err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The schema could not be written to '%s': %v", output, err),
+		}, "")
+		os.Exit(1)
+	}
+
+	log.Auditf("Schema for API version %s written to '%s'.", schemaVersion, output)
+	return nil
+}
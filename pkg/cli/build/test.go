@@ -0,0 +1,161 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/boottest"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+const consoleLogExtension = ".console.log"
+
+var diskInterfaces = []string{boottest.DiskInterfaceSATA, boottest.DiskInterfaceVirtIO, boottest.DiskInterfaceNVMe}
+
+func Test(c *cli.Context) error {
+	if c.NArg() != 1 {
+		cmd.LogError(&cmd.Error{
+			UserMessage: "Exactly one image to test must be specified.",
+		}, "")
+		os.Exit(1)
+	}
+
+	path := c.Args().First()
+
+	opts, cmdErr := testOptions(c, path)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, "")
+		os.Exit(1)
+	}
+
+	log.Auditf("Booting %s image '%s' using software emulation, this may take a while...", opts.ImageType, filepath.Base(path))
+
+	result, err := boottest.Run(opts)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The virtual machine could not be run: %v", err),
+		}, "")
+		os.Exit(1)
+	}
+
+	for _, script := range result.Scripts {
+		status := "completed"
+		if !script.Completed {
+			status = "did not complete"
+		}
+
+		log.Auditf("Combustion script %s %s.", script.Name, status)
+	}
+
+	if result.KubernetesReady {
+		log.Audit("Kubernetes API server is responding.")
+	}
+
+	log.Auditf("The serial console was captured to: %s", opts.LogPath)
+
+	if !result.Passed {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("Boot test failed: %s.", result.Reason),
+		}, "")
+		os.Exit(1)
+	}
+
+	log.Audit("Boot test passed.")
+	return nil
+}
+
+func testOptions(c *cli.Context, path string) (*boottest.Options, *cmd.Error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The image '%s' could not be read: %v", path, err),
+		}
+	}
+
+	opts := &boottest.Options{
+		ImagePath:       path,
+		Arch:            image.Arch(c.String("arch")),
+		Firmware:        c.String("firmware"),
+		Memory:          c.Int("memory"),
+		CPUs:            c.Int("cpus"),
+		DiskInterface:   c.String("disk-interface"),
+		ScratchDiskSize: c.String("disk-size"),
+		Kubernetes:      c.Bool("kubernetes"),
+		Timeout:         c.Duration("timeout"),
+		LogPath:         c.String("log-file"),
+	}
+
+	if opts.LogPath == "" {
+		opts.LogPath = path + consoleLogExtension
+	}
+
+	metadata, err := output.ReadMetadata(path)
+	switch {
+	case err == nil:
+		if metadata.Compression != "" {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The image is compressed using %s, please decompress it before testing.", metadata.Compression),
+			}
+		}
+
+		opts.ImageType = metadata.Type
+		if opts.Arch == "" {
+			opts.Arch = image.Arch(metadata.Arch)
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		opts.ImageType = imageTypeFromExtension(path)
+	default:
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The metadata of the image could not be read: %v", err),
+		}
+	}
+
+	if opts.Arch == "" {
+		opts.Arch = image.ArchTypeX86
+	}
+
+	if opts.ImageType != image.TypeRAW && opts.ImageType != image.TypeQCOW2 && opts.ImageType != image.TypeISO {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("Only '%s', '%s' and '%s' images can be tested.", image.TypeRAW, image.TypeQCOW2, image.TypeISO),
+		}
+	}
+
+	if opts.Arch != image.ArchTypeX86 && opts.Arch != image.ArchTypeARM {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("Invalid arch '%s': must be either '%s' or '%s'.", opts.Arch, image.ArchTypeX86, image.ArchTypeARM),
+		}
+	}
+
+	if !slices.Contains(diskInterfaces, opts.DiskInterface) {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("Invalid disk interface '%s': must be one of %s.", opts.DiskInterface, strings.Join(diskInterfaces, ", ")),
+		}
+	}
+
+	if opts.ImageType == image.TypeISO && !image.DiskSize(opts.ScratchDiskSize).IsValid() {
+		return nil, &cmd.Error{
+			UserMessage: "The disk size must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+		}
+	}
+
+	return opts, nil
+}
+
+func imageTypeFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".iso":
+		return image.TypeISO
+	case ".qcow2":
+		return image.TypeQCOW2
+	default:
+		return image.TypeRAW
+	}
+}
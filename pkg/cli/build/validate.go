@@ -1,6 +1,7 @@
 package build
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,20 +14,45 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 )
 
 const (
 	checkValidationLogMessage = "Please check the log file under the validation directory for more information."
+
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJUnit = "junit"
+
+	// Exit codes of the validate command
+	exitCodeInvalid  = 1
+	exitCodeInternal = 2
+
+	definitionComponent = "Definition"
 )
 
+var validOutputFormats = []string{outputText, outputJSON, outputJUnit}
+
 func Validate(c *cli.Context) error {
 	args := &cmd.CommonArgs
 	isConfigDrive := c.Bool("config-drive")
 
+	outputFormat := c.String("output")
+	if !slices.Contains(validOutputFormats, outputFormat) {
+		log.AuditError(fmt.Sprintf("The output format '%s' is not supported. Supported formats: %s.",
+			outputFormat, strings.Join(validOutputFormats, ", ")))
+		os.Exit(exitCodeInternal)
+	}
+
+	if outputFormat != outputText {
+		// Keep the standard output reserved for the report
+		log.SetAuditOutput(os.Stderr)
+	}
+
 	validationDir := filepath.Join(args.ConfigDir, "_validation")
 	if err := os.MkdirAll(validationDir, os.ModePerm); err != nil {
 		log.Auditf("The validation directory could not be setup under the configuration directory '%s'.", args.ConfigDir)
-		return err
+		os.Exit(exitCodeInternal)
 	}
 
 	// This needs to occur as early as possible so that the subsequent calls can use the log
@@ -38,15 +64,36 @@ func Validate(c *cli.Context) error {
 
 	if err := imageConfigDirExists(args.ConfigDir); err != nil {
 		cmd.LogError(err, checkValidationLogMessage)
-		os.Exit(1)
+		os.Exit(exitCodeInternal)
 	}
 
 	log.AuditInfo("Parsing definition...")
 
-	imageDefinition, err := parseDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	configData, cmdErr := readDefinitionFile(args.ConfigDir, args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkValidationLogMessage)
+		os.Exit(exitCodeInternal)
+	}
+
+	imageDefinition, err := parseDefinition(args.ConfigDir, configData)
 	if err != nil {
-		cmd.LogError(err, checkValidationLogMessage)
-		os.Exit(1)
+		if outputFormat != outputText {
+			failures := map[string][]validation.FailedValidation{
+				definitionComponent: definitionFailures(args.ConfigDir, args.DefinitionFile, err),
+			}
+			writeValidationReport(outputFormat, args.DefinitionFile, []string{definitionComponent}, failures, args.Strict)
+		} else {
+			cmd.LogError(definitionParseError(args.ConfigDir, args.DefinitionFile, err), checkValidationLogMessage)
+		}
+
+		os.Exit(exitCodeInvalid)
+	}
+
+	if c.Bool("print-definition") {
+		if cmdErr = printResolvedDefinition(args.ConfigDir, args.DefinitionFile); cmdErr != nil {
+			cmd.LogError(cmdErr, checkValidationLogMessage)
+			os.Exit(exitCodeInternal)
+		}
 	}
 
 	ctx := &image.Context{
@@ -61,9 +108,20 @@ func Validate(c *cli.Context) error {
 		log.AuditInfo("Validating image definition...")
 	}
 
-	if err = validateImageDefinition(ctx); err != nil {
-		cmd.LogError(err, checkValidationLogMessage)
-		os.Exit(1)
+	if outputFormat != outputText {
+		failures := validation.ValidateDefinition(ctx)
+		components := append([]string{definitionComponent}, validation.ComponentNames()...)
+
+		if !writeValidationReport(outputFormat, args.DefinitionFile, components, failures, args.Strict) {
+			os.Exit(exitCodeInvalid)
+		}
+
+		return nil
+	}
+
+	if cmdErr = validateImageDefinition(ctx, args.DefinitionFile, args.Strict); cmdErr != nil {
+		cmd.LogError(cmdErr, checkValidationLogMessage)
+		os.Exit(exitCodeInvalid)
 	}
 
 	log.AuditInfo("The specified image definition is valid.")
@@ -71,17 +129,117 @@ func Validate(c *cli.Context) error {
 	return nil
 }
 
-func validateImageDefinition(ctx *image.Context) *cmd.Error {
+// definitionFailures converts an error from parsing the definition into validation failures for the report.
+func definitionFailures(configDir, definitionFile string, err error) []validation.FailedValidation {
+	var unknownFieldsErr *image.UnknownFieldsError
+	if errors.As(err, &unknownFieldsErr) {
+		return validation.UnknownFieldFailures(unknownFieldsErr)
+	}
+
+	return []validation.FailedValidation{
+		{
+			UserMessage: definitionParseError(configDir, definitionFile, err).UserMessage,
+			Error:       err,
+		},
+	}
+}
+
+// writeValidationReport prints the report in the requested format to the standard output and returns whether the definition is valid.
+func writeValidationReport(outputFormat, definitionFile string, components []string, failures map[string][]validation.FailedValidation, strict bool) bool {
+	report := validation.NewReport(definitionFile, components, failures, strict)
+
+	var err error
+	switch outputFormat {
+	case outputJSON:
+		err = report.WriteJSON(os.Stdout)
+	case outputJUnit:
+		err = report.WriteJUnit(os.Stdout)
+	}
+
+	if err != nil {
+		log.AuditError(fmt.Sprintf("The validation report could not be written: %v", err))
+		os.Exit(exitCodeInternal)
+	}
+
+	if report.Valid {
+		log.AuditInfo("The specified image definition is valid.")
+	}
+
+	return report.Valid
+}
+
+func printResolvedDefinition(configDir, definitionFile string) *cmd.Error {
+	definitionFilePath := filepath.Join(configDir, definitionFile)
+
+	configData, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Reading definition file failed: %v", err),
+		}
+	}
+
+	resolved, err := image.ResolveDefinition(configData, configDir)
+	if err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition file '%s' could not be resolved.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Resolving definition file failed: %v", err),
+		}
+	}
+
+	log.Audit("Resolved image definition:")
+	log.Audit(string(resolved))
+
+	return nil
+}
+
+// validateImageDefinition displays any warnings found in the definition and returns an error if it is invalid.
+// Warnings are also considered errors when strict is set.
+func validateImageDefinition(ctx *image.Context, definitionFile string, strict bool) *cmd.Error {
 	failedValidations := validation.ValidateDefinition(ctx)
 	if len(failedValidations) == 0 {
 		return nil
 	}
 
+	errs := map[string][]validation.FailedValidation{}
+	warnings := map[string][]validation.FailedValidation{}
+
+	for componentName, componentFailures := range failedValidations {
+		for _, cf := range componentFailures {
+			if cf.IsWarning() && !strict {
+				warnings[componentName] = append(warnings[componentName], cf)
+			} else {
+				errs[componentName] = append(errs[componentName], cf)
+			}
+		}
+	}
+
+	if len(warnings) > 0 {
+		userMessage, logMessage := formatFailedValidations(definitionFile, warnings,
+			"Image definition validation found the following warnings:\n", "Image definition validation warnings:\n")
+		log.Audit(userMessage)
+		zap.S().Warn(logMessage)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	userMessage, logMessage := formatFailedValidations(definitionFile, errs,
+		"Image definition validation found the following errors:\n", "Image definition validation failures:\n")
+
+	return &cmd.Error{
+		UserMessage: userMessage,
+		LogMessage:  logMessage,
+	}
+}
+
+func formatFailedValidations(definitionFile string, failedValidations map[string][]validation.FailedValidation, userHeader, logHeader string) (userMessage, logMessage string) {
 	logMessageBuilder := strings.Builder{}
 	userMessageBuilder := strings.Builder{}
 
-	userMessageBuilder.WriteString("Image definition validation found the following errors:\n")
-	logMessageBuilder.WriteString("Image definition validation failures:\n")
+	userMessageBuilder.WriteString(userHeader)
+	logMessageBuilder.WriteString(logHeader)
 
 	orderedComponentNames := make([]string, 0, len(failedValidations))
 	for c := range failedValidations {
@@ -93,16 +251,40 @@ func validateImageDefinition(ctx *image.Context) *cmd.Error {
 		userMessageBuilder.WriteString("  " + componentName + "\n")
 
 		for _, cf := range failedValidations[componentName] {
-			userMessageBuilder.WriteString("    " + cf.UserMessage + "\n")
-			logMessageBuilder.WriteString("  " + cf.UserMessage + "\n")
+			message := cf.UserMessage
+			if location := failureLocation(definitionFile, cf); location != "" {
+				message = fmt.Sprintf("%s (%s)", message, location)
+			}
+
+			userMessageBuilder.WriteString("    " + message + "\n")
+			logMessageBuilder.WriteString("  " + message + "\n")
 			if cf.Error != nil {
 				logMessageBuilder.WriteString("    " + cf.Error.Error() + "\n")
 			}
 		}
 	}
 
-	return &cmd.Error{
-		UserMessage: userMessageBuilder.String(),
-		LogMessage:  logMessageBuilder.String(),
+	return userMessageBuilder.String(), logMessageBuilder.String()
+}
+
+// failureLocation describes the field a validation failure refers to along with its position in the definition source.
+func failureLocation(definitionFile string, failure validation.FailedValidation) string {
+	if failure.Field == "" {
+		return ""
 	}
+
+	if !failure.Position.IsKnown() {
+		return failure.Field
+	}
+
+	return fmt.Sprintf("%s at %s", failure.Field, sourcePosition(definitionFile, failure.Position))
+}
+
+func sourcePosition(definitionFile string, position image.Position) string {
+	file := definitionFile
+	if position.File != "" {
+		file = position.File
+	}
+
+	return fmt.Sprintf("%s:%d:%d", file, position.Line, position.Column)
 }
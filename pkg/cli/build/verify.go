@@ -0,0 +1,45 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
+	"github.com/urfave/cli/v2"
+)
+
+func Verify(c *cli.Context) error {
+	if c.NArg() != 1 {
+		cmd.LogError(&cmd.Error{
+			UserMessage: "Exactly one file to verify must be specified.",
+		}, "")
+		os.Exit(1)
+	}
+
+	path := c.Args().First()
+	keyPath := c.String("key")
+
+	if err := output.VerifyChecksum(path); err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The checksum of '%s' could not be verified: %v", path, err),
+		}, "")
+		os.Exit(1)
+	}
+
+	log.Auditf("Checksum of '%s' verified.", filepath.Base(path))
+
+	signaturePath, err := signing.VerifyFile(keyPath, path)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The signature of '%s' could not be verified: %v", path, err),
+		}, "")
+		os.Exit(1)
+	}
+
+	log.Auditf("Signature '%s' verified.", filepath.Base(signaturePath))
+	return nil
+}
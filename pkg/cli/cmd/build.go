@@ -37,6 +37,28 @@ func NewBuildCommand(action func(*cli.Context) error) *cli.Command {
 			BuildDirFlag,
 			CacheDirFlag,
 			CacheFlag,
+			StrictFlag,
+			ProvenanceKeyFlag,
+			SigningKeyFlag,
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "If specified, validates the definition and prints the build plan without downloading anything or building the image",
+			},
+			&cli.BoolFlag{
+				Name: "preflight",
+				Usage: "If specified, runs the combustion scripts in a container built from the base image before building the image, " +
+					"stubbing commands which require a booted system",
+			},
+			&cli.StringFlag{
+				Name: "reuse-build",
+				Usage: "Path to the directory of a previous build, e.g. '_build/build-Jan02_15-04-05', " +
+					"whose output is reused for every combustion component with unchanged inputs",
+			},
+			&cli.StringFlag{
+				Name: "resume",
+				Usage: "Path to the directory of an interrupted build, e.g. '_build/build-Jan02_15-04-05', " +
+					"which is continued from its last completed stage if the inputs have not changed",
+			},
 		},
 	}
 }
@@ -8,6 +8,9 @@ type CommonFlags struct {
 	DefinitionFile string
 	ConfigDir      string
 	RootBuildDir   string
+	Strict         bool
+	ProvenanceKey  string
+	SigningKey     string
 }
 
 var CommonArgs CommonFlags
@@ -41,4 +44,19 @@ var (
 		Usage:       "Full path to the directory to store build artifacts",
 		Destination: &CommonArgs.RootBuildDir,
 	}
+	StrictFlag = &cli.BoolFlag{
+		Name:        "strict",
+		Usage:       "Whether to treat image definition validation warnings as errors",
+		Destination: &CommonArgs.Strict,
+	}
+	ProvenanceKeyFlag = &cli.StringFlag{
+		Name:        "provenance-key",
+		Usage:       "Full path to a PEM encoded ed25519 or ECDSA private key used to sign the provenance attestation",
+		Destination: &CommonArgs.ProvenanceKey,
+	}
+	SigningKeyFlag = &cli.StringFlag{
+		Name:        "signing-key",
+		Usage:       "Full path to a PEM encoded ECDSA or an ASCII armored OpenPGP private key used to sign the output",
+		Destination: &CommonArgs.SigningKey,
+	}
 )
@@ -42,6 +42,9 @@ func NewGenerateCommand(action func(*cli.Context) error) *cli.Command {
 			BuildDirFlag,
 			CacheDirFlag,
 			CacheFlag,
+			StrictFlag,
+			ProvenanceKeyFlag,
+			SigningKeyFlag,
 			&cli.StringFlag{
 				Name:     "output-type",
 				Usage:    "The desired output type",
@@ -58,6 +61,12 @@ func NewGenerateCommand(action func(*cli.Context) error) *cli.Command {
 				Usage:    "The architecture of the generated artifacts",
 				Required: true,
 			},
+			&cli.BoolFlag{
+				Name: "reproducible",
+				Usage: "If specified, normalizes the ordering, timestamps, ownership and permissions of the files in the " +
+					"combustion drive so that identical inputs produce identical drives. Timestamps are taken from " +
+					"SOURCE_DATE_EPOCH if it is set, and default to the Unix epoch otherwise",
+			},
 		},
 	}
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/scaffold"
+	"github.com/urfave/cli/v2"
+)
+
+func NewInitCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Create a new image configuration directory",
+		UsageText: fmt.Sprintf("%s init --preset PRESET [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			ConfigDirFlag,
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: fmt.Sprintf("Layout and definition to create, one of: %s", strings.Join(scaffold.Presets(), ", ")),
+			},
+			&cli.StringFlag{
+				Name:  "definition-file",
+				Usage: "Name of the image definition file to create",
+				Value: "definition.yaml",
+			},
+			&cli.StringFlag{
+				Name:  "arch",
+				Usage: "Architecture of the image, one of: x86_64, aarch64",
+				Value: "x86_64",
+			},
+			&cli.StringFlag{
+				Name: "base-image",
+				Usage: "Full path to a base image which is copied into the configuration directory, or the name of a base image " +
+					"already placed in its 'base-images' directory. Required by all presets except config-drive.",
+			},
+		},
+	}
+}
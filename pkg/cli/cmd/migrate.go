@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewMigrateCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "Upgrade definition to the latest schema version",
+		UsageText: fmt.Sprintf("%s migrate [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+			&cli.StringFlag{
+				Name: "output",
+				Usage: "Name of the file to write the migrated definition to, relative to the image configuration directory. " +
+					"If not specified, the migrated definition is printed to standard output.",
+			},
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewSchemaCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "schema",
+		Usage:     "Generate the JSON schema of the image definition",
+		UsageText: fmt.Sprintf("%s schema [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "schema-version",
+				Usage: "API version of the image definition to generate the schema for. Defaults to the latest supported version.",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Full path to the file to write the schema to. If not specified, the schema is printed to standard output.",
+			},
+		},
+	}
+}
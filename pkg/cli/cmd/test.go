@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewTestCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "test",
+		Usage:     "Boot a built RAW, qcow2 or ISO image in a virtual machine and verify that combustion completes",
+		UsageText: fmt.Sprintf("%s test [OPTIONS] <IMAGE>", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "arch",
+				Usage: "The architecture of the image, read from its metadata file if not specified",
+			},
+			&cli.StringFlag{
+				Name:  "firmware",
+				Usage: "Full path to the firmware to boot the virtual machine with, required for UEFI only images on x86_64",
+			},
+			&cli.IntFlag{
+				Name:  "memory",
+				Usage: "Memory of the virtual machine in MB",
+				Value: 4096,
+			},
+			&cli.IntFlag{
+				Name:  "cpus",
+				Usage: "Number of virtual CPUs",
+				Value: 2,
+			},
+			&cli.StringFlag{
+				Name:  "disk-interface",
+				Usage: "Interface of the virtual disk, one of 'sata' (/dev/sda), 'virtio' (/dev/vda) or 'nvme' (/dev/nvme0n1)",
+				Value: "sata",
+			},
+			&cli.StringFlag{
+				Name:  "disk-size",
+				Usage: "Size of the disk ISO images are installed to",
+				Value: "64G",
+			},
+			&cli.BoolFlag{
+				Name:  "kubernetes",
+				Usage: "If specified, waits for the Kubernetes API server to respond once combustion completed",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum duration of the test, software emulation boots considerably slower than hardware",
+				Value: 60 * time.Minute,
+			},
+			&cli.StringFlag{
+				Name:  "log-file",
+				Usage: "Full path to the file the serial console is captured to, defaults to '<IMAGE>.console.log'",
+			},
+		},
+	}
+}
@@ -15,10 +15,20 @@ func NewValidateCommand(action func(*cli.Context) error) *cli.Command {
 		Flags: []cli.Flag{
 			DefinitionFileFlag,
 			ConfigDirFlag,
+			StrictFlag,
 			&cli.BoolFlag{
 				Name:  "config-drive",
 				Usage: "If specified, validates the input definition for generating a config drive.",
 			},
+			&cli.BoolFlag{
+				Name:  "print-definition",
+				Usage: "If specified, prints the image definition after resolving any base definitions it extends.",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "Format of the validation result, one of: text, json, junit. Structured results are printed to standard output.",
+				Value: "text",
+			},
 		},
 	}
 }
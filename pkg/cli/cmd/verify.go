@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewVerifyCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "verify",
+		Usage:     "Verify the signature and checksum of a built image or config drive",
+		UsageText: fmt.Sprintf("%s verify --key <PUBLIC KEY> <FILE>", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "key",
+				Usage:    "Full path to the PEM encoded ECDSA or ASCII armored OpenPGP public key matching the signing key",
+				Required: true,
+			},
+		},
+	}
+}
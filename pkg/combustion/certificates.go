@@ -23,7 +23,7 @@ const (
 var certsScriptTemplate string
 
 func configureCertificates(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, certsConfigDir) {
+	if skipCertificatesComponent(ctx) {
 		log.AuditComponentSkipped(certsComponentName)
 		zap.S().Info("skipping certificate configuration, no certificates provided")
 		return nil, nil
@@ -43,6 +43,10 @@ func configureCertificates(ctx *image.Context) ([]string, error) {
 	return []string{certsScriptName}, nil
 }
 
+func skipCertificatesComponent(ctx *image.Context) bool {
+	return !isComponentConfigured(ctx, certsConfigDir)
+}
+
 func copyCertificates(ctx *image.Context) error {
 	srcDir := filepath.Join(ctx.ImageConfigDir, certsConfigDir)
 	destDir := filepath.Join(ctx.CombustionDir, certsConfigDir)
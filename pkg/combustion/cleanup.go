@@ -21,9 +21,9 @@ const (
 var cleanupScript string
 
 func configureCleanup(ctx *image.Context) ([]string, error) {
-	if ctx.ImageDefinition.Image.ImageType != image.TypeRAW {
+	if skipCleanupComponent(ctx) {
 		log.AuditComponentSkipped(cleanupComponentName)
-		zap.S().Info("skipping cleanup component, image type is not raw")
+		zap.S().Info("skipping cleanup component, image type is neither raw nor qcow2")
 		return nil, nil
 	}
 
@@ -35,3 +35,8 @@ func configureCleanup(ctx *image.Context) ([]string, error) {
 	log.AuditComponentSuccessful(cleanupComponentName)
 	return []string{cleanupScriptName}, nil
 }
+
+func skipCleanupComponent(ctx *image.Context) bool {
+	imageType := ctx.ImageDefinition.Image.ImageType
+	return imageType != image.TypeRAW && imageType != image.TypeQCOW2
+}
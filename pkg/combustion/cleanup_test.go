@@ -50,3 +50,19 @@ func TestConfigureCleanupISO(t *testing.T) {
 	expectedCombustionScript := filepath.Join(ctx.CombustionDir, cleanupScriptName)
 	assert.NoFileExists(t, expectedCombustionScript)
 }
+
+func TestConfigureCleanupQCOW2(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition.Image.ImageType = image.TypeQCOW2
+
+	// Test
+	scriptNames, err := configureCleanup(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{cleanupScriptName}, scriptNames)
+	assert.FileExists(t, filepath.Join(ctx.CombustionDir, cleanupScriptName))
+}
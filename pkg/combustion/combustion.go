@@ -1,6 +1,7 @@
 package combustion
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -32,12 +33,12 @@ type networkConfiguratorInstaller interface {
 }
 
 type kubernetesScriptDownloader interface {
-	DownloadInstallScript(distribution, destinationPath string) (string, error)
+	DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error)
 }
 
 type kubernetesArtefactDownloader interface {
-	DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error
-	DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error
+	DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error
+	DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error
 }
 
 type rpmResolver interface {
@@ -67,13 +68,29 @@ type Combustion struct {
 	RPMRepoCreator               rpmRepoCreator
 	Registry                     embeddedRegistry
 	ImageDigester                imageDigester
+	// Sandbox runs the assembled scripts as a pre-flight check if set.
+	Sandbox scriptSandbox
 }
 
-// Configure iterates over all separate Combustion components and configures them independently.
-// If all of those are successful, the Combustion script is assembled and written to the file system.
-func (c *Combustion) Configure(ctx *image.Context) error {
-	var combustionScripts []string
+// Component is a single step of the Combustion configuration.
+type Component struct {
+	Name string
+	// skip reports whether the component has nothing to configure. It must not have any side effects,
+	// so that the components of a build can be inspected before running it. Components without it always run.
+	skip func(ctx *image.Context) bool
+	// inputs lists what the component is configured from, so that its output can be reused from a previous
+	// build if they are unchanged. Components without it are always configured.
+	inputs    func(ctx *image.Context) componentInputs
+	configure configureComponent
+}
+
+// Skipped returns whether the component will be skipped when configuring the given context.
+func (c Component) Skipped(ctx *image.Context) bool {
+	return c.skip != nil && c.skip(ctx)
+}
 
+// Components returns all Combustion components in the order they are configured.
+func (c *Combustion) Components() []Component {
 	// EIB Combustion script prefix ranges:
 	// 00-09 -- Networking
 	// 10-19 -- Operating System
@@ -88,97 +105,138 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	//   being able to override/preempt the built-in behavior
 	// - Elemental & SUMA must come after RPMs since the user must provide the
 	//   elemental and venv-salt-minion RPMs manually
-	type componentWrapper struct {
-		name     string
-		runnable configureComponent
-	}
-	combustionComponents := []componentWrapper{
+	// - Cleanup must always be last
+	return []Component{
+		{
+			Name:      messageComponentName,
+			configure: configureMessage,
+		},
 		{
-			name:     messageComponentName,
-			runnable: configureMessage,
+			Name:      customComponentName,
+			skip:      skipCustomFilesComponent,
+			inputs:    customInputs,
+			configure: configureCustomFiles,
 		},
 		{
-			name:     customComponentName,
-			runnable: configureCustomFiles,
+			Name:      timeComponentName,
+			skip:      skipTimeComponent,
+			inputs:    timeInputs,
+			configure: configureTime,
 		},
 		{
-			name:     timeComponentName,
-			runnable: configureTime,
+			Name:      networkComponentName,
+			skip:      skipNetworkComponent,
+			inputs:    networkInputs,
+			configure: c.configureNetwork,
 		},
 		{
-			name:     networkComponentName,
-			runnable: c.configureNetwork,
+			Name:      groupsComponentName,
+			skip:      skipGroupsComponent,
+			inputs:    groupsInputs,
+			configure: configureGroups,
 		},
 		{
-			name:     groupsComponentName,
-			runnable: configureGroups,
+			Name:      usersComponentName,
+			skip:      skipUsersComponent,
+			inputs:    usersInputs,
+			configure: configureUsers,
 		},
 		{
-			name:     usersComponentName,
-			runnable: configureUsers,
+			Name:      proxyComponentName,
+			skip:      skipProxyComponent,
+			inputs:    proxyInputs,
+			configure: configureProxy,
 		},
 		{
-			name:     proxyComponentName,
-			runnable: configureProxy,
+			Name:      rpmComponentName,
+			skip:      SkipRPMComponent,
+			inputs:    rpmInputs,
+			configure: c.configureRPMs,
 		},
 		{
-			name:     rpmComponentName,
-			runnable: c.configureRPMs,
+			Name:      osFilesComponentName,
+			skip:      skipOSFilesComponent,
+			inputs:    osFilesInputs,
+			configure: configureOSFiles,
 		},
 		{
-			name:     osFilesComponentName,
-			runnable: configureOSFiles,
+			Name:      systemdComponentName,
+			skip:      skipSystemdComponent,
+			inputs:    systemdInputs,
+			configure: configureSystemd,
 		},
 		{
-			name:     systemdComponentName,
-			runnable: configureSystemd,
+			Name:      fipsComponentName,
+			skip:      skipFIPSComponent,
+			inputs:    fipsInputs,
+			configure: configureFIPS,
 		},
 		{
-			name:     fipsComponentName,
-			runnable: configureFIPS,
+			Name:      elementalComponentName,
+			skip:      skipElementalComponent,
+			inputs:    elementalInputs,
+			configure: configureElemental,
 		},
 		{
-			name:     elementalComponentName,
-			runnable: configureElemental,
+			Name:      sumaComponentName,
+			skip:      skipSumaComponent,
+			inputs:    sumaInputs,
+			configure: configureSuma,
 		},
 		{
-			name:     sumaComponentName,
-			runnable: configureSuma,
+			Name:      registryComponentName,
+			skip:      skipRegistryComponent,
+			inputs:    registryInputs,
+			configure: c.configureRegistry,
 		},
 		{
-			name:     registryComponentName,
-			runnable: c.configureRegistry,
+			Name:      keymapComponentName,
+			configure: configureKeymap,
 		},
 		{
-			name:     keymapComponentName,
-			runnable: configureKeymap,
+			Name:      k8sComponentName,
+			skip:      skipKubernetesComponent,
+			inputs:    kubernetesInputs,
+			configure: c.configureKubernetes,
 		},
 		{
-			name:     k8sComponentName,
-			runnable: c.configureKubernetes,
+			Name:      certsComponentName,
+			skip:      skipCertificatesComponent,
+			inputs:    certificatesInputs,
+			configure: configureCertificates,
 		},
 		{
-			name:     certsComponentName,
-			runnable: configureCertificates,
+			Name:      cleanupComponentName,
+			skip:      skipCleanupComponent,
+			configure: configureCleanup,
 		},
 	}
+}
 
-	for _, component := range combustionComponents {
-		scripts, err := component.runnable(ctx)
+// Configure iterates over all separate Combustion components and configures them independently.
+// If all of those are successful, the Combustion script is assembled and written to the file system.
+func (c *Combustion) Configure(ctx *image.Context) error {
+	tracker, err := newComponentTracker(ctx)
+	if err != nil {
+		return fmt.Errorf("setting up component tracking: %w", err)
+	}
+
+	var combustionScripts []string
+
+	for _, component := range c.Components() {
+		// Stop before the next component once the build has been cancelled
+		if err = ctx.BuildContext().Err(); err != nil {
+			return fmt.Errorf("configuring component %q: %w", component.Name, err)
+		}
+
+		scripts, err := tracker.configure(component)
 		if err != nil {
-			return fmt.Errorf("configuring component %q: %w", component.name, err)
+			return fmt.Errorf("configuring component %q: %w", component.Name, err)
 		}
 
 		combustionScripts = append(combustionScripts, scripts...)
 	}
 
-	// We manually add the cleanup component as to always make sure it is last
-	s, err := configureCleanup(ctx)
-	if err != nil {
-		return fmt.Errorf("configuring cleanup component %q: %w", cleanupComponentName, err)
-	}
-	combustionScripts = append(combustionScripts, s...)
-
 	var networkScript string
 	if isComponentConfigured(ctx, networkConfigDir) {
 		networkScript = networkConfigScriptName
@@ -194,6 +252,12 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 		return fmt.Errorf("writing script: %w", err)
 	}
 
+	if c.Sandbox != nil {
+		if err = c.runSandbox(ctx); err != nil {
+			return fmt.Errorf("running pre-flight check: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -65,3 +65,75 @@ func TestIsComponentConfigured(t *testing.T) {
 	assert.False(t, isComponentConfigured(ctx, "missing-component"))
 	assert.False(t, isComponentConfigured(ctx, ""))
 }
+
+func TestComponents(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			ImageType: image.TypeRAW,
+		},
+		OperatingSystem: image.OperatingSystem{
+			Users: []image.OperatingSystemUser{
+				{Username: "alice"},
+			},
+			Time: image.Time{
+				Timezone: "Europe/London",
+			},
+		},
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(ctx.ImageConfigDir, certsConfigDir), 0o755))
+
+	c := &Combustion{}
+
+	var names, skipped []string
+	for _, component := range c.Components() {
+		names = append(names, component.Name)
+		if component.Skipped(ctx) {
+			skipped = append(skipped, component.Name)
+		}
+	}
+
+	assert.Equal(t, []string{
+		messageComponentName,
+		customComponentName,
+		timeComponentName,
+		networkComponentName,
+		groupsComponentName,
+		usersComponentName,
+		proxyComponentName,
+		rpmComponentName,
+		osFilesComponentName,
+		systemdComponentName,
+		fipsComponentName,
+		elementalComponentName,
+		sumaComponentName,
+		registryComponentName,
+		keymapComponentName,
+		k8sComponentName,
+		certsComponentName,
+		cleanupComponentName,
+	}, names)
+
+	assert.Equal(t, []string{
+		customComponentName,
+		networkComponentName,
+		groupsComponentName,
+		proxyComponentName,
+		rpmComponentName,
+		osFilesComponentName,
+		systemdComponentName,
+		fipsComponentName,
+		elementalComponentName,
+		sumaComponentName,
+		registryComponentName,
+		k8sComponentName,
+	}, skipped)
+
+	// Inspecting the components must not configure anything
+	entries, err := os.ReadDir(ctx.CombustionDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
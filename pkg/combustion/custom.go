@@ -18,7 +18,7 @@ const (
 )
 
 func configureCustomFiles(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, customDir) {
+	if skipCustomFilesComponent(ctx) {
 		log.AuditComponentSkipped(customComponentName)
 		return nil, nil
 	}
@@ -39,6 +39,10 @@ func configureCustomFiles(ctx *image.Context) ([]string, error) {
 	return scripts, nil
 }
 
+func skipCustomFilesComponent(ctx *image.Context) bool {
+	return !isComponentConfigured(ctx, customDir)
+}
+
 func handleCustomFiles(ctx *image.Context) error {
 	fullFilesDir := generateComponentPath(ctx, filepath.Join(customDir, customFilesDir))
 	err := copyCustomFiles(fullFilesDir, ctx.CombustionDir)
@@ -28,7 +28,7 @@ var (
 )
 
 func configureElemental(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, elementalConfigDir) {
+	if skipElementalComponent(ctx) {
 		log.AuditComponentSkipped(elementalComponentName)
 		zap.S().Info("Skipping elemental registration component, configuration is not provided")
 		return nil, nil
@@ -48,6 +48,10 @@ func configureElemental(ctx *image.Context) ([]string, error) {
 	return []string{elementalScriptName}, nil
 }
 
+func skipElementalComponent(ctx *image.Context) bool {
+	return !isComponentConfigured(ctx, elementalConfigDir)
+}
+
 func copyElementalConfigFile(ctx *image.Context) error {
 	srcFile := filepath.Join(ctx.ImageConfigDir, elementalConfigDir, elementalConfigName)
 	destFile := filepath.Join(ctx.CombustionDir, elementalConfigName)
@@ -24,8 +24,7 @@ var (
 )
 
 func configureFIPS(ctx *image.Context) ([]string, error) {
-	fips := ctx.ImageDefinition.OperatingSystem.EnableFIPS
-	if !fips {
+	if skipFIPSComponent(ctx) {
 		log.AuditComponentSkipped(fipsComponentName)
 		return nil, nil
 	}
@@ -39,6 +38,10 @@ func configureFIPS(ctx *image.Context) ([]string, error) {
 	return []string{fipsScriptName}, nil
 }
 
+func skipFIPSComponent(ctx *image.Context) bool {
+	return !ctx.ImageDefinition.OperatingSystem.EnableFIPS
+}
+
 func writeFIPSCombustionScript(ctx *image.Context) error {
 	fipsScriptFilename := filepath.Join(ctx.CombustionDir, fipsScriptName)
 
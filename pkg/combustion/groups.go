@@ -22,7 +22,7 @@ var groupsScript string
 
 func configureGroups(ctx *image.Context) ([]string, error) {
 	// Punch out early if there are no groups
-	if len(ctx.ImageDefinition.OperatingSystem.Groups) == 0 {
+	if skipGroupsComponent(ctx) {
 		log.AuditComponentSkipped(groupsComponentName)
 		return nil, nil
 	}
@@ -43,3 +43,7 @@ func configureGroups(ctx *image.Context) ([]string, error) {
 	log.AuditComponentSuccessful(groupsComponentName)
 	return []string{groupsScriptName}, nil
 }
+
+func skipGroupsComponent(ctx *image.Context) bool {
+	return len(ctx.ImageDefinition.OperatingSystem.Groups) == 0
+}
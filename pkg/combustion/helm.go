@@ -1,7 +1,14 @@
 package combustion
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 )
 
 func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRepository) {
@@ -58,3 +65,23 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 
 	return charts, repos
 }
+
+// recordHelmChart adds the chart to the SBOM of the image, along with the checksum of its archive.
+func recordHelmChart(ctx *image.Context, chart *registry.HelmCRD) error {
+	archive, err := base64.StdEncoding.DecodeString(chart.Spec.ChartContent)
+	if err != nil {
+		return fmt.Errorf("decoding content of chart '%s': %w", chart.Metadata.Name, err)
+	}
+
+	checksum := sha256.Sum256(archive)
+
+	ctx.SBOM.Add(sbom.Component{
+		Type:    sbom.TypeHelmChart,
+		Name:    chart.Metadata.Name,
+		Version: chart.Spec.Version,
+		SHA256:  hex.EncodeToString(checksum[:]),
+		Source:  chart.RepositoryURL(),
+	})
+
+	return nil
+}
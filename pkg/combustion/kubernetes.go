@@ -3,6 +3,7 @@ package combustion
 import (
 	_ "embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -60,7 +63,7 @@ var (
 func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	version := ctx.ImageDefinition.Kubernetes.Version
 
-	if version == "" {
+	if skipKubernetesComponent(ctx) {
 		log.AuditComponentSkipped(k8sComponentName)
 		return nil, nil
 	}
@@ -75,11 +78,6 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	// is usually taking longer to complete due to downloading files
 	log.Audit("Configuring Kubernetes component...")
 
-	if kubernetes.ServersCount(ctx.ImageDefinition.Kubernetes.Nodes) == 2 {
-		log.Audit("WARNING: Kubernetes clusters consisting of two server nodes cannot form a highly available architecture")
-		zap.S().Warn("Kubernetes cluster of two server nodes has been requested")
-	}
-
 	configDir := generateComponentPath(ctx, k8sDir)
 	configPath := filepath.Join(configDir, k8sConfigDir)
 
@@ -106,10 +104,19 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("configuring kubernetes components: %w", err)
 	}
 
+	if err = recordKubernetesArtefacts(ctx); err != nil {
+		log.AuditComponentFailed(k8sComponentName)
+		return nil, fmt.Errorf("recording kubernetes artefacts: %w", err)
+	}
+
 	log.AuditComponentSuccessful(k8sComponentName)
 	return []string{script}, nil
 }
 
+func skipKubernetesComponent(ctx *image.Context) bool {
+	return ctx.ImageDefinition.Kubernetes.Version == ""
+}
+
 func (c *Combustion) kubernetesConfigurator(version string) func(*image.Context, *kubernetes.Cluster) (string, error) {
 	switch {
 	case strings.Contains(version, image.KubernetesDistroRKE2):
@@ -124,7 +131,7 @@ func (c *Combustion) kubernetesConfigurator(version string) func(*image.Context,
 func (c *Combustion) downloadKubernetesInstallScript(ctx *image.Context, distribution string) (string, error) {
 	path := kubernetesArtefactsPath(ctx)
 
-	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(distribution, path)
+	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(ctx.BuildContext(), distribution, path)
 	if err != nil {
 		return "", fmt.Errorf("downloading install script: %w", err)
 	}
@@ -173,8 +180,6 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		if ctx.ImageDefinition.Kubernetes.Network.APIVIP4 == "" && ctx.ImageDefinition.Kubernetes.Network.APIVIP6 == "" {
 			zap.S().Info("Virtual IP address(es) for k3s cluster not provided and will not be configured")
 		} else {
-			log.Audit("WARNING: Virtual IP address(es) for the k3s cluster provided. " +
-				"An external IP address for the Ingress Controller (Traefik) must be manually configured.")
 			zap.S().Warn("Virtual IP address(es) for k3s cluster requested and will invalidate Traefik configuration")
 		}
 
@@ -183,7 +188,6 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		return storeKubernetesInstaller(ctx, "single-node-k3s", k3sSingleNodeInstaller, templateValues)
 	}
 
-	log.Audit("WARNING: An external IP address for the Ingress Controller (Traefik) must be manually configured in multi-node clusters.")
 	zap.S().Warn("Virtual IP address(es) for k3s cluster necessary for multi node clusters and will invalidate Traefik configuration")
 
 	templateValues["nodes"] = ctx.ImageDefinition.Kubernetes.Nodes
@@ -207,6 +211,7 @@ func (c *Combustion) downloadK3sArtefacts(ctx *image.Context) (binaryPath, image
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadK3sArtefacts(
+		ctx.BuildContext(),
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		installDestination,
@@ -326,6 +331,7 @@ func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernet
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadRKE2Artefacts(
+		ctx.BuildContext(),
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		cni,
@@ -340,6 +346,39 @@ func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernet
 	return prependArtefactPath(installPath), prependArtefactPath(imagesPath), nil
 }
 
+// recordKubernetesArtefacts adds the downloaded installation artefacts and images of the
+// Kubernetes distribution to the SBOM of the image.
+func recordKubernetesArtefacts(ctx *image.Context) error {
+	for _, dir := range []string{k8sInstallDir, k8sImagesDir} {
+		root := filepath.Join(kubernetesArtefactsPath(ctx), dir)
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			checksum, err := output.FileSHA256(path)
+			if err != nil {
+				return fmt.Errorf("calculating checksum of %s: %w", d.Name(), err)
+			}
+
+			ctx.SBOM.Add(sbom.Component{
+				Type:    sbom.TypeKubernetes,
+				Name:    d.Name(),
+				Version: ctx.ImageDefinition.Kubernetes.Version,
+				SHA256:  checksum,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reading %s directory: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
 func kubernetesVIPManifest(k *image.Kubernetes) (string, error) {
 	manifest := struct {
 		APIAddress4 string
@@ -468,6 +507,10 @@ func (c *Combustion) configureManifests(ctx *image.Context) (string, error) {
 			}
 
 			for _, chart := range charts {
+				if err = recordHelmChart(ctx, chart); err != nil {
+					return "", fmt.Errorf("recording helm chart: %w", err)
+				}
+
 				data, err := yaml.Marshal(chart)
 				if err != nil {
 					return "", fmt.Errorf("marshaling helm chart: %w", err)
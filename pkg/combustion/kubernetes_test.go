@@ -1,6 +1,7 @@
 package combustion
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,7 +22,7 @@ type mockKubernetesScriptDownloader struct {
 	downloadScript func(distribution, destPath string) (string, error)
 }
 
-func (m mockKubernetesScriptDownloader) DownloadInstallScript(distribution, destPath string) (string, error) {
+func (m mockKubernetesScriptDownloader) DownloadInstallScript(_ context.Context, distribution, destPath string) (string, error) {
 	if m.downloadScript != nil {
 		return m.downloadScript(distribution, destPath)
 	}
@@ -34,6 +36,7 @@ type mockKubernetesArtefactDownloader struct {
 }
 
 func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
+	_ context.Context,
 	arch image.Arch,
 	version string,
 	cni string,
@@ -49,7 +52,7 @@ func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
 	panic("not implemented")
 }
 
-func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(_ context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if m.downloadK3sArtefacts != nil {
 		return m.downloadK3sArtefacts(arch, version, installPath, imagesPath)
 	}
@@ -285,6 +288,16 @@ func TestConfigureKubernetes_Successful_SingleNode_K3s_IPv4(t *testing.T) {
 	assert.Nil(t, configContents["server"])
 	assert.Equal(t, []any{"192.168.122.100", "api.cluster01.hosted.on.edge.suse.com"}, configContents["tls-san"])
 	assert.Equal(t, []any{"servicelb"}, configContents["disable"])
+
+	// SBOM assertions
+	assert.Equal(t, []sbom.Component{
+		{
+			Type:    sbom.TypeKubernetes,
+			Name:    "cool-k3s-binary",
+			Version: "v1.30.3+k3s1",
+			SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	}, ctx.SBOM.Components())
 }
 
 func TestConfigureKubernetes_Successful_SingleNode_K3s_IPv6(t *testing.T) {
@@ -1372,7 +1385,7 @@ func TestConfigureManifests(t *testing.T) {
 			},
 			helmChartsFunc: func() ([]*registry.HelmCRD, error) {
 				return []*registry.HelmCRD{
-					registry.NewHelmCRD(helmChart, "some-content", `
+					registry.NewHelmCRD(helmChart, "c29tZS1jb250ZW50", `
 values: content`, "oci://registry-1.docker.io/bitnamicharts"),
 				}, nil
 			},
@@ -1408,7 +1421,7 @@ spec:
     version: 10.7.0
     valuesContent: |4-
         values: content
-    chartContent: some-content
+    chartContent: c29tZS1jb250ZW50
     targetNamespace: web
     createNamespace: true
     backOffLimit: 20
@@ -1417,6 +1430,16 @@ spec:
 	require.NoError(t, err)
 
 	assert.Equal(t, chartContent, string(b))
+
+	assert.Equal(t, []sbom.Component{
+		{
+			Type:    sbom.TypeHelmChart,
+			Name:    "apache",
+			Version: "10.7.0",
+			SHA256:  "0a8cac771ca188eacc57e2c96c31f5611925c5ecedccb16b8c236d6c0d325112",
+			Source:  "oci://registry-1.docker.io/bitnamicharts",
+		},
+	}, ctx.SBOM.Components())
 }
 
 func TestConfigureKubernetes_Successful_RKE2Server_WithManifests(t *testing.T) {
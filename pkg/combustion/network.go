@@ -52,7 +52,7 @@ var configureNetworkScript string
 func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err error) {
 	zap.S().Info("Configuring network component...")
 
-	if !isComponentConfigured(ctx, networkConfigDir) {
+	if skipNetworkComponent(ctx) {
 		log.AuditComponentSkipped(networkComponentName)
 		zap.S().Info("Skipping network component, configuration is not provided")
 		return nil, nil
@@ -99,6 +99,10 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 	return scripts, nil
 }
 
+func skipNetworkComponent(ctx *image.Context) bool {
+	return !isComponentConfigured(ctx, networkConfigDir)
+}
+
 func (c *Combustion) generateNetworkConfig(ctx *image.Context) error {
 	const networkConfigLogFile = "network-config.log"
 
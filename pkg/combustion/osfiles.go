@@ -25,7 +25,7 @@ var (
 )
 
 func configureOSFiles(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, osFilesConfigDir) {
+	if skipOSFilesComponent(ctx) {
 		log.AuditComponentSkipped(osFilesComponentName)
 		zap.S().Info("skipping os files component, no files provided")
 		return nil, nil
@@ -45,6 +45,10 @@ func configureOSFiles(ctx *image.Context) ([]string, error) {
 	return []string{osFilesScriptName}, nil
 }
 
+func skipOSFilesComponent(ctx *image.Context) bool {
+	return !isComponentConfigured(ctx, osFilesConfigDir)
+}
+
 func copyOSFiles(ctx *image.Context) error {
 	srcDirectory := filepath.Join(ctx.ImageConfigDir, osFilesConfigDir)
 	destDirectory := filepath.Join(ctx.CombustionDir, osFilesConfigDir)
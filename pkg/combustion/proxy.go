@@ -22,8 +22,7 @@ const (
 var proxyScript string
 
 func configureProxy(ctx *image.Context) ([]string, error) {
-	proxy := ctx.ImageDefinition.OperatingSystem.Proxy
-	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+	if skipProxyComponent(ctx) {
 		log.AuditComponentSkipped(proxyComponentName)
 		return nil, nil
 	}
@@ -37,6 +36,11 @@ func configureProxy(ctx *image.Context) ([]string, error) {
 	return []string{proxyScriptName}, nil
 }
 
+func skipProxyComponent(ctx *image.Context) bool {
+	proxy := ctx.ImageDefinition.OperatingSystem.Proxy
+	return proxy.HTTPProxy == "" && proxy.HTTPSProxy == ""
+}
+
 func writeProxyCombustionScript(ctx *image.Context) error {
 	proxyScriptFilename := filepath.Join(ctx.CombustionDir, proxyScriptName)
 
@@ -1,19 +1,30 @@
 package combustion
 
 import (
+	"archive/tar"
+	"context"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
 
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/klauspost/compress/zstd"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/schollz/progressbar/v3"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -37,7 +48,7 @@ var (
 )
 
 func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
-	if !IsEmbeddedArtifactRegistryConfigured(ctx) {
+	if skipRegistryComponent(ctx) {
 		log.AuditComponentSkipped(registryComponentName)
 		return nil, nil
 	}
@@ -64,20 +75,20 @@ func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	return []string{script}, nil
 }
 
-func storeImage(containerImage, arch string, outputWriter io.Writer) error {
+func storeImage(ctx context.Context, containerImage, arch string, outputWriter io.Writer) error {
 	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", arch)}
 
-	cmd := exec.Command(hauler, args...)
+	cmd := process.Command(ctx, hauler, args...)
 	cmd.Stdout = outputWriter
 	cmd.Stderr = outputWriter
 
 	return cmd.Run()
 }
 
-func generateRegistryTar(imageTarDest string, outputWriter io.Writer) error {
+func generateRegistryTar(ctx context.Context, imageTarDest string, outputWriter io.Writer) error {
 	args := []string{"store", "save", "--filename", imageTarDest}
 
-	cmd := exec.Command(hauler, args...)
+	cmd := process.Command(ctx, hauler, args...)
 	cmd.Stdout = outputWriter
 	cmd.Stderr = outputWriter
 
@@ -92,14 +103,20 @@ func generateRegistryTar(imageTarDest string, outputWriter io.Writer) error {
 	return nil
 }
 
-func loginToRegistry(registry image.Registry, outputWriter io.Writer) error {
-	args := []string{"login", registry.URI, "--username", registry.Authentication.Username, "--password", registry.Authentication.Password}
+func loginToRegistry(ctx context.Context, registry image.Registry, outputWriter io.Writer) error {
+	return loginToRegistryCommand(ctx, registry, outputWriter).Run()
+}
+
+func loginToRegistryCommand(ctx context.Context, registry image.Registry, outputWriter io.Writer) *exec.Cmd {
+	// The password is provided through stdin so that it is not exposed in the process list or logs
+	args := []string{"login", registry.URI, "--username", registry.Authentication.Username, "--password-stdin"}
 
-	cmd := exec.Command(hauler, args...)
+	cmd := process.Command(ctx, hauler, args...)
+	cmd.Stdin = strings.NewReader(registry.Authentication.Password)
 	cmd.Stdout = outputWriter
 	cmd.Stderr = outputWriter
 
-	return cmd.Run()
+	return cmd
 }
 
 func writeRegistryScript(ctx *image.Context) (string, error) {
@@ -127,6 +144,11 @@ func writeRegistryScript(ctx *image.Context) (string, error) {
 	return registryScriptName, nil
 }
 
+// skipRegistryComponent only checks the definition, the component is also skipped if no container images are found.
+func skipRegistryComponent(ctx *image.Context) bool {
+	return !IsEmbeddedArtifactRegistryConfigured(ctx)
+}
+
 func IsEmbeddedArtifactRegistryConfigured(ctx *image.Context) bool {
 	return len(ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages) != 0 ||
 		len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 ||
@@ -232,19 +254,21 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 	const registryLogFileName = "embedded-registry.log"
 	logFilename := filepath.Join(ctx.BuildDir, registryLogFileName)
 
-	logFile, err := os.OpenFile(logFilename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileio.NonExecutablePerms)
+	file, err := os.OpenFile(logFilename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileio.NonExecutablePerms)
 	if err != nil {
 		return fmt.Errorf("opening registry log file: %w", err)
 	}
 
 	defer func() {
-		if err = logFile.Close(); err != nil {
+		if err = file.Close(); err != nil {
 			zap.S().Warnf("Failed to close registry log file properly: %v", err)
 		}
 	}()
 
+	logFile := log.NewRedactingWriter(file)
+
 	for _, registry := range ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries {
-		if err = loginToRegistry(registry, logFile); err != nil {
+		if err = loginToRegistry(ctx.BuildContext(), registry, logFile); err != nil {
 			return fmt.Errorf("logging into registry '%s': %w", registry.URI, err)
 		}
 	}
@@ -259,11 +283,13 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 		cacheImage := enableCache
 		convertedImage := strings.ReplaceAll(img, "/", "_")
 		convertedImageName := fmt.Sprintf("%s-%s", convertedImage, registryTarSuffix)
+
+		// The digest of latest images identifies them in the cache, all other digests are read from the archives
+		var digest string
 		if strings.Contains(img, ":latest") {
-			var digest string
-			digest, err = c.ImageDigester.ImageDigest(img, arch)
-			if err != nil {
-				zap.S().Warnf("Failed getting digest for %s: %s", img, err)
+			var digestErr error
+			if digest, digestErr = c.containerImageDigest(img, arch); digestErr != nil {
+				zap.S().Warnf("Failed getting digest for %s: %s", img, digestErr)
 				cacheImage = false
 			} else {
 				convertedImageName = fmt.Sprintf("%s-%s-%s", convertedImage, digest, registryTarSuffix)
@@ -285,11 +311,11 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 				return fmt.Errorf("copying cached container image: %w", err)
 			}
 		} else {
-			if err = storeImage(img, arch, logFile); err != nil {
+			if err = storeImage(ctx.BuildContext(), img, arch, logFile); err != nil {
 				return fmt.Errorf("adding image to registry store: %w", err)
 			}
 
-			if err = generateRegistryTar(imageTarDest, logFile); err != nil {
+			if err = generateRegistryTar(ctx.BuildContext(), imageTarDest, logFile); err != nil {
 				return fmt.Errorf("generating registry store tarball: %w", err)
 			}
 
@@ -299,6 +325,15 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 				}
 			}
 		}
+		if digest == "" {
+			var digestErr error
+			if digest, digestErr = archivedImageDigest(imageTarDest); digestErr != nil {
+				zap.S().Debugf("Reading digest of %s from its registry archive failed: %s", img, digestErr)
+			}
+		}
+
+		recordContainerImage(ctx, img, digest)
+
 		if err = bar.Add(1); err != nil {
 			zap.S().Debugf("Error incrementing the progress bar: %s", err)
 		}
@@ -317,3 +352,82 @@ func (c *Combustion) populateRegistry(ctx *image.Context, images []string) error
 
 	return nil
 }
+
+// containerImageDigest returns the manifest digest of the image for the given architecture without the
+// algorithm prefix. Images referenced by digest are not inspected.
+func (c *Combustion) containerImageDigest(img, arch string) (string, error) {
+	if _, digest, found := strings.Cut(img, "@sha256:"); found {
+		return digest, nil
+	}
+
+	return c.ImageDigester.ImageDigest(img, arch)
+}
+
+// archivedImageDigest reads the manifest digest of the single image stored in a registry archive created by Hauler.
+func archivedImageDigest(archivePath string) (string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("opening archive: %w", err)
+	}
+	defer file.Close()
+
+	decoder, err := zstd.NewReader(file)
+	if err != nil {
+		return "", fmt.Errorf("creating zstd reader: %w", err)
+	}
+	defer decoder.Close()
+
+	tr := tar.NewReader(decoder)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("index not found in archive")
+		} else if err != nil {
+			return "", fmt.Errorf("reading archive: %w", err)
+		}
+
+		if path.Clean(header.Name) != imgspecv1.ImageIndexFile {
+			continue
+		}
+
+		var index imgspecv1.Index
+		if err = json.NewDecoder(tr).Decode(&index); err != nil {
+			return "", fmt.Errorf("decoding index: %w", err)
+		}
+
+		var digests []string
+		for _, m := range index.Manifests {
+			if m.MediaType == imgspecv1.MediaTypeImageManifest || m.MediaType == manifest.DockerV2Schema2MediaType {
+				digests = append(digests, m.Digest.Encoded())
+			}
+		}
+
+		if len(digests) != 1 {
+			return "", fmt.Errorf("expected a single image manifest, found %d", len(digests))
+		}
+
+		return digests[0], nil
+	}
+}
+
+// recordContainerImage adds the image to the SBOM of the image. The digest is omitted if it is not known.
+func recordContainerImage(ctx *image.Context, img, digest string) {
+	component := sbom.Component{
+		Type: sbom.TypeContainerImage,
+		Name: img,
+	}
+
+	if named, err := reference.ParseNormalizedNamed(img); err == nil {
+		component.Name = named.Name()
+
+		if tagged, ok := named.(reference.Tagged); ok {
+			component.Version = tagged.Tag()
+		}
+	}
+
+	if digest != "" {
+		component.Digest = "sha256:" + digest
+	}
+
+	ctx.SBOM.Add(component)
+}
@@ -1,15 +1,34 @@
 package combustion
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 )
 
+type mockImageDigester struct {
+	imageDigest func(img, arch string) (string, error)
+}
+
+func (m mockImageDigester) ImageDigest(img, arch string) (string, error) {
+	if m.imageDigest != nil {
+		return m.imageDigest(img, arch)
+	}
+
+	panic("not implemented")
+}
+
 func TestWriteRegistryScript(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
@@ -34,6 +53,28 @@ func TestWriteRegistryScript(t *testing.T) {
 	assert.Contains(t, found, "ExecStart=/opt/hauler/start-registry.sh")
 }
 
+func TestLoginToRegistryCommand(t *testing.T) {
+	registry := image.Registry{
+		URI: "registry.suse.com",
+		Authentication: image.RegistryAuthentication{
+			Username: "user",
+			Password: "pass",
+		},
+	}
+
+	var buf bytes.Buffer
+	cmd := loginToRegistryCommand(context.Background(), registry, &buf)
+
+	expectedArgs := []string{"hauler", "login", "registry.suse.com", "--username", "user", "--password-stdin"}
+	assert.Equal(t, expectedArgs, cmd.Args)
+	assert.Equal(t, &buf, cmd.Stdout)
+	assert.Equal(t, &buf, cmd.Stderr)
+
+	password, err := io.ReadAll(cmd.Stdin)
+	require.NoError(t, err)
+	assert.Equal(t, "pass", string(password))
+}
+
 func TestIsEmbeddedArtifactRegistryConfigured(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -180,3 +221,101 @@ func TestGetImageHostnames(t *testing.T) {
 	// Verify
 	assert.Equal(t, expectedHostnames, hostnames)
 }
+
+func TestContainerImageDigest(t *testing.T) {
+	c := Combustion{
+		ImageDigester: mockImageDigester{
+			imageDigest: func(img, arch string) (string, error) {
+				if img == "hello-world:latest" && arch == "amd64" {
+					return "abc123", nil
+				}
+
+				return "", fmt.Errorf("image is not built for linux/%s", arch)
+			},
+		},
+	}
+
+	digest, err := c.containerImageDigest("hello-world:latest", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", digest)
+
+	digest, err = c.containerImageDigest("quay.io/podman/hello@sha256:def456", "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", digest)
+
+	_, err = c.containerImageDigest("hello-world:latest", "arm64")
+	assert.EqualError(t, err, "image is not built for linux/arm64")
+}
+
+func TestRecordContainerImage(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	recordContainerImage(ctx, "hello-world:latest", "abc123")
+	recordContainerImage(ctx, "rgcrprod.azurecr.us/longhornio/longhorn-ui:v1.5.1", "")
+
+	assert.Equal(t, []sbom.Component{
+		{
+			Type:    sbom.TypeContainerImage,
+			Name:    "docker.io/library/hello-world",
+			Version: "latest",
+			Digest:  "sha256:abc123",
+		},
+		{
+			Type:    sbom.TypeContainerImage,
+			Name:    "rgcrprod.azurecr.us/longhornio/longhorn-ui",
+			Version: "v1.5.1",
+		},
+	}, ctx.SBOM.Components())
+}
+
+func writeRegistryArchive(t *testing.T, index string) string {
+	archivePath := filepath.Join(t.TempDir(), "hello-world-registry.tar.zst")
+
+	file, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	encoder, err := zstd.NewWriter(file)
+	require.NoError(t, err)
+
+	tw := tar.NewWriter(encoder)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "oci-layout", Mode: 0o644, Size: 2}))
+	_, err = tw.Write([]byte("{}"))
+	require.NoError(t, err)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "index.json", Mode: 0o644, Size: int64(len(index))}))
+	_, err = tw.Write([]byte(index))
+	require.NoError(t, err)
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, encoder.Close())
+
+	return archivePath
+}
+
+func TestArchivedImageDigest(t *testing.T) {
+	archivePath := writeRegistryArchive(t, `{
+  "schemaVersion": 2,
+  "manifests": [
+    {
+      "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+      "digest": "sha256:3dfc05677ed97fdf620a3af556d6fe44ec3747262cbf1b4c0c20eed284fd7290",
+      "size": 1156,
+      "annotations": {"io.containerd.image.name": "docker.io/library/hello-world:latest"}
+    }
+  ]
+}`)
+
+	digest, err := archivedImageDigest(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, "3dfc05677ed97fdf620a3af556d6fe44ec3747262cbf1b4c0c20eed284fd7290", digest)
+
+	archivePath = writeRegistryArchive(t, `{"schemaVersion": 2, "manifests": []}`)
+
+	_, err = archivedImageDigest(archivePath)
+	assert.EqualError(t, err, "expected a single image manifest, found 0")
+
+	_, err = archivedImageDigest(filepath.Join(t.TempDir(), "missing.tar.zst"))
+	assert.ErrorContains(t, err, "opening archive")
+}
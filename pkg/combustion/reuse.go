@@ -0,0 +1,493 @@
+package combustion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"go.uber.org/zap"
+)
+
+const componentsManifestName = "components.json"
+
+// componentInputs describes everything a component is configured from. The output of a component
+// whose inputs are unchanged since a previous build is reused instead of configuring it again.
+type componentInputs struct {
+	// Definition is the part of the image definition the component reads.
+	Definition any
+	// Paths are the files and directories the component reads, e.g. its image configuration directory.
+	Paths []string
+}
+
+// componentRecord describes the output of a single component of a build.
+type componentRecord struct {
+	Name string `json:"name"`
+	// Hash of the component inputs, empty if the component is never reused.
+	Hash    string   `json:"hash,omitempty"`
+	Scripts []string `json:"scripts,omitempty"`
+	// Files written by the component, relative to the build directory.
+	Files     []string         `json:"files,omitempty"`
+	SBOM      []sbom.Component `json:"sbom,omitempty"`
+	Downloads []http.Download  `json:"downloads,omitempty"`
+}
+
+type componentsManifest struct {
+	Components []componentRecord `json:"components"`
+	// Downloads lists every file downloaded by the build so far, including those made
+	// before the components were configured.
+	Downloads []http.Download `json:"downloads,omitempty"`
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+// componentTracker records the output of every component in the manifest of the build. Components
+// whose inputs match the build the context is reusing are not configured, but copied from that build.
+type componentTracker struct {
+	ctx      *image.Context
+	previous map[string]componentRecord
+	manifest componentsManifest
+}
+
+func newComponentTracker(ctx *image.Context) (*componentTracker, error) {
+	t := &componentTracker{ctx: ctx}
+
+	if ctx.ReuseBuildDir == "" {
+		return t, nil
+	}
+
+	manifest, err := readComponentsManifest(filepath.Join(ctx.ReuseBuildDir, componentsManifestName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.AuditInfof("The build directory '%s' does not list its components, all components will be configured.", ctx.ReuseBuildDir)
+			return t, nil
+		}
+
+		return nil, fmt.Errorf("reading components of the previous build: %w", err)
+	}
+
+	t.previous = map[string]componentRecord{}
+	for _, record := range manifest.Components {
+		t.previous[record.Name] = record
+	}
+
+	return t, nil
+}
+
+// configure either reuses the output of the component from the previous build or configures it,
+// recording which scripts, files, SBOM entries and downloads it produced.
+func (t *componentTracker) configure(component Component) ([]string, error) {
+	record := componentRecord{Name: component.Name}
+
+	if component.inputs != nil && !component.Skipped(t.ctx) {
+		hash, err := hashInputs(component.Name, component.inputs(t.ctx))
+		if err != nil {
+			return nil, fmt.Errorf("hashing inputs: %w", err)
+		}
+		record.Hash = hash
+
+		if previous, ok := t.previous[component.Name]; ok && previous.Hash == hash {
+			reused, err := t.reuse(&previous)
+			if err != nil {
+				return nil, fmt.Errorf("reusing output of the previous build: %w", err)
+			}
+
+			if reused {
+				log.AuditComponentReused(component.Name)
+				t.ctx.SBOM.Add(previous.SBOM...)
+				http.RestoreDownloads(previous.Downloads...)
+				return previous.Scripts, t.record(&previous)
+			}
+		}
+	}
+
+	before, err := snapshotOutput(t.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing build output: %w", err)
+	}
+	recorded := t.ctx.SBOM.Len()
+	downloaded := len(http.Downloads())
+
+	scripts, err := component.configure(t.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := snapshotOutput(t.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing build output: %w", err)
+	}
+
+	record.Scripts = scripts
+	record.Files = changedFiles(before, after)
+	record.SBOM = t.ctx.SBOM.Since(recorded)
+	record.Downloads = http.Downloads()[downloaded:]
+
+	return scripts, t.record(&record)
+}
+
+// reuse copies the files of the component from the previous build. Nothing is copied if any of them
+// is missing, in which case the component has to be configured again.
+func (t *componentTracker) reuse(record *componentRecord) (bool, error) {
+	for _, file := range record.Files {
+		if _, err := os.Lstat(filepath.Join(t.ctx.ReuseBuildDir, file)); err != nil {
+			zap.S().Infof("Output of component %q can not be reused, '%s' is not available: %s", record.Name, file, err)
+			return false, nil
+		}
+	}
+
+	for _, file := range record.Files {
+		dest := filepath.Join(t.ctx.BuildDir, file)
+		link := strings.HasPrefix(dest, t.ctx.ArtefactsDir+string(filepath.Separator))
+
+		if err := reuseFile(filepath.Join(t.ctx.ReuseBuildDir, file), dest, link); err != nil {
+			return false, fmt.Errorf("reusing '%s': %w", file, err)
+		}
+	}
+
+	return true, nil
+}
+
+// record writes the manifest after every component, so that a failed build can still be reused.
+func (t *componentTracker) record(record *componentRecord) error {
+	t.manifest.Components = append(t.manifest.Components, *record)
+	t.manifest.Downloads = http.Downloads()
+
+	data, err := json.MarshalIndent(t.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding components manifest: %w", err)
+	}
+
+	filename := filepath.Join(t.ctx.BuildDir, componentsManifestName)
+	if err = os.WriteFile(filename, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing components manifest: %w", err)
+	}
+
+	return nil
+}
+
+// RecordedSBOM returns the SBOM entries collected by the components of the build in the given directory.
+func RecordedSBOM(buildDir string) ([]sbom.Component, error) {
+	manifest, err := readComponentsManifest(filepath.Join(buildDir, componentsManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("reading components manifest: %w", err)
+	}
+
+	var components []sbom.Component
+	for _, record := range manifest.Components {
+		components = append(components, record.SBOM...)
+	}
+
+	return components, nil
+}
+
+// RecordedDownloads returns the files downloaded by the build in the given directory
+// up to the last component it configured.
+func RecordedDownloads(buildDir string) ([]http.Download, error) {
+	manifest, err := readComponentsManifest(filepath.Join(buildDir, componentsManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("reading components manifest: %w", err)
+	}
+
+	return manifest.Downloads, nil
+}
+
+func readComponentsManifest(path string) (*componentsManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest componentsManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
+
+// hashInputs calculates a digest of the component inputs. The EIB version is included,
+// since the output of a component may change between releases even if its inputs do not.
+func hashInputs(name string, inputs componentInputs) (string, error) {
+	h := sha256.New()
+
+	if _, err := fmt.Fprintf(h, "%s\n%s\n", name, version.GetEibVersion()); err != nil {
+		return "", err
+	}
+
+	definition, err := json.Marshal(inputs.Definition)
+	if err != nil {
+		return "", fmt.Errorf("encoding definition: %w", err)
+	}
+
+	if _, err = h.Write(definition); err != nil {
+		return "", err
+	}
+
+	for i, path := range inputs.Paths {
+		if err = hashPath(h, i, path); err != nil {
+			return "", fmt.Errorf("hashing '%s': %w", path, err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashPath writes the name, mode and contents of every file under root to the hash.
+// The location of root itself is left out, so that moving the configuration directory
+// does not invalidate the previous builds.
+func hashPath(h io.Writer, index int, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				_, err = fmt.Fprintf(h, "%d missing\n", index)
+			}
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintf(h, "%d %s %s\n", index, rel, info.Mode()); err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(h, target)
+			return err
+		case info.Mode().IsRegular():
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			_, err = io.Copy(h, file)
+			return err
+		default:
+			return nil
+		}
+	})
+}
+
+// fileStamp identifies a file by its size and modification time for files too large to be hashed,
+// such as the base image.
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+}
+
+// snapshotOutput lists the state of the files in the combustion and artefacts directories,
+// relative to the build directory.
+func snapshotOutput(ctx *image.Context) (map[string]fileState, error) {
+	files := map[string]fileState{}
+
+	for _, dir := range []string{ctx.CombustionDir, ctx.ArtefactsDir} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if path == dir {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(ctx.BuildDir, path)
+			if err != nil {
+				return err
+			}
+
+			files[rel] = fileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// changedFiles returns the files which were created or modified between the snapshots in lexical
+// order, so that directories precede their contents. Directories are only considered if they are new.
+func changedFiles(before, after map[string]fileState) []string {
+	var changed []string
+
+	for path, state := range after {
+		previous, ok := before[path]
+		switch {
+		case !ok:
+			changed = append(changed, path)
+		case state.mode.IsDir():
+		case previous != state:
+			changed = append(changed, path)
+		}
+	}
+
+	slices.Sort(changed)
+	return changed
+}
+
+// reuseFile copies a file from the previous build. Artefacts are hard linked where possible since they
+// may be large, while the smaller combustion files are always copied.
+func reuseFile(src, dest string, link bool) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return os.MkdirAll(dest, info.Mode().Perm())
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err = os.Remove(dest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+
+		return os.Symlink(target, dest)
+	}
+
+	if link {
+		if err = os.Link(src, dest); err == nil {
+			return nil
+		}
+
+		zap.S().Debugf("Hard linking '%s' failed, copying it instead: %s", src, err)
+	}
+
+	return fileio.CopyFile(src, dest, info.Mode().Perm())
+}
+
+func customInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Paths: []string{generateComponentPath(ctx, customDir)}}
+}
+
+func timeInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Time}
+}
+
+func networkInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Paths: []string{generateComponentPath(ctx, networkConfigDir)}}
+}
+
+func groupsInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Groups}
+}
+
+func usersInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Users}
+}
+
+func proxyInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Proxy}
+}
+
+// rpmInputs includes the base image since the packages are resolved against it.
+func rpmInputs(ctx *image.Context) componentInputs {
+	baseImage := ctx.ImageDefinition.Image.BaseImage
+
+	return componentInputs{
+		Definition: []any{
+			ctx.ImageDefinition.OperatingSystem.Packages,
+			ctx.ImageDefinition.Image.Arch,
+			baseImage,
+			fileStamp(filepath.Join(ctx.ImageConfigDir, "base-images", baseImage)),
+		},
+		Paths: []string{RPMsPath(ctx)},
+	}
+}
+
+func osFilesInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Paths: []string{generateComponentPath(ctx, osFilesConfigDir)}}
+}
+
+func systemdInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Systemd}
+}
+
+func fipsInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.EnableFIPS}
+}
+
+func elementalInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Paths: []string{ElementalPath(ctx)}}
+}
+
+func sumaInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Definition: ctx.ImageDefinition.OperatingSystem.Suma}
+}
+
+func registryInputs(ctx *image.Context) componentInputs {
+	return componentInputs{
+		Definition: []any{
+			ctx.ImageDefinition.EmbeddedArtifactRegistry,
+			ctx.ImageDefinition.Kubernetes,
+			ctx.ImageDefinition.Image.Arch,
+		},
+		Paths: []string{generateComponentPath(ctx, k8sDir), ctx.ArtifactSourcesFile},
+	}
+}
+
+func kubernetesInputs(ctx *image.Context) componentInputs {
+	return componentInputs{
+		Definition: []any{
+			ctx.ImageDefinition.Kubernetes,
+			ctx.ImageDefinition.Image.Arch,
+		},
+		Paths: []string{generateComponentPath(ctx, k8sDir), ctx.ArtifactSourcesFile},
+	}
+}
+
+func certificatesInputs(ctx *image.Context) componentInputs {
+	return componentInputs{Paths: []string{generateComponentPath(ctx, certsConfigDir)}}
+}
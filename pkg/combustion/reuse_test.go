@@ -0,0 +1,236 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+)
+
+func setupReuseContext(t *testing.T, configDir string) *image.Context {
+	buildDir := t.TempDir()
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		BuildDir:       buildDir,
+		CombustionDir:  filepath.Join(buildDir, "combustion"),
+		ArtefactsDir:   filepath.Join(buildDir, "artefacts"),
+		ImageDefinition: &image.Definition{
+			OperatingSystem: image.OperatingSystem{
+				Time: image.Time{Timezone: "Europe/London"},
+			},
+		},
+	}
+
+	require.NoError(t, os.MkdirAll(ctx.CombustionDir, 0o755))
+	require.NoError(t, os.MkdirAll(ctx.ArtefactsDir, 0o755))
+
+	return ctx
+}
+
+var testDownload = http.Download{URL: "https://example.com/test", SHA256: "c1"}
+
+// testComponent writes a script and an artefact from the time zone and the files in the "test" configuration dir.
+func testComponent(configured *int) Component {
+	return Component{
+		Name: "test",
+		inputs: func(ctx *image.Context) componentInputs {
+			return componentInputs{
+				Definition: ctx.ImageDefinition.OperatingSystem.Time,
+				Paths:      []string{generateComponentPath(ctx, "test")},
+			}
+		},
+		configure: func(ctx *image.Context) ([]string, error) {
+			*configured++
+
+			script := filepath.Join(ctx.CombustionDir, "10-test.sh")
+			if err := os.WriteFile(script, []byte(ctx.ImageDefinition.OperatingSystem.Time.Timezone), 0o744); err != nil {
+				return nil, err
+			}
+
+			artefactsDir := filepath.Join(ctx.ArtefactsDir, "test")
+			if err := os.MkdirAll(artefactsDir, 0o755); err != nil {
+				return nil, err
+			}
+
+			if err := os.WriteFile(filepath.Join(artefactsDir, "data"), []byte("data"), 0o644); err != nil {
+				return nil, err
+			}
+
+			ctx.SBOM.Add(sbom.Component{Type: sbom.TypePackage, Name: "test", Version: "1.0"})
+			http.RestoreDownloads(testDownload)
+
+			return []string{"10-test.sh"}, nil
+		},
+	}
+}
+
+func TestComponentTracker(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "test"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "test", "config"), []byte("a"), 0o600))
+
+	var configured int
+	component := testComponent(&configured)
+
+	configureComponent := func(ctx *image.Context) []string {
+		tracker, err := newComponentTracker(ctx)
+		require.NoError(t, err)
+
+		scripts, err := tracker.configure(component)
+		require.NoError(t, err)
+
+		return scripts
+	}
+
+	first := setupReuseContext(t, configDir)
+	assert.Equal(t, []string{"10-test.sh"}, configureComponent(first))
+	assert.Equal(t, 1, configured)
+
+	manifest, err := readComponentsManifest(filepath.Join(first.BuildDir, componentsManifestName))
+	require.NoError(t, err)
+	require.Len(t, manifest.Components, 1)
+
+	record := manifest.Components[0]
+	assert.Equal(t, "test", record.Name)
+	assert.NotEmpty(t, record.Hash)
+	assert.Equal(t, []string{"10-test.sh"}, record.Scripts)
+	assert.Equal(t, []string{"artefacts/test", "artefacts/test/data", "combustion/10-test.sh"}, record.Files)
+	assert.Equal(t, []sbom.Component{{Type: sbom.TypePackage, Name: "test", Version: "1.0"}}, record.SBOM)
+	assert.Equal(t, []http.Download{testDownload}, record.Downloads)
+	assert.Contains(t, manifest.Downloads, testDownload)
+
+	t.Run("Unchanged inputs", func(t *testing.T) {
+		ctx := setupReuseContext(t, configDir)
+		ctx.ReuseBuildDir = first.BuildDir
+
+		assert.Equal(t, []string{"10-test.sh"}, configureComponent(ctx))
+		assert.Equal(t, 1, configured)
+		assert.Equal(t, record.SBOM, ctx.SBOM.Components())
+
+		script, err := os.Stat(filepath.Join(ctx.CombustionDir, "10-test.sh"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o744), script.Mode().Perm())
+
+		data, err := os.ReadFile(filepath.Join(ctx.ArtefactsDir, "test", "data"))
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(data))
+
+		reused, err := readComponentsManifest(filepath.Join(ctx.BuildDir, componentsManifestName))
+		require.NoError(t, err)
+		assert.Equal(t, manifest.Components, reused.Components)
+
+		recorded, err := RecordedSBOM(ctx.BuildDir)
+		require.NoError(t, err)
+		assert.Equal(t, record.SBOM, recorded)
+
+		downloads, err := RecordedDownloads(ctx.BuildDir)
+		require.NoError(t, err)
+		assert.Equal(t, http.Downloads(), downloads)
+		assert.Contains(t, downloads, testDownload)
+	})
+
+	t.Run("Changed definition", func(t *testing.T) {
+		configured = 1
+
+		ctx := setupReuseContext(t, configDir)
+		ctx.ReuseBuildDir = first.BuildDir
+		ctx.ImageDefinition.OperatingSystem.Time.Timezone = "Europe/Berlin"
+
+		configureComponent(ctx)
+		assert.Equal(t, 2, configured)
+	})
+
+	t.Run("Missing output", func(t *testing.T) {
+		configured = 1
+
+		previous := setupReuseContext(t, configDir)
+		configureComponent(previous)
+		require.NoError(t, os.RemoveAll(filepath.Join(previous.ArtefactsDir, "test")))
+
+		ctx := setupReuseContext(t, configDir)
+		ctx.ReuseBuildDir = previous.BuildDir
+
+		configureComponent(ctx)
+		assert.Equal(t, 3, configured)
+	})
+
+	t.Run("No manifest", func(t *testing.T) {
+		configured = 1
+
+		ctx := setupReuseContext(t, configDir)
+		ctx.ReuseBuildDir = t.TempDir()
+
+		configureComponent(ctx)
+		assert.Equal(t, 2, configured)
+	})
+}
+
+func TestHashInputs(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config"), []byte("a"), 0o600))
+
+	inputs := componentInputs{
+		Definition: image.Time{Timezone: "Europe/London"},
+		Paths:      []string{configDir, filepath.Join(configDir, "missing")},
+	}
+
+	hash, err := hashInputs("time", inputs)
+	require.NoError(t, err)
+
+	movedDir := filepath.Join(t.TempDir(), "moved")
+	require.NoError(t, os.Rename(configDir, movedDir))
+
+	moved, err := hashInputs("time", componentInputs{
+		Definition: inputs.Definition,
+		Paths:      []string{movedDir, filepath.Join(movedDir, "missing")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hash, moved, "moving the configuration dir must not change the hash")
+
+	otherComponent, err := hashInputs("network", componentInputs{
+		Definition: inputs.Definition,
+		Paths:      []string{movedDir, filepath.Join(movedDir, "missing")},
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherComponent)
+
+	require.NoError(t, os.WriteFile(filepath.Join(movedDir, "config"), []byte("b"), 0o600))
+
+	changed, err := hashInputs("time", componentInputs{
+		Definition: inputs.Definition,
+		Paths:      []string{movedDir, filepath.Join(movedDir, "missing")},
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+}
+
+func TestChangedFiles(t *testing.T) {
+	modTime := time.Unix(1000, 0)
+
+	before := map[string]fileState{
+		"combustion/10-a.sh": {size: 1, modTime: modTime, mode: 0o744},
+		"combustion/20-b.sh": {size: 1, modTime: modTime, mode: 0o744},
+		"artefacts/rpms":     {modTime: modTime, mode: os.ModeDir | 0o755},
+	}
+
+	after := map[string]fileState{
+		"combustion/10-a.sh":   {size: 1, modTime: modTime, mode: 0o744},
+		"combustion/20-b.sh":   {size: 2, modTime: modTime.Add(time.Second), mode: 0o744},
+		"combustion/30-c.sh":   {size: 1, modTime: modTime, mode: 0o744},
+		"artefacts/rpms":       {modTime: modTime.Add(time.Second), mode: os.ModeDir | 0o755},
+		"artefacts/rpms/a.rpm": {size: 3, modTime: modTime, mode: 0o644},
+	}
+
+	assert.Equal(t, []string{
+		"artefacts/rpms/a.rpm",
+		"combustion/20-b.sh",
+		"combustion/30-c.sh",
+	}, changedFiles(before, after))
+}
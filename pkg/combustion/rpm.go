@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -21,6 +24,9 @@ const (
 	gpgDir                = "gpg-keys"
 	installRPMsScriptName = "10-rpm-install.sh"
 	rpmComponentName      = "RPM"
+
+	rpmExec        = "rpm"
+	rpmQueryFormat = "%{NAME}\\n%{VERSION}-%{RELEASE}\\n%{ARCH}"
 )
 
 //go:embed templates/10-rpm-install.sh.tpl
@@ -77,6 +83,11 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("writing the RPM install script %s: %w", installRPMsScriptName, err)
 	}
 
+	if err = recordRPMs(ctx, repoPath); err != nil {
+		log.AuditComponentFailed(rpmComponentName)
+		return nil, fmt.Errorf("recording resolved packages: %w", err)
+	}
+
 	log.AuditComponentSuccessful(rpmComponentName)
 	return []string{script}, nil
 }
@@ -166,6 +177,73 @@ func writeRPMScript(ctx *image.Context, repoPath string, packages []string) (str
 	return installRPMsScriptName, nil
 }
 
+// recordRPMs adds every package in the resolved RPM repository to the SBOM of the image.
+func recordRPMs(ctx *image.Context, repoPath string) error {
+	return filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".rpm" {
+			return nil
+		}
+
+		name, version, arch, err := rpmPackageInfo(path)
+		if err != nil {
+			zap.S().Warnf("Skipping %s in the SBOM: %s", d.Name(), err)
+			return nil
+		}
+
+		checksum, err := output.FileSHA256(path)
+		if err != nil {
+			return fmt.Errorf("calculating checksum of %s: %w", d.Name(), err)
+		}
+
+		ctx.SBOM.Add(sbom.Component{
+			Type:    sbom.TypePackage,
+			Name:    name,
+			Version: version,
+			Arch:    arch,
+			SHA256:  checksum,
+		})
+
+		return nil
+	})
+}
+
+// rpmPackageInfo reads the package name, its "<version>-<release>" and its architecture from the RPM header.
+// The filename is parsed instead if the header can not be queried, e.g. when rpm is not installed.
+func rpmPackageInfo(path string) (name, version, arch string, err error) {
+	cmd := exec.Command(rpmExec, "--query", "--package", "--nosignature", "--queryformat", rpmQueryFormat, path)
+
+	out, err := cmd.Output()
+	if err == nil {
+		if fields := strings.Split(string(out), "\n"); len(fields) == 3 {
+			return fields[0], fields[1], fields[2], nil
+		}
+	}
+
+	zap.S().Debugf("Querying the header of %s failed, parsing its filename instead: %v", path, err)
+
+	return parseRPMFilename(filepath.Base(path))
+}
+
+// parseRPMFilename splits a "<name>-<version>-<release>.<arch>.rpm" filename into
+// the package name, its "<version>-<release>" and its architecture.
+func parseRPMFilename(filename string) (name, version, arch string, err error) {
+	nvra := strings.TrimSuffix(filename, ".rpm")
+
+	archIndex := strings.LastIndex(nvra, ".")
+	releaseIndex := strings.LastIndex(nvra[:max(archIndex, 0)], "-")
+	versionIndex := strings.LastIndex(nvra[:max(releaseIndex, 0)], "-")
+
+	if archIndex == -1 || releaseIndex == -1 || versionIndex <= 0 {
+		return "", "", "", fmt.Errorf("unexpected RPM filename '%s'", filename)
+	}
+
+	return nvra[:versionIndex], nvra[versionIndex+1 : archIndex], nvra[archIndex+1:], nil
+}
+
 func RPMsPath(ctx *image.Context) string {
 	return generateComponentPath(ctx, rpmDir)
 }
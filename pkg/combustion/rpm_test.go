@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
 )
 
 type mockRPMResolver struct {
@@ -286,12 +287,18 @@ func TestConfigureRPMs_GPGFailures(t *testing.T) {
 
 func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	expectedRepoName := "bar"
-	expectedDir := "/foo/bar"
 	expectedPkg := []string{"foo", "bar"}
 
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
+	expectedDir := filepath.Join(ctx.BuildDir, expectedRepoName)
+	require.NoError(t, os.MkdirAll(filepath.Join(expectedDir, "x86_64"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "x86_64", "foo-1.2.3-150600.1.1.x86_64.rpm"), []byte("foo"), 0o600))
+	// Side-loaded packages may be named freely, they are skipped in the SBOM if their header can not be read
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "x86_64", "custom.rpm"), []byte("custom"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "repomd.xml"), nil, 0o600))
+
 	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
 		PKGList: []string{"foo", "bar"},
 		AdditionalRepos: []image.AddRepo{
@@ -356,4 +363,60 @@ func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	assert.Contains(t, foundContents, zypperAR)
 	assert.Contains(t, foundContents, zypperInstall)
 	assert.Contains(t, foundContents, zypperRR)
+
+	assert.Equal(t, []sbom.Component{
+		{
+			Type:    sbom.TypePackage,
+			Name:    "foo",
+			Version: "1.2.3-150600.1.1",
+			Arch:    "x86_64",
+			SHA256:  "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+	}, ctx.SBOM.Components())
+}
+
+func TestParseRPMFilename(t *testing.T) {
+	tests := []struct {
+		filename        string
+		expectedName    string
+		expectedVersion string
+		expectedArch    string
+		expectedErr     string
+	}{
+		{
+			filename:        "foo-1.2.3-150600.1.1.x86_64.rpm",
+			expectedName:    "foo",
+			expectedVersion: "1.2.3-150600.1.1",
+			expectedArch:    "x86_64",
+		},
+		{
+			filename:        "rke2-selinux-0.18-1.slemicro.noarch.rpm",
+			expectedName:    "rke2-selinux",
+			expectedVersion: "0.18-1.slemicro",
+			expectedArch:    "noarch",
+		},
+		{
+			filename:    "foo.rpm",
+			expectedErr: "unexpected RPM filename 'foo.rpm'",
+		},
+		{
+			filename:    "foo-1.x86_64.rpm",
+			expectedErr: "unexpected RPM filename 'foo-1.x86_64.rpm'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.filename, func(t *testing.T) {
+			name, version, arch, err := parseRPMFilename(test.filename)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedName, name)
+			assert.Equal(t, test.expectedVersion, version)
+			assert.Equal(t, test.expectedArch, arch)
+		})
+	}
 }
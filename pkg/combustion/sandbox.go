@@ -0,0 +1,43 @@
+package combustion
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/sandbox"
+)
+
+type scriptSandbox interface {
+	Run(combustionDir, artefactsDir string) ([]sandbox.ScriptResult, error)
+}
+
+// runSandbox executes the assembled combustion scripts in the sandbox and reports the result of each of them.
+func (c *Combustion) runSandbox(ctx *image.Context) error {
+	log.Audit("Running combustion scripts in a sandbox...")
+
+	results, err := c.Sandbox.Run(ctx.CombustionDir, ctx.ArtefactsDir)
+	if err != nil {
+		return fmt.Errorf("running sandbox: %w", err)
+	}
+
+	var failed int
+	for _, result := range results {
+		switch {
+		case result.Passed():
+			log.Auditf("  %s: passed", result.Name)
+		case result.ExitCode < 0:
+			failed++
+			log.Auditf("  %s: did not run", result.Name)
+		default:
+			failed++
+			log.Auditf("  %s: failed with exit code %d, see %s", result.Name, result.ExitCode, result.LogPath)
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d combustion scripts failed in the sandbox", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package combustion
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sandbox"
+)
+
+type mockScriptSandbox struct {
+	runFunc func(combustionDir, artefactsDir string) ([]sandbox.ScriptResult, error)
+}
+
+func (m mockScriptSandbox) Run(combustionDir, artefactsDir string) ([]sandbox.ScriptResult, error) {
+	if m.runFunc != nil {
+		return m.runFunc(combustionDir, artefactsDir)
+	}
+
+	panic("not implemented")
+}
+
+func TestRunSandbox(t *testing.T) {
+	tests := map[string]struct {
+		results       []sandbox.ScriptResult
+		runErr        error
+		expectedError string
+	}{
+		"All scripts passed": {
+			results: []sandbox.ScriptResult{
+				{Name: "10-users.sh"},
+				{Name: "20-custom.sh"},
+			},
+		},
+		"Scripts failed": {
+			results: []sandbox.ScriptResult{
+				{Name: "10-users.sh", ExitCode: 2},
+				{Name: "20-custom.sh", ExitCode: -1},
+				{Name: "30-cleanup.sh"},
+			},
+			expectedError: "2 of 3 combustion scripts failed in the sandbox",
+		},
+		"Sandbox failed": {
+			runErr:        fmt.Errorf("building sandbox image: no space left on device"),
+			expectedError: "running sandbox: building sandbox image: no space left on device",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				CombustionDir: "combustion",
+				ArtefactsDir:  "artefacts",
+			}
+
+			c := &Combustion{
+				Sandbox: mockScriptSandbox{
+					runFunc: func(combustionDir, artefactsDir string) ([]sandbox.ScriptResult, error) {
+						assert.Equal(t, "combustion", combustionDir)
+						assert.Equal(t, "artefacts", artefactsDir)
+
+						return test.results, test.runErr
+					},
+				},
+			}
+
+			err := c.runSandbox(ctx)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
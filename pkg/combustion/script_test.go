@@ -21,13 +21,18 @@ func TestAssembleScript_DynamicNetwork(t *testing.T) {
 	assert.Contains(t, script, `
 echo "Running bar.sh"
 ./bar.sh
+echo "Completed bar.sh"
 
 echo "Running baz.sh"
 ./baz.sh
+echo "Completed baz.sh"
 
 echo "Running foo.sh"
 ./foo.sh
+echo "Completed foo.sh"
 `)
+
+	assert.Contains(t, script, `echo "Completed 3 combustion scripts"`)
 }
 
 func TestAssembleScript_StaticNetwork(t *testing.T) {
@@ -44,11 +49,14 @@ func TestAssembleScript_StaticNetwork(t *testing.T) {
 	assert.Contains(t, script, `
 echo "Running bar.sh"
 ./bar.sh
+echo "Completed bar.sh"
 
 echo "Running baz.sh"
 ./baz.sh
+echo "Completed baz.sh"
 
 echo "Running foo.sh"
 ./foo.sh
+echo "Completed foo.sh"
 `)
 }
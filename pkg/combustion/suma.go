@@ -21,8 +21,7 @@ const (
 var sumaScript string
 
 func configureSuma(ctx *image.Context) ([]string, error) {
-	suma := ctx.ImageDefinition.OperatingSystem.Suma
-	if suma.Host == "" {
+	if skipSumaComponent(ctx) {
 		log.AuditComponentSkipped(sumaComponentName)
 		return nil, nil
 	}
@@ -36,6 +35,10 @@ func configureSuma(ctx *image.Context) ([]string, error) {
 	return []string{sumaScriptName}, nil
 }
 
+func skipSumaComponent(ctx *image.Context) bool {
+	return ctx.ImageDefinition.OperatingSystem.Suma.Host == ""
+}
+
 func writeSumaCombustionScript(ctx *image.Context) error {
 	sumaScriptFilename := filepath.Join(ctx.CombustionDir, sumaScriptName)
 
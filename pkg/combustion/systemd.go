@@ -22,8 +22,7 @@ var systemdTemplate string
 
 func configureSystemd(ctx *image.Context) ([]string, error) {
 	// Nothing to do if both lists are empty
-	systemd := ctx.ImageDefinition.OperatingSystem.Systemd
-	if len(systemd.Enable) == 0 && len(systemd.Disable) == 0 {
+	if skipSystemdComponent(ctx) {
 		log.AuditComponentSkipped(systemdComponentName)
 		return nil, nil
 	}
@@ -44,3 +43,8 @@ func configureSystemd(ctx *image.Context) ([]string, error) {
 	log.AuditComponentSuccessful(systemdComponentName)
 	return []string{systemdScriptName}, nil
 }
+
+func skipSystemdComponent(ctx *image.Context) bool {
+	systemd := ctx.ImageDefinition.OperatingSystem.Systemd
+	return len(systemd.Enable) == 0 && len(systemd.Disable) == 0
+}
@@ -23,7 +23,10 @@ export ARTEFACTS_DIR=/mnt/artefacts
 {{ range .Scripts -}}
 echo "Running {{ . }}"
 ./{{ . }}
+echo "Completed {{ . }}"
 
 {{ end -}}
 
 umount /mnt
+
+echo "Completed {{ len .Scripts }} combustion scripts"
@@ -21,8 +21,7 @@ const (
 var timeScript string
 
 func configureTime(ctx *image.Context) ([]string, error) {
-	time := ctx.ImageDefinition.OperatingSystem.Time
-	if time.Timezone == "" {
+	if skipTimeComponent(ctx) {
 		log.AuditComponentSkipped(timeComponentName)
 		return nil, nil
 	}
@@ -36,6 +35,10 @@ func configureTime(ctx *image.Context) ([]string, error) {
 	return []string{timeScriptName}, nil
 }
 
+func skipTimeComponent(ctx *image.Context) bool {
+	return ctx.ImageDefinition.OperatingSystem.Time.Timezone == ""
+}
+
 func writeTimeCombustionScript(ctx *image.Context) error {
 	timeScriptFilename := filepath.Join(ctx.CombustionDir, timeScriptName)
 
@@ -22,7 +22,7 @@ var usersScript string
 
 func configureUsers(ctx *image.Context) ([]string, error) {
 	// Punch out early if there are no users
-	if len(ctx.ImageDefinition.OperatingSystem.Users) == 0 {
+	if skipUsersComponent(ctx) {
 		log.AuditComponentSkipped(usersComponentName)
 		return nil, nil
 	}
@@ -43,3 +43,7 @@ func configureUsers(ctx *image.Context) ([]string, error) {
 	log.AuditComponentSuccessful(usersComponentName)
 	return []string{usersScriptName}, nil
 }
+
+func skipUsersComponent(ctx *image.Context) bool {
+	return len(ctx.ImageDefinition.OperatingSystem.Users) == 0
+}
@@ -0,0 +1,226 @@
+package containerdisk
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/image/v5/oci/archive"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	DefaultTag = "latest"
+
+	// KubeVirt expects the disk image in the /disk directory, readable by the qemu user
+	diskDir = "disk"
+	qemuID  = 107
+
+	layerFilename = "containerdisk-layer.tar"
+)
+
+// OutputPath returns the location the containerDisk for the given disk image is written to.
+func OutputPath(diskImagePath, format string) string {
+	if format == image.ContainerDiskFormatOCIArchive {
+		return diskImagePath + ".oci.tar"
+	}
+
+	return diskImagePath + ".oci"
+}
+
+// Create wraps the disk image into a KubeVirt containerDisk, and writes it either as an OCI image layout
+// directory or as an OCI archive depending on the format. The work directory holds intermediate files.
+// Writing the containerDisk is interrupted once the context is cancelled.
+func Create(ctx context.Context, diskImagePath, outputPath, format, tag string, arch image.Arch, workDir string) error {
+	if tag == "" {
+		tag = DefaultTag
+	}
+
+	if err := os.RemoveAll(outputPath); err != nil {
+		return fmt.Errorf("deleting existing containerDisk: %w", err)
+	}
+
+	ref, err := newReference(outputPath, format, tag)
+	if err != nil {
+		return fmt.Errorf("creating image reference: %w", err)
+	}
+
+	layerPath := filepath.Join(workDir, layerFilename)
+	layerDigest, layerSize, err := writeLayer(ctx, diskImagePath, layerPath)
+	if err != nil {
+		return fmt.Errorf("writing disk layer: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(layerPath)
+	}()
+
+	dest, err := ref.NewImageDestination(ctx, &types.SystemContext{BigFilesTemporaryDir: workDir})
+	if err != nil {
+		return fmt.Errorf("creating image destination: %w", err)
+	}
+
+	defer func() {
+		_ = dest.Close()
+	}()
+
+	layerFile, err := os.Open(layerPath)
+	if err != nil {
+		return fmt.Errorf("opening disk layer: %w", err)
+	}
+	defer layerFile.Close()
+
+	layer, err := dest.PutBlob(ctx, fileio.ContextReader(ctx, layerFile), types.BlobInfo{Digest: layerDigest, Size: layerSize}, none.NoCache, false)
+	if err != nil {
+		return fmt.Errorf("storing disk layer: %w", err)
+	}
+
+	config, err := imageConfig(arch, layerDigest)
+	if err != nil {
+		return fmt.Errorf("creating image config: %w", err)
+	}
+
+	configDigest := digest.FromBytes(config)
+	configBlob, err := dest.PutBlob(ctx, bytes.NewReader(config), types.BlobInfo{Digest: configDigest, Size: int64(len(config))}, none.NoCache, true)
+	if err != nil {
+		return fmt.Errorf("storing image config: %w", err)
+	}
+
+	manifest, err := json.Marshal(imgspecv1.Manifest{
+		Versioned: imgspecs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageManifest,
+		Config: imgspecv1.Descriptor{
+			MediaType: imgspecv1.MediaTypeImageConfig,
+			Digest:    configBlob.Digest,
+			Size:      configBlob.Size,
+		},
+		Layers: []imgspecv1.Descriptor{
+			{
+				MediaType: imgspecv1.MediaTypeImageLayer,
+				Digest:    layer.Digest,
+				Size:      layer.Size,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating image manifest: %w", err)
+	}
+
+	if err = dest.PutManifest(ctx, manifest, nil); err != nil {
+		return fmt.Errorf("storing image manifest: %w", err)
+	}
+
+	if err = dest.Commit(ctx, nil); err != nil {
+		return fmt.Errorf("committing image: %w", err)
+	}
+
+	return nil
+}
+
+func newReference(outputPath, format, tag string) (types.ImageReference, error) {
+	switch format {
+	case image.ContainerDiskFormatOCI:
+		return layout.NewReference(outputPath, tag)
+	case image.ContainerDiskFormatOCIArchive:
+		return archive.NewReference(outputPath, tag)
+	default:
+		return nil, fmt.Errorf("unsupported containerDisk format '%s'", format)
+	}
+}
+
+// writeLayer writes an uncompressed layer holding the disk image under /disk and returns its digest and size.
+func writeLayer(ctx context.Context, diskImagePath, layerPath string) (digest.Digest, int64, error) {
+	diskImage, err := os.Open(diskImagePath)
+	if err != nil {
+		return "", 0, fmt.Errorf("opening disk image: %w", err)
+	}
+	defer diskImage.Close()
+
+	info, err := diskImage.Stat()
+	if err != nil {
+		return "", 0, fmt.Errorf("reading disk image info: %w", err)
+	}
+
+	layerFile, err := os.Create(layerPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("creating layer file: %w", err)
+	}
+	defer layerFile.Close()
+
+	digester := digest.Canonical.Digester()
+	counter := &countingWriter{}
+	tw := tar.NewWriter(io.MultiWriter(layerFile, digester.Hash(), counter))
+
+	dirHeader := &tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     diskDir + "/",
+		Mode:     0o555,
+		Uid:      qemuID,
+		Gid:      qemuID,
+		ModTime:  info.ModTime(),
+	}
+	if err = tw.WriteHeader(dirHeader); err != nil {
+		return "", 0, fmt.Errorf("writing directory header: %w", err)
+	}
+
+	diskHeader := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filepath.Join(diskDir, filepath.Base(diskImagePath)),
+		Mode:     0o440,
+		Uid:      qemuID,
+		Gid:      qemuID,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}
+	if err = tw.WriteHeader(diskHeader); err != nil {
+		return "", 0, fmt.Errorf("writing disk image header: %w", err)
+	}
+
+	if _, err = io.Copy(tw, fileio.ContextReader(ctx, diskImage)); err != nil {
+		return "", 0, fmt.Errorf("writing disk image: %w", err)
+	}
+
+	if err = tw.Close(); err != nil {
+		return "", 0, fmt.Errorf("closing layer: %w", err)
+	}
+
+	return digester.Digest(), counter.size, nil
+}
+
+func imageConfig(arch image.Arch, layerDigest digest.Digest) ([]byte, error) {
+	created := time.Now().UTC()
+
+	return json.Marshal(imgspecv1.Image{
+		Created: &created,
+		Platform: imgspecv1.Platform{
+			Architecture: arch.Short(),
+			OS:           "linux",
+		},
+		RootFS: imgspecv1.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{layerDigest},
+		},
+	})
+}
+
+type countingWriter struct {
+	size int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.size += int64(len(p))
+	return len(p), nil
+}
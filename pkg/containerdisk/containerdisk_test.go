@@ -0,0 +1,131 @@
+package containerdisk
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestOutputPath(t *testing.T) {
+	assert.Equal(t, "/eib/image.qcow2.oci", OutputPath("/eib/image.qcow2", image.ContainerDiskFormatOCI))
+	assert.Equal(t, "/eib/image.qcow2.oci.tar", OutputPath("/eib/image.qcow2", image.ContainerDiskFormatOCIArchive))
+}
+
+func TestCreate_OCILayout(t *testing.T) {
+	workDir := t.TempDir()
+	diskImage := filepath.Join(workDir, "image.qcow2")
+	require.NoError(t, os.WriteFile(diskImage, []byte("disk contents"), 0o600))
+
+	outputPath := filepath.Join(t.TempDir(), "image.qcow2.oci")
+	require.NoError(t, Create(context.Background(), diskImage, outputPath, image.ContainerDiskFormatOCI, "", image.ArchTypeARM, workDir))
+
+	var index imgspecv1.Index
+	readJSON(t, filepath.Join(outputPath, "index.json"), &index)
+
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, DefaultTag, index.Manifests[0].Annotations[imgspecv1.AnnotationRefName])
+
+	var manifest imgspecv1.Manifest
+	readJSON(t, blobPath(outputPath, index.Manifests[0].Digest), &manifest)
+
+	var config imgspecv1.Image
+	readJSON(t, blobPath(outputPath, manifest.Config.Digest), &config)
+
+	assert.Equal(t, "arm64", config.Architecture)
+	assert.Equal(t, "linux", config.OS)
+
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, imgspecv1.MediaTypeImageLayer, manifest.Layers[0].MediaType)
+	assert.Equal(t, []digest.Digest{manifest.Layers[0].Digest}, config.RootFS.DiffIDs)
+
+	layer, err := os.Open(blobPath(outputPath, manifest.Layers[0].Digest))
+	require.NoError(t, err)
+	defer layer.Close()
+
+	var headers []*tar.Header
+	var contents string
+
+	reader := tar.NewReader(layer)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		headers = append(headers, header)
+
+		if header.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			contents = string(data)
+		}
+	}
+
+	require.Len(t, headers, 2)
+	assert.Equal(t, "disk/", headers[0].Name)
+	assert.Equal(t, "disk/image.qcow2", headers[1].Name)
+	assert.Equal(t, 107, headers[1].Uid)
+	assert.Equal(t, 107, headers[1].Gid)
+	assert.Equal(t, "disk contents", contents)
+
+	// The intermediate layer must not be left behind
+	assert.NoFileExists(t, filepath.Join(workDir, layerFilename))
+}
+
+func TestCreate_OCIArchive(t *testing.T) {
+	workDir := t.TempDir()
+	diskImage := filepath.Join(workDir, "image.raw")
+	require.NoError(t, os.WriteFile(diskImage, []byte("disk contents"), 0o600))
+
+	outputPath := filepath.Join(t.TempDir(), "image.raw.oci.tar")
+	require.NoError(t, Create(context.Background(), diskImage, outputPath, image.ContainerDiskFormatOCIArchive, "v1", image.ArchTypeX86, workDir))
+
+	archive, err := os.Open(outputPath)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	var names []string
+
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		names = append(names, filepath.Clean(header.Name))
+	}
+
+	assert.Contains(t, names, "oci-layout")
+	assert.Contains(t, names, "index.json")
+}
+
+func TestCreate_UnsupportedFormat(t *testing.T) {
+	workDir := t.TempDir()
+
+	err := Create(context.Background(), filepath.Join(workDir, "image.raw"), filepath.Join(workDir, "out"), "docker", "", image.ArchTypeX86, workDir)
+	assert.EqualError(t, err, "creating image reference: unsupported containerDisk format 'docker'")
+}
+
+func blobPath(layoutDir string, d digest.Digest) string {
+	return filepath.Join(layoutDir, "blobs", d.Algorithm().String(), d.Encoded())
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
@@ -14,12 +14,15 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/podman"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm/resolver"
+	"github.com/suse-edge/edge-image-builder/pkg/sandbox"
 
 	"github.com/suse-edge/edge-image-builder/pkg/build"
 	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
 	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/container"
 	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
@@ -28,22 +31,45 @@ import (
 	"go.uber.org/zap"
 )
 
+// abortedFileName marks a build directory whose build was interrupted.
+const abortedFileName = "aborted"
+
 func Run(ctx *image.Context, rootBuildDir string) error {
-	if err := appendKubernetesSELinuxRPMs(ctx); err != nil {
+	if err := appendDependencies(ctx); err != nil {
 		log.Auditf("Bootstrapping dependency services failed.")
-		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+		return err
 	}
 
-	appendHelm(ctx)
-	if !ctx.IsConfigDrive {
-		appendElementalRPMs(ctx)
-		appendFIPS(ctx)
-	}
+	var c *combustion.Combustion
 
-	c, err := buildCombustion(ctx, rootBuildDir)
-	if err != nil {
-		log.Audit("Bootstrapping dependency services failed.")
-		return fmt.Errorf("building combustion: %w", err)
+	if ctx.Checkpoints.Completed(checkpoint.StageCombustionConfigured) {
+		// The dependency services are only used for configuring combustion, which is skipped when resuming
+		components, err := combustion.RecordedSBOM(ctx.BuildDir)
+		if err != nil {
+			log.Audit("Restoring the SBOM of the interrupted build failed.")
+			return fmt.Errorf("restoring SBOM: %w", err)
+		}
+
+		ctx.SBOM.Add(components...)
+
+		downloads, err := combustion.RecordedDownloads(ctx.BuildDir)
+		if err != nil {
+			log.Audit("Restoring the downloads of the interrupted build failed.")
+			return fmt.Errorf("restoring downloads: %w", err)
+		}
+
+		http.RestoreDownloads(downloads...)
+	} else {
+		if err := downloadKubernetesSELinuxSigningKey(ctx); err != nil {
+			log.Auditf("Bootstrapping dependency services failed.")
+			return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+		}
+
+		var err error
+		if c, err = buildCombustion(ctx, rootBuildDir); err != nil {
+			log.Audit("Bootstrapping dependency services failed.")
+			return fmt.Errorf("building combustion: %w", err)
+		}
 	}
 
 	if !ctx.IsConfigDrive {
@@ -55,18 +81,43 @@ func Run(ctx *image.Context, rootBuildDir string) error {
 	return builder.Generate()
 }
 
-func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+// appendDependencies adds the packages, repositories, Helm charts and kernel arguments required by
+// the configured components to the definition. It does not download anything.
+func appendDependencies(ctx *image.Context) error {
+	if err := appendKubernetesSELinuxRPMs(ctx); err != nil {
+		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+	}
+
+	appendHelm(ctx)
+	if !ctx.IsConfigDrive {
+		appendElementalRPMs(ctx)
+		appendFIPS(ctx)
+	}
+
+	return nil
+}
+
+func kubernetesSELinuxEnabled(ctx *image.Context) (bool, error) {
 	if ctx.ImageDefinition.Kubernetes.Version == "" {
-		return nil
+		return false, nil
 	}
 
 	configPath := combustion.KubernetesConfigPath(ctx)
 	config, err := kubernetes.ParseKubernetesConfig(configPath)
 	if err != nil {
-		return fmt.Errorf("parsing kubernetes server config: %w", err)
+		return false, fmt.Errorf("parsing kubernetes server config: %w", err)
 	}
 
 	selinuxEnabled, _ := config["selinux"].(bool)
+	return selinuxEnabled, nil
+}
+
+func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+	selinuxEnabled, err := kubernetesSELinuxEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
 	if !selinuxEnabled {
 		return nil
 	}
@@ -86,12 +137,25 @@ func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
 
 	appendRPMs(ctx, []image.AddRepo{repository}, selinuxPackage)
 
+	return nil
+}
+
+func downloadKubernetesSELinuxSigningKey(ctx *image.Context) error {
+	selinuxEnabled, err := kubernetesSELinuxEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !selinuxEnabled {
+		return nil
+	}
+
 	gpgKeysDir := combustion.GPGKeysPath(ctx)
 	if err = os.MkdirAll(gpgKeysDir, os.ModePerm); err != nil {
 		return fmt.Errorf("creating directory '%s': %w", gpgKeysDir, err)
 	}
 
-	if err = kubernetes.DownloadSELinuxRPMsSigningKey(gpgKeysDir); err != nil {
+	if err = kubernetes.DownloadSELinuxRPMsSigningKey(ctx.BuildContext(), gpgKeysDir); err != nil {
 		return fmt.Errorf("downloading signing key: %w", err)
 	}
 
@@ -127,12 +191,6 @@ func appendFIPS(ctx *image.Context) {
 	if fips {
 		log.AuditInfo("FIPS mode is configured. The necessary RPM packages will be downloaded.")
 
-		packages := ctx.ImageDefinition.OperatingSystem.Packages
-		if packages.RegCode == "" && len(packages.AdditionalRepos) > 0 {
-			log.Audit("WARNING: FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package.")
-			zap.S().Warn("Detected FIPS for installation with no sccRegistrationCode provided")
-		}
-
 		appendRPMs(ctx, nil, combustion.FIPSPackages...)
 		appendKernelArgs(ctx, combustion.FIPSKernelArgs...)
 	}
@@ -168,8 +226,8 @@ func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion
 		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
 	}
 
-	if !combustion.SkipRPMComponent(ctx) || combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
-		p, err := podman.New(ctx.BuildDir)
+	if !combustion.SkipRPMComponent(ctx) || combustion.IsEmbeddedArtifactRegistryConfigured(ctx) || ctx.Preflight {
+		p, err := podman.New(ctx.BuildContext(), ctx.BuildDir)
 		if err != nil {
 			return nil, fmt.Errorf("setting up Podman instance: %w", err)
 		}
@@ -178,18 +236,30 @@ func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion
 			ImageInspector: p,
 		}
 
-		if !combustion.SkipRPMComponent(ctx) {
+		// The RPM resolver and the sandbox share the image built from the base image
+		var baseBuilder *resolver.TarballImageBuilder
+		if !combustion.SkipRPMComponent(ctx) || ctx.Preflight {
 			imgPath := filepath.Join(ctx.ImageConfigDir, "base-images", ctx.ImageDefinition.Image.BaseImage)
 			imgType := ctx.ImageDefinition.Image.ImageType
+			if imgType == image.TypePXE {
+				// PXE artefacts are built from the SelfInstall ISO
+				imgType = image.TypeISO
+			}
 			luksKey := ctx.ImageDefinition.OperatingSystem.RawConfiguration.LUKSKey
-			baseBuilder := resolver.NewTarballBuilder(ctx.BuildDir, imgPath, imgType, string(ctx.ImageDefinition.Image.Arch), luksKey, p)
+			baseBuilder = resolver.NewTarballBuilder(ctx.BuildContext(), ctx.BuildDir, imgPath, imgType, string(ctx.ImageDefinition.Image.Arch), luksKey, p)
+		}
 
+		if !combustion.SkipRPMComponent(ctx) {
 			combustionHandler.RPMResolver = resolver.New(ctx.BuildDir, p, baseBuilder, "", string(ctx.ImageDefinition.Image.Arch))
-			combustionHandler.RPMRepoCreator = rpm.NewRepoCreator(ctx.BuildDir)
+			combustionHandler.RPMRepoCreator = rpm.NewRepoCreator(ctx.BuildContext(), ctx.BuildDir)
+		}
+
+		if ctx.Preflight {
+			combustionHandler.Sandbox = sandbox.New(ctx.BuildDir, p, baseBuilder)
 		}
 
 		if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
-			helmClient := helm.New(ctx.BuildDir, combustion.HelmCertsPath(ctx))
+			helmClient := helm.New(ctx.BuildContext(), ctx.BuildDir, combustion.HelmCertsPath(ctx))
 
 			combustionHandler.Registry, err = registry.New(ctx, combustion.KubernetesManifestsPath(ctx), helmClient, combustion.HelmValuesPath(ctx))
 			if err != nil {
@@ -225,6 +295,29 @@ func SetupBuildDirectory(rootDir string) (string, error) {
 	return buildDir, nil
 }
 
+// MarkBuildAborted records in the build directory that the build was interrupted, so that it is not mistaken
+// for a complete one. The marker is removed once the build is resumed.
+func MarkBuildAborted(buildDir string, reason error) error {
+	contents := fmt.Sprintf("%s: %s\n", time.Now().Format(time.RFC3339), reason)
+
+	filename := filepath.Join(buildDir, abortedFileName)
+	if err := os.WriteFile(filename, []byte(contents), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing aborted marker: %w", err)
+	}
+
+	return nil
+}
+
+// ClearBuildAborted removes the marker of an interrupted build from the build directory.
+func ClearBuildAborted(buildDir string) error {
+	filename := filepath.Join(buildDir, abortedFileName)
+	if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing aborted marker: %w", err)
+	}
+
+	return nil
+}
+
 func SetupCombustionDirectory(buildDir string) (combustionDir, artefactsDir string, err error) {
 	combustionDir = filepath.Join(buildDir, "combustion")
 	if err = os.MkdirAll(combustionDir, os.ModePerm); err != nil {
@@ -239,9 +332,13 @@ func SetupCombustionDirectory(buildDir string) (combustionDir, artefactsDir stri
 	return combustionDir, artefactsDir, nil
 }
 
-func SetupCacheDirectory(rootDir, userCacheDir string) (string, error) {
-	defaultMountedCacheDir := "/eib-cache"
+const (
+	defaultMountedCacheDir = "/eib-cache"
+	defaultCacheDirName    = "cache"
+)
 
+// CacheDirectory determines the location of the cache directory without setting it up.
+func CacheDirectory(rootDir, userCacheDir string) (string, error) {
 	// If a user specifies a custom location for the cache directory, but it's not mounted or found, return an error.
 	// Otherwise, use the expected mount location.
 	if userCacheDir != defaultMountedCacheDir {
@@ -255,14 +352,24 @@ func SetupCacheDirectory(rootDir, userCacheDir string) (string, error) {
 
 	// If the user has not mounted a cache directory at `/eib-cache` we will use the original cache location under the root directory.
 	if !fileio.DirExists(defaultMountedCacheDir) {
-		defaultCacheDir := filepath.Join(rootDir, "cache")
-		if err := os.MkdirAll(defaultCacheDir, os.ModePerm); err != nil {
-			return "", fmt.Errorf("creating cache directory in default location `%s`: %w", defaultCacheDir, err)
-		}
-		log.AuditInfof("No mounted cache directory detected, default cache directory at `%s` will be used.", defaultCacheDir)
-
-		return defaultCacheDir, nil
+		return filepath.Join(rootDir, defaultCacheDirName), nil
 	}
 
 	return defaultMountedCacheDir, nil
 }
+
+func SetupCacheDirectory(rootDir, userCacheDir string) (string, error) {
+	cacheDir, err := CacheDirectory(rootDir, userCacheDir)
+	if err != nil {
+		return "", err
+	}
+
+	if cacheDir == filepath.Join(rootDir, defaultCacheDirName) {
+		if err = os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+			return "", fmt.Errorf("creating cache directory in default location `%s`: %w", cacheDir, err)
+		}
+		log.AuditInfof("No mounted cache directory detected, default cache directory at `%s` will be used.", cacheDir)
+	}
+
+	return cacheDir, nil
+}
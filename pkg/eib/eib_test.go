@@ -1,6 +1,7 @@
 package eib
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -55,3 +56,19 @@ func TestSetupBuildDir_NonEmptyRootDir(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkBuildAborted(t *testing.T) {
+	buildDir := t.TempDir()
+	markerPath := filepath.Join(buildDir, abortedFileName)
+
+	require.NoError(t, MarkBuildAborted(buildDir, context.Canceled))
+
+	contents, err := os.ReadFile(markerPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "context canceled")
+
+	require.NoError(t, ClearBuildAborted(buildDir))
+	assert.NoFileExists(t, markerPath)
+
+	require.NoError(t, ClearBuildAborted(buildDir), "clearing a build which was not aborted must succeed")
+}
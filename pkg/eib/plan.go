@@ -0,0 +1,352 @@
+package eib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+)
+
+// BuildPlan describes the work a build of the image definition would perform.
+type BuildPlan struct {
+	Components []ComponentPlan
+	// Packages is nil if no RPMs will be resolved.
+	Packages *PackagesPlan
+	// Kubernetes is nil if no Kubernetes cluster is configured.
+	Kubernetes *KubernetesPlan
+	HelmCharts []HelmChartPlan
+	// ContainerImages lists the images the embedded artifact registry will contain which are known before building.
+	// Images referenced by remote manifests and Helm charts are only discovered during the build.
+	ContainerImages []string
+	Sizes           []SizeEstimate
+}
+
+type ComponentPlan struct {
+	Name    string
+	Skipped bool
+}
+
+type PackagesPlan struct {
+	Packages        []string
+	Repositories    []string
+	SideLoadedRPMs  []string
+	RegCodeProvided bool
+}
+
+type KubernetesPlan struct {
+	Version      string
+	Artefacts    []kubernetes.Artefact
+	ManifestURLs []string
+}
+
+type HelmChartPlan struct {
+	Name       string
+	Version    string
+	Repository string
+}
+
+// SizeEstimate is the size of a group of build inputs. Unknown is set when the size
+// of some of the inputs can only be determined by downloading them.
+type SizeEstimate struct {
+	Description string
+	Size        int64
+	Unknown     bool
+}
+
+// Plan applies the same definition modifications as a build and describes the resulting build
+// without downloading any artefacts or building the image.
+func Plan(ctx *image.Context) (*BuildPlan, error) {
+	if err := appendDependencies(ctx); err != nil {
+		return nil, fmt.Errorf("appending dependencies: %w", err)
+	}
+
+	plan := &BuildPlan{}
+
+	c := &combustion.Combustion{}
+	for _, component := range c.Components() {
+		plan.Components = append(plan.Components, ComponentPlan{
+			Name:    component.Name,
+			Skipped: component.Skipped(ctx),
+		})
+	}
+
+	if !combustion.SkipRPMComponent(ctx) {
+		packages, err := planPackages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("planning packages: %w", err)
+		}
+
+		plan.Packages = packages
+	}
+
+	if ctx.ImageDefinition.Kubernetes.Version != "" {
+		k8s, err := planKubernetes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("planning kubernetes: %w", err)
+		}
+
+		plan.Kubernetes = k8s
+	}
+
+	plan.HelmCharts = planHelmCharts(&ctx.ImageDefinition.Kubernetes.Helm)
+
+	if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+		containerImages, err := planContainerImages(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("planning container images: %w", err)
+		}
+
+		plan.ContainerImages = containerImages
+	}
+
+	sizes, err := estimateSizes(ctx, plan)
+	if err != nil {
+		return nil, fmt.Errorf("estimating sizes: %w", err)
+	}
+
+	plan.Sizes = sizes
+
+	return plan, nil
+}
+
+func planPackages(ctx *image.Context) (*PackagesPlan, error) {
+	packages := ctx.ImageDefinition.OperatingSystem.Packages
+
+	plan := &PackagesPlan{
+		Packages:        packages.PKGList,
+		RegCodeProvided: packages.RegCode != "",
+	}
+
+	for _, repo := range packages.AdditionalRepos {
+		plan.Repositories = append(plan.Repositories, repo.URL)
+	}
+
+	entries, err := os.ReadDir(combustion.RPMsPath(ctx))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading side-loaded RPMs: %w", err)
+	}
+
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".rpm" {
+			plan.SideLoadedRPMs = append(plan.SideLoadedRPMs, entry.Name())
+		}
+	}
+
+	return plan, nil
+}
+
+func planKubernetes(ctx *image.Context) (*KubernetesPlan, error) {
+	k8s := &ctx.ImageDefinition.Kubernetes
+
+	var artefactCache *cache.Cache
+	if ctx.CacheDir != "" {
+		c, err := cache.New(ctx.CacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("initialising cache instance: %w", err)
+		}
+
+		artefactCache = c
+	}
+
+	downloader := kubernetes.ArtefactDownloader{
+		Cache: artefactCache,
+	}
+
+	if ctx.ArtifactSources != nil {
+		downloader.Rke2ReleaseURL = ctx.ArtifactSources.Kubernetes.Rke2.ReleaseURL
+		downloader.K3sReleaseURL = ctx.ArtifactSources.Kubernetes.K3s.ReleaseURL
+	}
+
+	var artefacts []kubernetes.Artefact
+
+	switch {
+	case strings.Contains(k8s.Version, image.KubernetesDistroRKE2):
+		cluster, err := kubernetes.NewCluster(k8s, combustion.KubernetesConfigPath(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("initialising cluster config: %w", err)
+		}
+
+		cni, multusEnabled, err := cluster.ExtractCNI()
+		if err != nil {
+			return nil, fmt.Errorf("extracting CNI from cluster config: %w", err)
+		}
+
+		ingressController, err := cluster.ExtractIngress()
+		if err != nil {
+			return nil, fmt.Errorf("extracting ingress-controller from cluster config: %w", err)
+		}
+
+		artefacts, err = downloader.RKE2Artefacts(ctx.ImageDefinition.Image.Arch, k8s.Version, cni, multusEnabled, ingressController)
+		if err != nil {
+			return nil, fmt.Errorf("listing RKE2 artefacts: %w", err)
+		}
+	case strings.Contains(k8s.Version, image.KubernetesDistroK3S):
+		var err error
+		artefacts, err = downloader.K3sArtefacts(ctx.ImageDefinition.Image.Arch, k8s.Version)
+		if err != nil {
+			return nil, fmt.Errorf("listing k3s artefacts: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("cannot configure kubernetes version: %s", k8s.Version)
+	}
+
+	return &KubernetesPlan{
+		Version:      k8s.Version,
+		Artefacts:    artefacts,
+		ManifestURLs: k8s.Manifests.URLs,
+	}, nil
+}
+
+func planHelmCharts(helm *image.Helm) []HelmChartPlan {
+	repositories := map[string]string{}
+	for _, repo := range helm.Repositories {
+		repositories[repo.Name] = repo.URL
+	}
+
+	var charts []HelmChartPlan
+
+	for _, chart := range helm.Charts {
+		charts = append(charts, HelmChartPlan{
+			Name:       chart.Name,
+			Version:    chart.Version,
+			Repository: repositories[chart.RepositoryName],
+		})
+	}
+
+	return charts
+}
+
+func planContainerImages(ctx *image.Context) ([]string, error) {
+	var containerImages []string
+
+	for _, img := range ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages {
+		containerImages = append(containerImages, img.Name)
+	}
+
+	manifestImages, err := registry.ManifestImages(combustion.KubernetesManifestsPath(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("getting container images from local manifests: %w", err)
+	}
+
+	containerImages = append(containerImages, manifestImages...)
+
+	slices.Sort(containerImages)
+	return slices.Compact(containerImages), nil
+}
+
+func estimateSizes(ctx *image.Context, plan *BuildPlan) ([]SizeEstimate, error) {
+	var sizes []SizeEstimate
+
+	if ctx.ImageDefinition.Image.BaseImage != "" {
+		info, err := os.Stat(filepath.Join(ctx.ImageConfigDir, "base-images", ctx.ImageDefinition.Image.BaseImage))
+		if err != nil {
+			return nil, fmt.Errorf("reading base image: %w", err)
+		}
+
+		sizes = append(sizes, SizeEstimate{Description: "Base image", Size: info.Size()})
+	}
+
+	configSize, err := configurationSize(ctx.ImageConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("calculating configuration size: %w", err)
+	}
+
+	sizes = append(sizes, SizeEstimate{Description: "Image configuration files", Size: configSize})
+
+	if plan.Packages != nil {
+		rpmsSize, err := dirSize(combustion.RPMsPath(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("calculating side-loaded RPMs size: %w", err)
+		}
+
+		sizes = append(sizes, SizeEstimate{
+			Description: "RPM packages",
+			Size:        rpmsSize,
+			Unknown:     len(plan.Packages.Packages) != 0,
+		})
+	}
+
+	if plan.Kubernetes != nil {
+		estimate := SizeEstimate{Description: "Kubernetes artefacts"}
+
+		for _, artefact := range plan.Kubernetes.Artefacts {
+			if artefact.CachedSize == 0 {
+				estimate.Unknown = true
+			}
+			estimate.Size += artefact.CachedSize
+		}
+
+		sizes = append(sizes, estimate)
+	}
+
+	if len(plan.HelmCharts) != 0 || len(plan.ContainerImages) != 0 {
+		sizes = append(sizes, SizeEstimate{Description: "Helm charts and container images", Unknown: true})
+	}
+
+	return sizes, nil
+}
+
+// configurationSize sums up the files in the image configuration directory which are copied into the image.
+// Base images, side-loaded RPMs and the output of previous runs are excluded.
+func configurationSize(configDir string) (int64, error) {
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		return 0, fmt.Errorf("reading configuration directory: %w", err)
+	}
+
+	var size int64
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "base-images" || entry.Name() == "rpms" || strings.HasPrefix(entry.Name(), "_") {
+			continue
+		}
+
+		s, err := dirSize(filepath.Join(configDir, entry.Name()))
+		if err != nil {
+			return 0, err
+		}
+
+		size += s
+	}
+
+	return size, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("walking directory '%s': %w", dir, err)
+	}
+
+	return size, nil
+}
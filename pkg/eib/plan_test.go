@@ -0,0 +1,119 @@
+package eib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestPlan(t *testing.T) {
+	configDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base-images", "base.raw"), make([]byte, 2048), 0o600))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "rpms"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "rpms", "local.rpm"), make([]byte, 100), 0o600))
+
+	manifestsDir := filepath.Join(configDir, "kubernetes", "manifests")
+	require.NoError(t, os.MkdirAll(manifestsDir, os.ModePerm))
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx:1.25
+`
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "pod.yaml"), []byte(manifest), 0o600))
+
+	// Output of previous builds must not be counted towards the configuration size
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "_build"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "_build", "old.raw"), make([]byte, 4096), 0o600))
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType: image.TypeRAW,
+				Arch:      image.ArchTypeX86,
+				BaseImage: "base.raw",
+			},
+			OperatingSystem: image.OperatingSystem{
+				EnableFIPS: true,
+				Packages: image.Packages{
+					PKGList: []string{"vim"},
+					RegCode: "regcode",
+					AdditionalRepos: []image.AddRepo{
+						{URL: "https://repo.example.com"},
+					},
+				},
+			},
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+k3s1",
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{Name: "apache", RepositoryName: "bitnami", Version: "10.7.0"},
+					},
+					Repositories: []image.HelmRepository{
+						{Name: "bitnami", URL: "oci://registry-1.docker.io/bitnamicharts"},
+					},
+				},
+			},
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{Name: "nginx:1.25"},
+					{Name: "busybox:1.36"},
+				},
+			},
+		},
+		ArtifactSources: &image.ArtifactSources{},
+	}
+
+	plan, err := Plan(ctx)
+	require.NoError(t, err)
+
+	skipped := map[string]bool{}
+	for _, component := range plan.Components {
+		skipped[component.Name] = component.Skipped
+	}
+	assert.False(t, skipped["RPM"])
+	assert.False(t, skipped["fips"])
+	assert.False(t, skipped["kubernetes"])
+	assert.True(t, skipped["users"])
+
+	require.NotNil(t, plan.Packages)
+	assert.Equal(t, []string{"vim", "pattern:fips"}, plan.Packages.Packages)
+	assert.Equal(t, []string{"https://repo.example.com"}, plan.Packages.Repositories)
+	assert.Equal(t, []string{"local.rpm"}, plan.Packages.SideLoadedRPMs)
+	assert.True(t, plan.Packages.RegCodeProvided)
+
+	require.NotNil(t, plan.Kubernetes)
+	var artefacts []string
+	for _, artefact := range plan.Kubernetes.Artefacts {
+		artefacts = append(artefacts, artefact.Name)
+	}
+	assert.Equal(t, []string{"k3s-airgap-images-amd64.tar.zst", "k3s"}, artefacts)
+
+	assert.Equal(t, []HelmChartPlan{
+		{Name: "apache", Version: "10.7.0", Repository: "oci://registry-1.docker.io/bitnamicharts"},
+	}, plan.HelmCharts)
+
+	assert.Equal(t, []string{"busybox:1.36", "nginx:1.25"}, plan.ContainerImages)
+
+	assert.Equal(t, []SizeEstimate{
+		{Description: "Base image", Size: 2048},
+		{Description: "Image configuration files", Size: int64(len(manifest))},
+		{Description: "RPM packages", Size: 100, Unknown: true},
+		{Description: "Kubernetes artefacts", Unknown: true},
+		{Description: "Helm charts and container images", Unknown: true},
+	}, plan.Sizes)
+
+	// The definition is modified the same way as for a build
+	assert.Contains(t, ctx.ImageDefinition.OperatingSystem.KernelArgs, "fips=1")
+}
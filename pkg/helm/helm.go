@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/url"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -25,12 +28,15 @@ const (
 )
 
 type Helm struct {
+	context   context.Context
 	outputDir string
 	certsDir  string
 }
 
-func New(outputDir, certsDir string) *Helm {
+// New returns a Helm client whose commands are terminated once the given context is cancelled.
+func New(ctx context.Context, outputDir, certsDir string) *Helm {
 	return &Helm{
+		context:   ctx,
 		outputDir: outputDir,
 		certsDir:  certsDir,
 	}
@@ -58,21 +64,22 @@ func (h *Helm) AddRepo(repo *image.HelmRepository) error {
 		}
 	}()
 
-	cmd := addRepoCommand(repo, h.certsDir, file)
+	cmd := addRepoCommand(h.context, repo, h.certsDir, log.NewRedactingWriter(file))
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(file, "command: %s\n", log.Redact(cmd.String())); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
 	return cmd.Run()
 }
 
-func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
+func addRepoCommand(ctx context.Context, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
 	var args []string
 	args = append(args, "repo", "add", repo.Name, repo.URL)
 
+	// The password is provided through stdin so that it is not exposed in the process list or logs
 	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
-		args = append(args, "--username", repo.Authentication.Username, "--password", repo.Authentication.Password)
+		args = append(args, "--username", repo.Authentication.Username, "--password-stdin")
 	}
 
 	if repo.SkipTLSVerify {
@@ -82,10 +89,14 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := process.Command(ctx, "helm", args...)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
+	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
+		cmd.Stdin = strings.NewReader(repo.Authentication.Password)
+	}
+
 	return cmd
 }
 
@@ -107,21 +118,22 @@ func (h *Helm) RegistryLogin(repo *image.HelmRepository) error {
 		return fmt.Errorf("getting host url: %w", err)
 	}
 
-	cmd := registryLoginCommand(host, repo, h.certsDir, file)
+	cmd := registryLoginCommand(h.context, host, repo, h.certsDir, log.NewRedactingWriter(file))
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(file, "command: %s\n", log.Redact(cmd.String())); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
 	return cmd.Run()
 }
 
-func registryLoginCommand(host string, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
+func registryLoginCommand(ctx context.Context, host string, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
 	var args []string
 	args = append(args, "registry", "login", host)
 
+	// The password is provided through stdin so that it is not exposed in the process list or logs
 	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
-		args = append(args, "--username", repo.Authentication.Username, "--password", repo.Authentication.Password)
+		args = append(args, "--username", repo.Authentication.Username, "--password-stdin")
 	}
 
 	if repo.SkipTLSVerify || repo.PlainHTTP {
@@ -131,10 +143,14 @@ func registryLoginCommand(host string, repo *image.HelmRepository, certsDir stri
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := process.Command(ctx, "helm", args...)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
+	if repo.Authentication.Username != "" && repo.Authentication.Password != "" {
+		cmd.Stdin = strings.NewReader(repo.Authentication.Password)
+	}
+
 	return cmd
 }
 
@@ -156,9 +172,9 @@ func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir s
 		return "", fmt.Errorf("creating chart dir %q: %w", chartDir, err)
 	}
 
-	cmd := pullCommand(chart, repo, version, chartDir, h.certsDir, file)
+	cmd := pullCommand(h.context, chart, repo, version, chartDir, h.certsDir, log.NewRedactingWriter(file))
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(file, "command: %s\n", log.Redact(cmd.String())); err != nil {
 		return "", fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -179,7 +195,7 @@ func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir s
 	return chartPath, nil
 }
 
-func pullCommand(chart string, repo *image.HelmRepository, version, destDir, certsDir string, output io.Writer) *exec.Cmd {
+func pullCommand(ctx context.Context, chart string, repo *image.HelmRepository, version, destDir, certsDir string, output io.Writer) *exec.Cmd {
 	path := chartPath(repo.Name, repo.URL, chart)
 
 	var args []string
@@ -202,7 +218,7 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := process.Command(ctx, "helm", args...)
 
 	cmd.Stdout = output
 	cmd.Stderr = output
@@ -224,9 +240,10 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 	}()
 
 	chartContentsBuffer := new(strings.Builder)
-	cmd := templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions, io.MultiWriter(file, chartContentsBuffer), file)
+	output := log.NewRedactingWriter(file)
+	cmd := templateCommand(h.context, chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, apiVersions, io.MultiWriter(output, chartContentsBuffer), output)
 
-	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+	if _, err = fmt.Fprintf(file, "command: %s\n", log.Redact(cmd.String())); err != nil {
 		return nil, fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
@@ -243,7 +260,7 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 	return resources, nil
 }
 
-func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, stdout, stderr io.Writer) *exec.Cmd {
+func templateCommand(ctx context.Context, chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, apiVersions []string, stdout, stderr io.Writer) *exec.Cmd {
 	var args []string
 	args = append(args, "template", "--skip-crds", chart, repository)
 
@@ -265,7 +282,7 @@ func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, ta
 
 	args = append(args, "--kube-version", kubeVersion)
 
-	cmd := exec.Command("helm", args...)
+	cmd := process.Command(ctx, "helm", args...)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 
@@ -2,6 +2,8 @@ package helm
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -83,8 +85,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -106,8 +107,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure-skip-tls-verify",
 			},
 		},
@@ -130,8 +130,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"http://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -153,8 +152,7 @@ func TestAddRepoCommand(t *testing.T) {
 				"https://suse-edge.github.io/charts",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--ca-file",
 				"certs/suse-edge.crt",
 			},
@@ -165,11 +163,20 @@ func TestAddRepoCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := addRepoCommand(test.repo, certsDir, &buf)
+			cmd := addRepoCommand(context.Background(), test.repo, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
 			assert.Equal(t, &buf, cmd.Stderr)
+
+			if test.repo.Authentication.Password != "" {
+				require.NotNil(t, cmd.Stdin)
+				password, err := io.ReadAll(cmd.Stdin)
+				require.NoError(t, err)
+				assert.Equal(t, test.repo.Authentication.Password, string(password))
+			} else {
+				assert.Nil(t, cmd.Stdin)
+			}
 		})
 	}
 }
@@ -200,8 +207,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 			},
 		},
 		{
@@ -223,8 +229,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure",
 			},
 		},
@@ -247,8 +252,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--insecure",
 			},
 		},
@@ -271,8 +275,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 				"registry-1.docker.io",
 				"--username",
 				"user",
-				"--password",
-				"pass",
+				"--password-stdin",
 				"--ca-file",
 				"certs/apache.crt",
 			},
@@ -283,11 +286,20 @@ func TestRegistryLoginCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := registryLoginCommand(test.host, test.repo, certsDir, &buf)
+			cmd := registryLoginCommand(context.Background(), test.host, test.repo, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
 			assert.Equal(t, &buf, cmd.Stderr)
+
+			if test.repo.Authentication.Password != "" {
+				require.NotNil(t, cmd.Stdin)
+				password, err := io.ReadAll(cmd.Stdin)
+				require.NoError(t, err)
+				assert.Equal(t, test.repo.Authentication.Password, string(password))
+			} else {
+				assert.Nil(t, cmd.Stdin)
+			}
 		})
 	}
 }
@@ -487,7 +499,7 @@ func TestPullCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := pullCommand(test.chart, test.repo, test.version, test.destDir, certsDir, &buf)
+			cmd := pullCommand(context.Background(), test.chart, test.repo, test.version, test.destDir, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
@@ -557,7 +569,7 @@ func TestTemplateCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := templateCommand(test.chart, test.repo, test.version, test.valuesPath, test.kubeVersion, test.targetNamespace, test.apiVersions, &stdout, &stderr)
+			cmd := templateCommand(context.Background(), test.chart, test.repo, test.version, test.valuesPath, test.kubeVersion, test.targetNamespace, test.apiVersions, &stdout, &stderr)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &stdout, cmd.Stdout)
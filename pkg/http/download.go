@@ -2,6 +2,8 @@ package http
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -43,8 +45,10 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 	}
 	defer file.Close()
 
+	hash := sha256.New()
+
 	var writers []io.Writer
-	writers = append(writers, file)
+	writers = append(writers, file, hash)
 
 	if cache != nil {
 		writers = append(writers, cache)
@@ -65,6 +69,8 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 		return fmt.Errorf("storing response: %w", err)
 	}
 
+	recordDownload(url, hex.EncodeToString(hash.Sum(nil)))
+
 	zap.S().Infof("Downloading file '%s' completed", filename)
 
 	return nil
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Download describes a file which was downloaded during the current run.
+type Download struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+var downloads struct {
+	sync.Mutex
+	list []Download
+}
+
+// Downloads returns all files downloaded so far in the order the downloads completed.
+func Downloads() []Download {
+	downloads.Lock()
+	defer downloads.Unlock()
+
+	return slices.Clone(downloads.list)
+}
+
+// RestoreDownloads registers files which were downloaded by a previous build whose output is reused.
+func RestoreDownloads(restored ...Download) {
+	downloads.Lock()
+	defer downloads.Unlock()
+
+	downloads.list = append(downloads.list, restored...)
+}
+
+// RecordDownload registers a file which was previously downloaded from the given URL
+// and is reused instead of being downloaded again, e.g. because it was cached.
+func RecordDownload(url, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	recordDownload(url, hex.EncodeToString(hash.Sum(nil)))
+	return nil
+}
+
+func recordDownload(url, checksum string) {
+	downloads.Lock()
+	defer downloads.Unlock()
+
+	downloads.list = append(downloads.list, Download{URL: url, SHA256: checksum})
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadFile_RecordsDownload(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, _ *nethttp.Request) {
+		_, _ = fmt.Fprint(w, "image")
+	}))
+	defer server.Close()
+
+	url := server.URL + "/image.raw"
+	require.NoError(t, DownloadFile(context.Background(), url, filepath.Join(t.TempDir(), "image.raw"), nil))
+
+	assert.Contains(t, Downloads(), Download{
+		URL:    url,
+		SHA256: "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d",
+	})
+}
+
+func TestRecordDownload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "k3s")
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+
+	require.NoError(t, RecordDownload("https://example.com/k3s", path))
+
+	assert.Contains(t, Downloads(), Download{
+		URL:    "https://example.com/k3s",
+		SHA256: "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d",
+	})
+}
+
+func TestRecordDownload_MissingFile(t *testing.T) {
+	err := RecordDownload("https://example.com/k3s", filepath.Join(t.TempDir(), "k3s"))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "opening file: ")
+}
+
+func TestRestoreDownloads(t *testing.T) {
+	restored := Download{URL: "https://example.com/rke2", SHA256: "c1"}
+
+	RestoreDownloads(restored)
+
+	assert.Contains(t, Downloads(), restored)
+}
@@ -1,6 +1,13 @@
 package image
 
-import "path/filepath"
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/checkpoint"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+)
 
 type LocalRPMConfig struct {
 	// RPMPath is the path to the directory holding RPMs that will be side-loaded
@@ -10,6 +17,9 @@ type LocalRPMConfig struct {
 }
 
 type Context struct {
+	// Context is cancelled when the build is interrupted, terminating the running commands and downloads.
+	// It is only set by the commands which can be interrupted and is read through BuildContext.
+	Context context.Context
 	// ImageConfigDir is the root directory storing all configuration files.
 	ImageConfigDir string
 	// BuildDir is the directory used for assembling the different components used in a build.
@@ -18,6 +28,16 @@ type Context struct {
 	CombustionDir string
 	// ArtefactsDir is a subdirectory under BuildDir containing the larger Combustion related files.
 	ArtefactsDir string
+	// DefinitionFile is the path to the image definition file.
+	DefinitionFile string
+	// ArtifactSourcesFile is the path to the file the ArtifactSources are loaded from.
+	ArtifactSourcesFile string
+	// ProvenanceKey is the path to the private key used to sign the provenance attestation.
+	// The attestation is left unsigned if it is empty.
+	ProvenanceKey string
+	// SigningKey is the path to the private key used to create a detached signature of the output.
+	// The output is left unsigned if it is empty.
+	SigningKey string
 	// ImageDefinition contains the image definition properties.
 	ImageDefinition *Definition
 	// ArtifactSources contains the information necessary for the deployment of external artifacts.
@@ -26,6 +46,19 @@ type Context struct {
 	CacheDir string
 	// IsConfigDrive defines whether this is an image or config drive build
 	IsConfigDrive bool
+	// ReuseBuildDir is the directory of a previous build whose output is reused for every combustion
+	// component with unchanged inputs. All components are configured if it is empty.
+	ReuseBuildDir string
+	// Checkpoints tracks the completed stages of the build, which are skipped when it is resumed.
+	// Builds which can not be resumed, such as config drives, leave it nil.
+	Checkpoints *checkpoint.Checkpoints
+	// Preflight defines whether the combustion scripts are run in a container sandbox before the image is built.
+	Preflight bool
+	// SourceDateEpoch is the timestamp applied to every file of a reproducible config drive.
+	// Config drives keep the metadata of the generated files if it is nil.
+	SourceDateEpoch *time.Time
+	// SBOM collects the software components shipped with the image while it is being configured.
+	SBOM sbom.Inventory
 }
 
 type ArtifactSources struct {
@@ -59,3 +92,13 @@ func (c *Context) OutputPath() string {
 	filename := filepath.Join(c.ImageConfigDir, c.ImageDefinition.Image.OutputImageName)
 	return filename
 }
+
+// BuildContext returns the context which is cancelled when the build is interrupted,
+// defaulting to one which is never cancelled if it was not set.
+func (c *Context) BuildContext() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+
+	return c.Context
+}
@@ -0,0 +1,19 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildContext(t *testing.T) {
+	ctx := &Context{}
+	assert.Equal(t, context.Background(), ctx.BuildContext())
+
+	buildCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx.Context = buildCtx
+	assert.Equal(t, buildCtx, ctx.BuildContext())
+}
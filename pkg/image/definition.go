@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,13 +14,26 @@ import (
 )
 
 const (
-	TypeISO = "iso"
-	TypeRAW = "raw"
-	TypeTar = "tar"
+	TypeISO   = "iso"
+	TypeRAW   = "raw"
+	TypeQCOW2 = "qcow2"
+	TypePXE   = "pxe"
+	TypeTar   = "tar"
 
 	ArchTypeX86 Arch = "x86_64"
 	ArchTypeARM Arch = "aarch64"
 
+	CompressionXZ   = "xz"
+	CompressionZstd = "zstd"
+	CompressionGzip = "gzip"
+
+	FilesystemExt4  = "ext4"
+	FilesystemXFS   = "xfs"
+	FilesystemBtrfs = "btrfs"
+
+	ContainerDiskFormatOCI        = "oci"
+	ContainerDiskFormatOCIArchive = "oci-archive"
+
 	KubernetesDistroRKE2 = "rke2"
 	KubernetesDistroK3S  = "k3s"
 
@@ -33,16 +47,35 @@ const (
 	IngressTypeTraefik = "traefik"
 )
 
+// DiskSizePattern describes the accepted format of the RAW disk size, e.g. '32G'.
+const DiskSizePattern = `^([1-9]\d+|[1-9])+([MGT])`
+
+// DiskSizeAuto sizes the RAW disk based on the base image and the contents added to it during the build.
+const DiskSizeAuto DiskSize = "auto"
+
+// DefaultDiskSizeHeadroom is the percentage of free space added to an automatically calculated disk size.
+const DefaultDiskSizeHeadroom = 10
+
+// PartitionLabelPattern describes the accepted format of partition labels, which fits the limits of all supported filesystems.
+const PartitionLabelPattern = `^[a-zA-Z0-9_-]{1,12}$`
+
 var (
-	diskSizeRegexp = regexp.MustCompile(`^([1-9]\d+|[1-9])+([MGT])`)
+	diskSizeRegexp       = regexp.MustCompile(DiskSizePattern)
+	partitionLabelRegexp = regexp.MustCompile(PartitionLabelPattern)
 )
 
 type Definition struct {
 	APIVersion               string                   `yaml:"apiVersion"`
+	Extends                  string                   `yaml:"extends"`
 	Image                    Image                    `yaml:"image"`
 	OperatingSystem          OperatingSystem          `yaml:"operatingSystem"`
 	EmbeddedArtifactRegistry EmbeddedArtifactRegistry `yaml:"embeddedArtifactRegistry"`
 	Kubernetes               Kubernetes               `yaml:"kubernetes"`
+
+	// positions maps the paths of the fields to their location in the definition source.
+	positions map[string]Position
+	// baseDefinitions lists the files the definition extends, starting with the one extended directly.
+	baseDefinitions []string
 }
 
 type Arch string
@@ -60,10 +93,25 @@ func (a Arch) Short() string {
 }
 
 type Image struct {
-	ImageType       string `yaml:"imageType"`
-	Arch            Arch   `yaml:"arch"`
-	BaseImage       string `yaml:"baseImage"`
-	OutputImageName string `yaml:"outputImageName"`
+	ImageType       string        `yaml:"imageType"`
+	Arch            Arch          `yaml:"arch"`
+	BaseImage       string        `yaml:"baseImage"`
+	OutputImageName string        `yaml:"outputImageName"`
+	ContainerDisk   ContainerDisk `yaml:"containerDisk"`
+	Compression     Compression   `yaml:"compression"`
+}
+
+// Compression configures compressing the output after it has been built. A level of 0 selects
+// the default level of the compression type.
+type Compression struct {
+	Type  string `yaml:"type"`
+	Level int    `yaml:"level"`
+}
+
+// ContainerDisk configures wrapping the built disk image into a KubeVirt containerDisk.
+type ContainerDisk struct {
+	Format string `yaml:"format"`
+	Tag    string `yaml:"tag"`
 }
 
 type OperatingSystem struct {
@@ -91,6 +139,10 @@ func (d DiskSize) IsValid() bool {
 	return diskSizeRegexp.MatchString(string(d))
 }
 
+func (d DiskSize) IsAuto() bool {
+	return d == DiskSizeAuto
+}
+
 func (d DiskSize) ToMB() int64 {
 	if d == "" {
 		return 0
@@ -120,10 +172,34 @@ func (d DiskSize) ToMB() int64 {
 	}
 }
 
+// IsValidPartitionLabel reports whether the label can be used as the partition, filesystem and LUKS mapping name.
+func IsValidPartitionLabel(label string) bool {
+	return partitionLabelRegexp.MatchString(label)
+}
+
 type RawConfiguration struct {
 	DiskSize                 DiskSize `yaml:"diskSize"`
 	LUKSKey                  string   `yaml:"luksKey"`
 	ExpandEncryptedPartition bool     `yaml:"expandEncryptedPartition"`
+	// DiskSizeHeadroom is the percentage of free space added on top of an automatically calculated disk size.
+	// DefaultDiskSizeHeadroom is used if it is not set, while an explicit 0 adds no free space.
+	DiskSizeHeadroom *int `yaml:"diskSizeHeadroom"`
+	// MinimumDiskSize is the lower bound of an automatically calculated disk size.
+	MinimumDiskSize DiskSize `yaml:"minimumDiskSize"`
+	// RootSize limits the size of the root partition, leaving the rest of the disk to the additional partitions.
+	RootSize   DiskSize       `yaml:"rootSize"`
+	Partitions []RawPartition `yaml:"partitions"`
+}
+
+// RawPartition describes an additional partition created after the root partition.
+type RawPartition struct {
+	Label string `yaml:"label"`
+	// Size of the partition. The last partition takes up the remaining space on the disk if it is not set.
+	Size       DiskSize `yaml:"size"`
+	Filesystem string   `yaml:"filesystem"`
+	MountPoint string   `yaml:"mountPoint"`
+	// LUKSKey encrypts the partition if set. The key is stored on the root filesystem to unlock it at boot.
+	LUKSKey string `yaml:"luksKey"`
 }
 
 type Packages struct {
@@ -258,13 +334,36 @@ type HelmAuthentication struct {
 
 var ErrorInvalidSchemaVersion = errors.New("invalid schema version")
 
-func ParseDefinition(data []byte) (*Definition, error) {
+func ParseDefinition(data []byte, configDir string) (*Definition, error) {
+	return parseDefinition(data, configDir, true)
+}
+
+// ParseUnresolvedDefinition parses the image definition without resolving its secret references,
+// e.g. for migrating it without access to the secrets.
+func ParseUnresolvedDefinition(data []byte, configDir string) (*Definition, error) {
+	return parseDefinition(data, configDir, false)
+}
+
+// parseDefinition parses the image definition, resolving its secret references only if requested.
+// Unresolved references are kept as they are written in the definition.
+func parseDefinition(data []byte, configDir string, resolve bool) (*Definition, error) {
 	var definition Definition
 
-	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	source, err := resolveDefinitionSource(data, configDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the image definition: %w", err)
+	}
+
+	if source.node != nil {
+		if unknown := findUnknownFields(source.node, reflect.TypeOf(definition), "", source.sources); len(unknown) > 0 {
+			return nil, fmt.Errorf("could not parse the image definition: %w", &UnknownFieldsError{Fields: unknown})
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(source.data))
 	decoder.KnownFields(true)
 
-	if err := decoder.Decode(&definition); err != nil {
+	if err = decoder.Decode(&definition); err != nil {
 		return nil, fmt.Errorf("could not parse the image definition: %w", err)
 	}
 	definition.Image.ImageType = strings.ToLower(definition.Image.ImageType)
@@ -273,5 +372,18 @@ func ParseDefinition(data []byte) (*Definition, error) {
 		return nil, ErrorInvalidSchemaVersion
 	}
 
+	if resolve {
+		if err = resolveSecrets(&definition, configDir); err != nil {
+			return nil, fmt.Errorf("resolving secrets: %w", err)
+		}
+	}
+
+	definition.baseDefinitions = source.bases
+
+	definition.positions = map[string]Position{}
+	if source.node != nil {
+		indexPositions(source.node, "", source.sources, definition.positions)
+	}
+
 	return &definition, nil
 }
@@ -15,7 +15,7 @@ func TestParse(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test
-	definition, err := ParseDefinition(configData)
+	definition, err := ParseDefinition(configData, "")
 
 	// Verify
 	require.NoError(t, err)
@@ -243,7 +243,7 @@ func TestParseBadConfig_InvalidFormat(t *testing.T) {
 	badData := []byte("Not actually YAML")
 
 	// Test
-	_, err := ParseDefinition(badData)
+	_, err := ParseDefinition(badData, "")
 
 	// Verify
 	require.Error(t, err)
@@ -261,12 +261,77 @@ operatingSystem:
     zone: Europe/London
 `
 
-	_, err := ParseDefinition([]byte(badConfig))
+	_, err := ParseDefinition([]byte(badConfig), "")
 
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "could not parse the image definition")
-	assert.ErrorContains(t, err, "line 4: field type not found in type image.Image")
-	assert.ErrorContains(t, err, "line 7: field zone not found in type image.Time")
+	assert.ErrorContains(t, err, "line 4: unknown field 'image.type'")
+	assert.ErrorContains(t, err, "line 7: unknown field 'operatingSystem.time.zone'")
+}
+
+func TestParseBadConfig_UnknownFieldSuggestions(t *testing.T) {
+	badConfig := `
+apiVersion: 1.4
+image:
+  imagetype: iso
+kubernetes:
+  helm:
+    charts:
+      - name: metallb
+        valuesFile: metallb.yaml
+      - name: rancher
+        valueFile: rancher.yaml
+        verison: 2.8.4
+`
+
+	_, err := ParseDefinition([]byte(badConfig), "")
+	require.Error(t, err)
+
+	var unknownFieldsErr *UnknownFieldsError
+	require.ErrorAs(t, err, &unknownFieldsErr)
+
+	expected := []UnknownField{
+		{
+			Path:       "image.imagetype",
+			Position:   Position{Line: 4, Column: 3},
+			Suggestion: "imageType",
+		},
+		{
+			Path:       "kubernetes.helm.charts[1].valueFile",
+			Position:   Position{Line: 11, Column: 9},
+			Suggestion: "valuesFile",
+		},
+		{
+			Path:       "kubernetes.helm.charts[1].verison",
+			Position:   Position{Line: 12, Column: 9},
+			Suggestion: "version",
+		},
+	}
+	assert.Equal(t, expected, unknownFieldsErr.Fields)
+	assert.ErrorContains(t, err, "line 11: unknown field 'kubernetes.helm.charts[1].valueFile', did you mean 'valuesFile'?")
+}
+
+func TestDefinition_Position(t *testing.T) {
+	config := `
+apiVersion: 1.4
+kubernetes:
+  helm:
+    charts:
+      - name: metallb
+        repositoryName: suse-edge
+      - name: rancher
+        valuesFile: rancher.yaml
+`
+
+	definition, err := ParseDefinition([]byte(config), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, Position{Line: 9, Column: 9}, definition.Position("kubernetes.helm.charts[1].valuesFile"))
+	assert.Equal(t, Position{Line: 6, Column: 9}, definition.Position("kubernetes.helm.charts[0]"))
+	// Missing fields are reported at their closest parent
+	assert.Equal(t, Position{Line: 8, Column: 9}, definition.Position("kubernetes.helm.charts[1].version"))
+	assert.Equal(t, Position{Line: 3, Column: 1}, definition.Position("kubernetes.network.apiVIP"))
+	assert.False(t, definition.Position("operatingSystem.users").IsKnown())
 }
 
 func TestParseDefinition_InvalidSchemaVersion(t *testing.T) {
@@ -274,7 +339,7 @@ func TestParseDefinition_InvalidSchemaVersion(t *testing.T) {
 apiVersion: 10.0
 `
 
-	_, err := ParseDefinition([]byte(badConfig))
+	_, err := ParseDefinition([]byte(badConfig), "")
 
 	require.ErrorIs(t, err, ErrorInvalidSchemaVersion)
 }
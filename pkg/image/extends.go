@@ -0,0 +1,340 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const extendsKey = "extends"
+
+type listMergeStrategy int
+
+const (
+	// listReplace replaces the base list with the extending list entirely.
+	listReplace listMergeStrategy = iota
+	// listAppend appends the entries of the extending list which are not already present in the base list.
+	listAppend
+	// listMergeByKey deep merges entries sharing the same key and appends the rest.
+	listMergeByKey
+)
+
+type listMergeRule struct {
+	strategy listMergeStrategy
+	// key is the field used to identify entries when merging by key.
+	key string
+}
+
+// Lists which are not specified here are replaced by the extending definition.
+var listMergeRules = map[string]listMergeRule{
+	"operatingSystem.kernelArgs":               {strategy: listAppend},
+	"operatingSystem.groups":                   {strategy: listMergeByKey, key: "name"},
+	"operatingSystem.users":                    {strategy: listMergeByKey, key: "username"},
+	"operatingSystem.users.sshKeys":            {strategy: listAppend},
+	"operatingSystem.users.secondaryGroups":    {strategy: listAppend},
+	"operatingSystem.systemd.enable":           {strategy: listAppend},
+	"operatingSystem.systemd.disable":          {strategy: listAppend},
+	"operatingSystem.packages.packageList":     {strategy: listAppend},
+	"operatingSystem.packages.additionalRepos": {strategy: listMergeByKey, key: "url"},
+	"operatingSystem.proxy.noProxy":            {strategy: listAppend},
+	"embeddedArtifactRegistry.images":          {strategy: listMergeByKey, key: "name"},
+	"embeddedArtifactRegistry.registries":      {strategy: listMergeByKey, key: "uri"},
+	"kubernetes.manifests.urls":                {strategy: listAppend},
+	"kubernetes.helm.charts":                   {strategy: listMergeByKey, key: "name"},
+	"kubernetes.helm.charts.apiVersions":       {strategy: listAppend},
+	"kubernetes.helm.repositories":             {strategy: listMergeByKey, key: "name"},
+}
+
+var ErrorCyclicExtends = errors.New("cyclic definition inheritance")
+
+// ResolveDefinition returns the image definition data after recursively merging it
+// on top of the base definitions referenced through the 'extends' key.
+// Base definitions are looked up relative to the image configuration directory.
+// The data is returned as is if the definition does not extend another one.
+func ResolveDefinition(data []byte, configDir string) ([]byte, error) {
+	source, err := resolveDefinitionSource(data, configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.data, nil
+}
+
+// BaseDefinitions returns the files of the base definitions the definition extends, relative to the
+// image configuration directory and starting with the one extended directly.
+func (d *Definition) BaseDefinitions() []string {
+	return d.baseDefinitions
+}
+
+// definitionSource holds the resolved definition data along with its YAML node tree.
+type definitionSource struct {
+	data []byte
+	// node is nil if the data does not hold a mapping.
+	node *yaml.Node
+	// sources maps the nodes originating from base definitions to their files.
+	sources map[*yaml.Node]string
+	// bases lists the files of the base definitions, starting with the one extended directly.
+	bases []string
+}
+
+func resolveDefinitionSource(data []byte, configDir string) (*definitionSource, error) {
+	definition, err := parseDefinitionNode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &definitionSource{
+		data:    data,
+		node:    definition,
+		sources: map[*yaml.Node]string{},
+	}
+
+	if definition == nil || extendsValue(definition) == "" {
+		// Let the typed decoding report any issues with the original data
+		return source, nil
+	}
+
+	resolved, err := resolveExtends(definition, configDir, map[string]bool{}, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(resolved); err != nil {
+		return nil, fmt.Errorf("encoding resolved definition: %w", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoding resolved definition: %w", err)
+	}
+
+	source.data = buf.Bytes()
+	source.node = resolved
+
+	return source, nil
+}
+
+func parseDefinitionNode(data []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("could not parse the image definition: %w", err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	return document.Content[0], nil
+}
+
+func extendsValue(definition *yaml.Node) string {
+	if n := mappingValue(definition, extendsKey); n != nil {
+		return n.Value
+	}
+
+	return ""
+}
+
+func resolveExtends(definition *yaml.Node, configDir string, visited map[string]bool, source *definitionSource) (*yaml.Node, error) {
+	extends := extendsValue(definition)
+	if extends == "" {
+		return definition, nil
+	}
+
+	basePath, err := extendsPath(configDir, extends)
+	if err != nil {
+		return nil, err
+	}
+
+	if visited[basePath] {
+		return nil, fmt.Errorf("%w: '%s' is extended more than once", ErrorCyclicExtends, extends)
+	}
+	visited[basePath] = true
+
+	baseData, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading base definition '%s': %w", extends, err)
+	}
+
+	base, err := parseDefinitionNode(baseData)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base definition '%s': %w", extends, err)
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("base definition '%s' is not a valid image definition", extends)
+	}
+
+	registerSources(base, extends, source.sources)
+	source.bases = append(source.bases, extends)
+
+	if base, err = resolveExtends(base, configDir, visited, source); err != nil {
+		return nil, fmt.Errorf("resolving base definition '%s': %w", extends, err)
+	}
+
+	return mergeMappings(base, definition, ""), nil
+}
+
+func extendsPath(configDir, extends string) (string, error) {
+	if filepath.IsAbs(extends) {
+		return "", fmt.Errorf("base definition '%s' must be relative to the image configuration directory", extends)
+	}
+
+	path := filepath.Join(configDir, extends)
+
+	rel, err := filepath.Rel(configDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("base definition '%s' must be located within the image configuration directory", extends)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// mergeMappings deep merges the override mapping node on top of the base one.
+// Scalars and mismatching node kinds are overridden while lists follow the rules in listMergeRules.
+func mergeMappings(base, override *yaml.Node, path string) *yaml.Node {
+	merged := &yaml.Node{
+		Kind:        yaml.MappingNode,
+		Tag:         base.Tag,
+		Style:       base.Style,
+		HeadComment: base.HeadComment,
+		Line:        base.Line,
+		Column:      base.Column,
+	}
+	merged.Content = append(merged.Content, base.Content...)
+
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+
+		index := -1
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				index = j
+				break
+			}
+		}
+
+		if index == -1 {
+			merged.Content = append(merged.Content, key, value)
+			continue
+		}
+
+		merged.Content[index] = key
+		merged.Content[index+1] = mergeNodes(merged.Content[index+1], value, joinPath(path, key.Value))
+	}
+
+	return merged
+}
+
+func mergeNodes(base, override *yaml.Node, path string) *yaml.Node {
+	if base.Kind != override.Kind {
+		return override
+	}
+
+	switch override.Kind {
+	case yaml.MappingNode:
+		return mergeMappings(base, override, path)
+	case yaml.SequenceNode:
+		return mergeSequences(base, override, path)
+	default:
+		return override
+	}
+}
+
+func mergeSequences(base, override *yaml.Node, path string) *yaml.Node {
+	rule := listMergeRules[path]
+
+	switch rule.strategy {
+	case listAppend:
+		merged := *override
+		merged.Content = append([]*yaml.Node{}, base.Content...)
+
+		for _, item := range override.Content {
+			if !containsScalar(merged.Content, item) {
+				merged.Content = append(merged.Content, item)
+			}
+		}
+
+		return &merged
+	case listMergeByKey:
+		merged := *override
+		merged.Content = append([]*yaml.Node{}, base.Content...)
+
+		for _, item := range override.Content {
+			index := indexByKey(merged.Content, item, rule.key)
+			if index == -1 {
+				merged.Content = append(merged.Content, item)
+				continue
+			}
+
+			merged.Content[index] = mergeNodes(merged.Content[index], item, path)
+		}
+
+		return &merged
+	default:
+		return override
+	}
+}
+
+func containsScalar(nodes []*yaml.Node, item *yaml.Node) bool {
+	if item.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	for _, n := range nodes {
+		if n.Kind == yaml.ScalarNode && n.Value == item.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indexByKey(nodes []*yaml.Node, item *yaml.Node, key string) int {
+	if item.Kind != yaml.MappingNode {
+		return -1
+	}
+
+	itemKey := mappingValue(item, key)
+	if itemKey == nil || itemKey.Value == "" {
+		return -1
+	}
+
+	for i, n := range nodes {
+		if n.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if k := mappingValue(n, key); k != nil && k.Value == itemKey.Value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,130 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefinition_Extends(t *testing.T) {
+	configDir := filepath.Join("testdata", "extends")
+
+	data, err := os.ReadFile(filepath.Join(configDir, "site.yaml"))
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(data, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", definition.APIVersion)
+	assert.Equal(t, "rke2.yaml", definition.Extends)
+	assert.Equal(t, []string{"rke2.yaml", "common.yaml"}, definition.BaseDefinitions())
+
+	// Scalars are inherited unless overridden
+	assert.Equal(t, TypeRAW, definition.Image.ImageType)
+	assert.Equal(t, ArchTypeX86, definition.Image.Arch)
+	assert.Equal(t, "sl-micro6.0.raw", definition.Image.BaseImage)
+	assert.Equal(t, "site.raw", definition.Image.OutputImageName)
+	assert.Equal(t, "us", definition.OperatingSystem.Keymap)
+
+	// Appended lists
+	assert.Equal(t, []string{"alpha=foo", "beta=bar"}, definition.OperatingSystem.KernelArgs)
+	assert.Equal(t, []string{"wget2", "libatomic1"}, definition.OperatingSystem.Packages.PKGList)
+
+	// Replaced lists
+	assert.Equal(t, []string{"10.0.0.2"}, definition.OperatingSystem.Time.NtpConfiguration.Servers)
+	require.Len(t, definition.Kubernetes.Nodes, 1)
+	assert.Equal(t, "node2.suse.com", definition.Kubernetes.Nodes[0].Hostname)
+
+	// Lists merged by key
+	users := definition.OperatingSystem.Users
+	require.Len(t, users, 3)
+	assert.Equal(t, "alpha", users[0].Username)
+	assert.Equal(t, "alpha-password", users[0].EncryptedPassword)
+	assert.Equal(t, []string{"ssh-rsa AAAA", "ssh-rsa BBBB"}, users[0].SSHKeys)
+	assert.True(t, users[0].CreateHomeDir)
+	assert.Equal(t, "beta", users[1].Username)
+	assert.Equal(t, "gamma", users[2].Username)
+	assert.Equal(t, "gamma-password", users[2].EncryptedPassword)
+
+	charts := definition.Kubernetes.Helm.Charts
+	require.Len(t, charts, 2)
+	assert.Equal(t, "apache", charts[0].Name)
+	assert.Equal(t, "bitnami", charts[0].RepositoryName)
+	assert.Equal(t, "10.8.0", charts[0].Version)
+	assert.Equal(t, "apache-values.yaml", charts[0].ValuesFile)
+	assert.Equal(t, "metallb", charts[1].Name)
+
+	repos := definition.Kubernetes.Helm.Repositories
+	require.Len(t, repos, 2)
+	assert.Equal(t, "bitnami", repos[0].Name)
+	assert.Equal(t, "suse-edge", repos[1].Name)
+}
+
+func TestParseDefinition_ExtendsPositions(t *testing.T) {
+	configDir := filepath.Join("testdata", "extends")
+
+	data, err := os.ReadFile(filepath.Join(configDir, "site.yaml"))
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(data, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, Position{Line: 4, Column: 3}, definition.Position("image.outputImageName"))
+	assert.Equal(t, Position{File: "rke2.yaml", Line: 3, Column: 3}, definition.Position("operatingSystem.keymap"))
+	// Entries merged by key are located in the extending definition
+	assert.Equal(t, Position{Line: 9, Column: 7}, definition.Position("operatingSystem.users[0]"))
+	assert.Equal(t, Position{File: "common.yaml", Line: 14, Column: 7}, definition.Position("operatingSystem.users[0].createHomeDir"))
+	assert.Equal(t, Position{File: "common.yaml", Line: 15, Column: 7}, definition.Position("operatingSystem.users[1].username"))
+	assert.Equal(t, Position{Line: 12, Column: 7}, definition.Position("operatingSystem.users[2]"))
+}
+
+func TestParseDefinition_ExtendsCycle(t *testing.T) {
+	configDir := filepath.Join("testdata", "extends")
+
+	data, err := os.ReadFile(filepath.Join(configDir, "cyclic-a.yaml"))
+	require.NoError(t, err)
+
+	_, err = ParseDefinition(data, configDir)
+	require.ErrorIs(t, err, ErrorCyclicExtends)
+}
+
+func TestParseDefinition_ExtendsInvalidPath(t *testing.T) {
+	tests := map[string]struct {
+		extends       string
+		expectedError string
+	}{
+		`missing base`: {
+			extends:       "missing.yaml",
+			expectedError: "reading base definition 'missing.yaml'",
+		},
+		`outside config dir`: {
+			extends:       "../definition.yaml",
+			expectedError: "base definition '../definition.yaml' must be located within the image configuration directory",
+		},
+		`absolute path`: {
+			extends:       "/etc/definition.yaml",
+			expectedError: "base definition '/etc/definition.yaml' must be relative to the image configuration directory",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := []byte("apiVersion: 1.4\nextends: " + test.extends + "\n")
+
+			_, err := ParseDefinition(data, filepath.Join("testdata", "extends"))
+			require.Error(t, err)
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestResolveDefinition_NoExtends(t *testing.T) {
+	data := []byte("apiVersion: 1.3\n# comment\nimage:\n  arch: x86_64\n")
+
+	resolved, err := ResolveDefinition(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, data, resolved)
+}
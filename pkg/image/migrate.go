@@ -0,0 +1,214 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultRepositoryPriority = 99
+
+// MigrationStep describes the changes applied to a definition when upgrading it between two consecutive API versions.
+type MigrationStep struct {
+	From    string
+	To      string
+	Changes []string
+}
+
+type migration struct {
+	from    string
+	to      string
+	migrate func(definition *yaml.Node) []string
+}
+
+// Ordered migrations between each of the supported API versions.
+var migrations = []migration{
+	{from: "1.0", to: "1.1", migrate: migrateFIPS},
+	{from: "1.1", to: "1.2", migrate: migrateHelmReleaseNames},
+	{from: "1.2", to: "1.3", migrate: migrateRepositoryPriorities},
+	{from: "1.3", to: "1.4"},
+}
+
+// MigrateDefinition upgrades the image definition to the latest supported API version.
+// The definition is modified in place on the YAML node level so that comments and ordering are preserved
+// as much as possible. Both the original and the migrated definitions are verified by parsing them.
+// Secret references are not resolved, so that definitions can be migrated without access to the secrets.
+func MigrateDefinition(data []byte, configDir string) ([]byte, []MigrationStep, error) {
+	definition, err := parseDefinition(data, configDir, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var document yaml.Node
+	if err = yaml.Unmarshal(data, &document); err != nil {
+		return nil, nil, fmt.Errorf("could not parse the image definition: %w", err)
+	}
+
+	root := document.Content[0]
+
+	var steps []MigrationStep
+
+	current := definition.APIVersion
+	for _, m := range migrations {
+		if m.from != current {
+			continue
+		}
+
+		step := MigrationStep{From: m.from, To: m.to}
+		if m.migrate != nil {
+			step.Changes = m.migrate(root)
+		}
+
+		steps = append(steps, step)
+		current = m.to
+	}
+
+	if len(steps) == 0 {
+		return data, nil, nil
+	}
+
+	if apiVersion := mappingValue(root, "apiVersion"); apiVersion != nil {
+		apiVersion.Value = current
+	} else {
+		// The version was inherited through 'extends', it is set explicitly as it may differ from the base definition
+		root.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "apiVersion"},
+			{Kind: yaml.ScalarNode, Tag: "!!float", Value: current},
+		}, root.Content...)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(&document); err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated definition: %w", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return nil, nil, fmt.Errorf("encoding migrated definition: %w", err)
+	}
+
+	migrated := buf.Bytes()
+	if _, err = parseDefinition(migrated, configDir, false); err != nil {
+		return nil, nil, fmt.Errorf("verifying migrated definition: %w", err)
+	}
+
+	return migrated, steps, nil
+}
+
+// migrateFIPS replaces FIPS mode specified through kernel arguments and packages with the 'enableFIPS' option.
+func migrateFIPS(definition *yaml.Node) []string {
+	operatingSystem := mappingValue(definition, "operatingSystem")
+	if operatingSystem == nil || operatingSystem.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	kernelArgs := mappingValue(operatingSystem, "kernelArgs")
+	if kernelArgs == nil || !removeScalar(kernelArgs, "fips=1") {
+		return nil
+	}
+
+	changes := []string{"Removed 'fips=1' from 'operatingSystem.kernelArgs'"}
+
+	if packages := mappingValue(operatingSystem, "packages"); packages != nil {
+		if packageList := mappingValue(packages, "packageList"); packageList != nil {
+			for _, p := range []string{"pattern:fips", "patterns-base-fips"} {
+				if removeScalar(packageList, p) {
+					changes = append(changes, fmt.Sprintf("Removed '%s' from 'operatingSystem.packages.packageList'", p))
+				}
+			}
+		}
+	}
+
+	setMappingValue(operatingSystem, "enableFIPS", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+	changes = append(changes, "Set 'operatingSystem.enableFIPS' to 'true' which installs the FIPS packages and sets the kernel argument")
+
+	return changes
+}
+
+// migrateHelmReleaseNames makes the release names of Helm charts explicit, which previously always matched the chart name.
+func migrateHelmReleaseNames(definition *yaml.Node) []string {
+	charts := nestedValue(definition, "kubernetes", "helm", "charts")
+	if charts == nil || charts.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var changes []string
+
+	for i, chart := range charts.Content {
+		name := mappingValue(chart, "name")
+		if chart.Kind != yaml.MappingNode || name == nil || mappingValue(chart, "releaseName") != nil {
+			continue
+		}
+
+		setMappingValue(chart, "releaseName", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name.Value})
+		changes = append(changes, fmt.Sprintf("Set 'kubernetes.helm.charts[%d].releaseName' to '%s'", i, name.Value))
+	}
+
+	return changes
+}
+
+// migrateRepositoryPriorities makes the priority of additional repositories explicit, which previously always used the default.
+func migrateRepositoryPriorities(definition *yaml.Node) []string {
+	repositories := nestedValue(definition, "operatingSystem", "packages", "additionalRepos")
+	if repositories == nil || repositories.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var changes []string
+
+	for i, repository := range repositories.Content {
+		if repository.Kind != yaml.MappingNode || mappingValue(repository, "priority") != nil {
+			continue
+		}
+
+		priority := strconv.Itoa(defaultRepositoryPriority)
+		setMappingValue(repository, "priority", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: priority})
+		changes = append(changes, fmt.Sprintf("Set 'operatingSystem.packages.additionalRepos[%d].priority' to '%s'", i, priority))
+	}
+
+	return changes
+}
+
+func nestedValue(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		node = mappingValue(node, key)
+	}
+
+	return node
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func removeScalar(sequence *yaml.Node, value string) bool {
+	if sequence.Kind != yaml.SequenceNode {
+		return false
+	}
+
+	i := slices.IndexFunc(sequence.Content, func(n *yaml.Node) bool {
+		return n.Kind == yaml.ScalarNode && n.Value == value
+	})
+	if i == -1 {
+		return false
+	}
+
+	sequence.Content = slices.Delete(sequence.Content, i, i+1)
+	return true
+}
@@ -0,0 +1,194 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+func TestMigrations_CoverSupportedVersions(t *testing.T) {
+	require.Len(t, migrations, len(version.SupportedSchemaVersions)-1)
+
+	for i, m := range migrations {
+		assert.Equal(t, version.SupportedSchemaVersions[i], m.from)
+		assert.Equal(t, version.SupportedSchemaVersions[i+1], m.to)
+	}
+}
+
+func TestMigrateDefinition(t *testing.T) {
+	data := []byte(`# Edge cluster definition
+apiVersion: 1.0
+image:
+  imageType: iso
+  arch: x86_64
+  baseImage: slemicro.iso
+  outputImageName: eib-image.iso
+operatingSystem:
+  kernelArgs:
+    - fips=1
+    - console=ttyS0 # serial console
+  packages:
+    packageList:
+      - pattern:fips
+      - vim
+    additionalRepos:
+      - url: https://download.opensuse.org/update/leap/15.6/sle
+      - url: https://download.opensuse.org/distribution/leap/15.6/repo/oss
+        priority: 10
+kubernetes:
+  version: v1.30.3+rke2r1
+  helm:
+    charts:
+      # Load balancer
+      - name: metallb
+        repositoryName: suse-edge
+        version: 0.14.3
+      - name: rancher
+        releaseName: rancher-prod
+        repositoryName: rancher
+        version: 2.8.4
+`)
+
+	migrated, steps, err := MigrateDefinition(data, "")
+	require.NoError(t, err)
+
+	expectedSteps := []MigrationStep{
+		{
+			From: "1.0",
+			To:   "1.1",
+			Changes: []string{
+				"Removed 'fips=1' from 'operatingSystem.kernelArgs'",
+				"Removed 'pattern:fips' from 'operatingSystem.packages.packageList'",
+				"Set 'operatingSystem.enableFIPS' to 'true' which installs the FIPS packages and sets the kernel argument",
+			},
+		},
+		{
+			From:    "1.1",
+			To:      "1.2",
+			Changes: []string{"Set 'kubernetes.helm.charts[0].releaseName' to 'metallb'"},
+		},
+		{
+			From:    "1.2",
+			To:      "1.3",
+			Changes: []string{"Set 'operatingSystem.packages.additionalRepos[0].priority' to '99'"},
+		},
+		{
+			From: "1.3",
+			To:   "1.4",
+		},
+	}
+	assert.Equal(t, expectedSteps, steps)
+
+	assert.Contains(t, string(migrated), "# Edge cluster definition")
+	assert.Contains(t, string(migrated), "- console=ttyS0 # serial console")
+	assert.Contains(t, string(migrated), "# Load balancer")
+
+	definition, err := ParseDefinition(migrated, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", definition.APIVersion)
+	assert.True(t, definition.OperatingSystem.EnableFIPS)
+	assert.Equal(t, []string{"console=ttyS0"}, definition.OperatingSystem.KernelArgs)
+	assert.Equal(t, []string{"vim"}, definition.OperatingSystem.Packages.PKGList)
+	assert.Equal(t, 99, definition.OperatingSystem.Packages.AdditionalRepos[0].Priority)
+	assert.Equal(t, 10, definition.OperatingSystem.Packages.AdditionalRepos[1].Priority)
+	assert.Equal(t, "metallb", definition.Kubernetes.Helm.Charts[0].ReleaseName)
+	assert.Equal(t, "rancher-prod", definition.Kubernetes.Helm.Charts[1].ReleaseName)
+}
+
+func TestMigrateDefinition_IntermediateVersion(t *testing.T) {
+	data := []byte(`apiVersion: 1.2
+operatingSystem:
+  kernelArgs:
+    - fips=1
+`)
+
+	migrated, steps, err := MigrateDefinition(data, "")
+	require.NoError(t, err)
+
+	require.Len(t, steps, 2)
+	assert.Equal(t, "1.2", steps[0].From)
+	assert.Empty(t, steps[0].Changes)
+	assert.Equal(t, "1.4", steps[1].To)
+
+	definition, err := ParseDefinition(migrated, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", definition.APIVersion)
+	assert.False(t, definition.OperatingSystem.EnableFIPS)
+	assert.Equal(t, []string{"fips=1"}, definition.OperatingSystem.KernelArgs)
+}
+
+func TestMigrateDefinition_LatestVersion(t *testing.T) {
+	data := []byte(`apiVersion: 1.4
+image:
+  imageType: iso
+`)
+
+	migrated, steps, err := MigrateDefinition(data, "")
+	require.NoError(t, err)
+
+	assert.Empty(t, steps)
+	assert.Equal(t, data, migrated)
+}
+
+func TestMigrateDefinition_Invalid(t *testing.T) {
+	_, _, err := MigrateDefinition([]byte("apiVersion: 0.9\n"), "")
+	require.ErrorIs(t, err, ErrorInvalidSchemaVersion)
+}
+
+func TestMigrateDefinition_InheritedVersion(t *testing.T) {
+	configDir := t.TempDir()
+
+	base := []byte(`apiVersion: 1.2
+image:
+  imageType: raw
+  arch: x86_64
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base.yaml"), base, 0o600))
+
+	data := []byte(`extends: base.yaml
+image:
+  outputImageName: eib-image.raw
+`)
+
+	migrated, steps, err := MigrateDefinition(data, configDir)
+	require.NoError(t, err)
+
+	require.Len(t, steps, 2)
+	assert.Equal(t, "1.2", steps[0].From)
+	assert.Equal(t, "1.4", steps[1].To)
+
+	assert.Equal(t, `apiVersion: 1.4
+extends: base.yaml
+image:
+  outputImageName: eib-image.raw
+`, string(migrated))
+
+	definition, err := ParseDefinition(migrated, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.4", definition.APIVersion)
+	assert.Equal(t, TypeRAW, definition.Image.ImageType)
+}
+
+func TestMigrateDefinition_UnresolvedSecrets(t *testing.T) {
+	data := []byte(`apiVersion: 1.3
+embeddedArtifactRegistry:
+  registries:
+    - uri: registry.example.com
+      authentication:
+        username: user
+        password: ${env:EIB_TEST_MISSING_VARIABLE}
+`)
+
+	migrated, steps, err := MigrateDefinition(data, "")
+	require.NoError(t, err)
+
+	require.Len(t, steps, 1)
+	assert.Contains(t, string(migrated), "password: ${env:EIB_TEST_MISSING_VARIABLE}")
+}
@@ -0,0 +1,225 @@
+package image
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the location of a field in the source of the image definition.
+type Position struct {
+	// File is the base definition the field originates from, relative to the image configuration directory.
+	// It is empty for fields specified in the definition file itself.
+	File   string
+	Line   int
+	Column int
+}
+
+// IsKnown returns whether the position points to an actual location in the definition source.
+func (p Position) IsKnown() bool {
+	return p.Line > 0
+}
+
+// UnknownField is a key in the definition source which does not match any of the definition fields.
+type UnknownField struct {
+	Path       string
+	Position   Position
+	Suggestion string
+}
+
+// UnknownFieldsError is returned when parsing a definition which contains unknown fields.
+type UnknownFieldsError struct {
+	Fields []UnknownField
+}
+
+func (e *UnknownFieldsError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+
+	for _, f := range e.Fields {
+		message := fmt.Sprintf("line %d: unknown field '%s'", f.Position.Line, f.Path)
+		if f.Position.File != "" {
+			message = fmt.Sprintf("%s: %s", f.Position.File, message)
+		}
+
+		if f.Suggestion != "" {
+			message += fmt.Sprintf(", did you mean '%s'?", f.Suggestion)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Position returns the location of the field with the given path in the definition source,
+// e.g. 'kubernetes.helm.charts[2].valuesFile'. Fields which are not present in the source
+// are reported at the position of their closest present parent.
+func (d *Definition) Position(path string) Position {
+	for path != "" {
+		if p, ok := d.positions[path]; ok {
+			return p
+		}
+
+		path = parentPath(path)
+	}
+
+	return Position{}
+}
+
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		return path[:strings.LastIndex(path, "[")]
+	}
+
+	if i := strings.LastIndex(path, "."); i != -1 {
+		return path[:i]
+	}
+
+	return ""
+}
+
+// registerSources records the file each of the nodes of a base definition originates from.
+func registerSources(node *yaml.Node, file string, sources map[*yaml.Node]string) {
+	sources[node] = file
+
+	for _, n := range node.Content {
+		registerSources(n, file, sources)
+	}
+}
+
+// indexPositions maps the paths of all fields in the definition to their location in the source.
+// Fields are located by their keys and list entries by their first key or value respectively.
+func indexPositions(node *yaml.Node, path string, sources map[*yaml.Node]string, positions map[string]Position) {
+	position := func(n *yaml.Node) Position {
+		return Position{File: sources[n], Line: n.Line, Column: n.Column}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+
+			positions[fieldPath] = position(key)
+			indexPositions(value, fieldPath, sources, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+			// Merged list entries are new nodes, their first key is always taken from a source file
+			if item.Kind == yaml.MappingNode && len(item.Content) > 0 {
+				positions[itemPath] = position(item.Content[0])
+			} else {
+				positions[itemPath] = position(item)
+			}
+
+			indexPositions(item, itemPath, sources, positions)
+		}
+	}
+}
+
+// findUnknownFields walks the definition source against the definition type and reports all keys which do not match a field.
+func findUnknownFields(node *yaml.Node, t reflect.Type, path string, sources map[*yaml.Node]string) []UnknownField {
+	var unknown []UnknownField
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		fields := yamlFields(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				unknown = append(unknown, UnknownField{
+					Path:       fieldPath,
+					Position:   Position{File: sources[key], Line: key.Line, Column: key.Column},
+					Suggestion: suggestField(key.Value, fields),
+				})
+				continue
+			}
+
+			unknown = append(unknown, findUnknownFields(value, fieldType, fieldPath, sources)...)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		for i, item := range node.Content {
+			unknown = append(unknown, findUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), sources)...)
+		}
+	}
+
+	return unknown
+}
+
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+// suggestField returns the known field closest to the given unknown key, if any is similar enough.
+func suggestField(key string, fields map[string]reflect.Type) string {
+	var suggestion string
+	best := max(2, len(key)/3) + 1
+
+	for name := range fields {
+		distance := levenshtein(strings.ToLower(key), strings.ToLower(name))
+		if distance < best || (distance == best && name < suggestion) {
+			best = distance
+			suggestion = name
+		}
+	}
+
+	return suggestion
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
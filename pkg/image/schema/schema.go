@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	draft = "https://json-schema.org/draft/2020-12/schema"
+
+	typeObject  = "object"
+	typeArray   = "array"
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+)
+
+// Schema is a subset of the JSON Schema specification which is sufficient to describe the image definition.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+}
+
+// Allowed values of fields, keyed by their path in the definition.
+var enums = map[string][]any{
+	"image.arch":                 {string(image.ArchTypeX86), string(image.ArchTypeARM)},
+	"image.containerDisk.format": {image.ContainerDiskFormatOCI, image.ContainerDiskFormatOCIArchive},
+	"image.compression.type":     {image.CompressionXZ, image.CompressionZstd, image.CompressionGzip},
+	"operatingSystem.rawConfiguration.partitions.filesystem": {image.FilesystemExt4, image.FilesystemXFS, image.FilesystemBtrfs},
+	"kubernetes.nodes.type":                                  {image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent},
+}
+
+// Value formats of fields, keyed by their path in the definition.
+var patterns = map[string]string{
+	"operatingSystem.rawConfiguration.diskSize":         image.DiskSizePattern,
+	"operatingSystem.rawConfiguration.minimumDiskSize":  image.DiskSizePattern,
+	"operatingSystem.rawConfiguration.rootSize":         image.DiskSizePattern,
+	"operatingSystem.rawConfiguration.partitions.size":  image.DiskSizePattern,
+	"operatingSystem.rawConfiguration.partitions.label": image.PartitionLabelPattern,
+}
+
+// Value ranges of numeric fields, keyed by their path in the definition.
+var ranges = map[string][2]int{
+	"image.compression.level":                           {0, 22},
+	"operatingSystem.rawConfiguration.diskSizeHeadroom": {0, 100},
+	"operatingSystem.packages.additionalRepos.priority": {0, 99},
+}
+
+// Fields which must be set in each entry of a list, keyed by the path of the list in the definition.
+var requiredFields = map[string][]string{
+	"operatingSystem.groups":                      {"name"},
+	"operatingSystem.users":                       {"username"},
+	"operatingSystem.packages.additionalRepos":    {"url"},
+	"operatingSystem.rawConfiguration.partitions": {"label", "filesystem", "mountPoint"},
+	"embeddedArtifactRegistry.images":             {"name"},
+	"embeddedArtifactRegistry.registries":         {"uri", "authentication"},
+	"kubernetes.nodes":                            {"hostname"},
+	"kubernetes.helm.charts":                      {"name", "repositoryName", "version"},
+	"kubernetes.helm.repositories":                {"name", "url"},
+}
+
+// Generate builds the JSON schema of the image definition for the given API version.
+func Generate(apiVersion string) (*Schema, error) {
+	if !version.IsSchemaVersionSupported(apiVersion) {
+		return nil, fmt.Errorf("unsupported schema version '%s'", apiVersion)
+	}
+
+	unavailable := validation.UnavailableFields(apiVersion)
+
+	schema := generate(reflect.TypeOf(image.Definition{}), "", unavailable)
+	schema.Schema = draft
+	schema.ID = fmt.Sprintf("https://github.com/suse-edge/edge-image-builder/schema/%s/definition.json", apiVersion)
+	schema.Title = fmt.Sprintf("Edge Image Builder image definition %s", apiVersion)
+
+	// Numeric looking versions are parsed as floats by most YAML tooling
+	apiVersionSchema := schema.Properties["apiVersion"]
+	apiVersionSchema.Type = []string{typeString, typeNumber}
+	apiVersionSchema.Enum = []any{apiVersion}
+	if number, ok := parseNumber(apiVersion); ok {
+		apiVersionSchema.Enum = append(apiVersionSchema.Enum, number)
+	}
+
+	schema.Required = []string{"apiVersion"}
+
+	// EIB accepts image types in any case, the schema only lists them in lowercase as JSON Schema
+	// has no means of matching enums case-insensitively
+	imageTypeSchema := schema.Properties["image"].Properties["imageType"]
+	imageTypeSchema.Description = "The type of image to build, in lowercase."
+	for _, imageType := range validation.ImageTypes(apiVersion) {
+		imageTypeSchema.Enum = append(imageTypeSchema.Enum, imageType)
+	}
+
+	if validation.AutoDiskSizeAvailable(apiVersion) {
+		diskSizeSchema := schema.Properties["operatingSystem"].Properties["rawConfiguration"].Properties["diskSize"]
+		diskSizeSchema.Pattern = "^" + string(image.DiskSizeAuto) + "$|" + image.DiskSizePattern
+	}
+
+	return schema, nil
+}
+
+func generate(t reflect.Type, path string, unavailable []string) *Schema {
+	switch t.Kind() {
+	case reflect.Struct:
+		return generateObject(t, path, unavailable)
+	case reflect.Slice:
+		items := generate(t.Elem(), path, unavailable)
+		items.Required = requiredFields[path]
+
+		return &Schema{
+			Type:  typeArray,
+			Items: items,
+		}
+	case reflect.Ptr:
+		// Optional values which differ from their zero value, the schema describes the value itself
+		return generate(t.Elem(), path, unavailable)
+	case reflect.Bool:
+		return &Schema{Type: typeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := &Schema{Type: typeInteger}
+		if r, ok := ranges[path]; ok {
+			s.Minimum, s.Maximum = &r[0], &r[1]
+		}
+
+		return s
+	default:
+		return &Schema{
+			Type:    typeString,
+			Enum:    enums[path],
+			Pattern: patterns[path],
+		}
+	}
+}
+
+func generateObject(t reflect.Type, path string, unavailable []string) *Schema {
+	additionalProperties := false
+
+	s := &Schema{
+		Type:                 typeObject,
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: &additionalProperties,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		if slices.Contains(unavailable, fieldPath) {
+			continue
+		}
+
+		s.Properties[name] = generate(field.Type, fieldPath, unavailable)
+	}
+
+	return s
+}
+
+func parseNumber(v string) (float64, bool) {
+	var f float64
+	if _, err := fmt.Sscanf(v, "%g", &f); err != nil {
+		return 0, false
+	}
+
+	return f, true
+}
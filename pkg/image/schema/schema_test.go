@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+func TestGenerate(t *testing.T) {
+	s, err := Generate("1.4")
+	require.NoError(t, err)
+
+	assert.Equal(t, draft, s.Schema)
+	assert.Equal(t, typeObject, s.Type)
+	assert.Equal(t, []string{"apiVersion"}, s.Required)
+	require.NotNil(t, s.AdditionalProperties)
+	assert.False(t, *s.AdditionalProperties)
+
+	assert.Equal(t, []any{"1.4", 1.4}, s.Properties["apiVersion"].Enum)
+	assert.Contains(t, s.Properties, "extends")
+
+	img := s.Properties["image"]
+	assert.Equal(t, []any{image.TypeISO, image.TypeRAW, image.TypeQCOW2, image.TypePXE}, img.Properties["imageType"].Enum)
+	assert.Equal(t, []any{"x86_64", "aarch64"}, img.Properties["arch"].Enum)
+
+	os := s.Properties["operatingSystem"]
+	assert.Equal(t, typeBoolean, os.Properties["enableFIPS"].Type)
+	assert.Equal(t, "^auto$|"+image.DiskSizePattern, os.Properties["rawConfiguration"].Properties["diskSize"].Pattern)
+	assert.Equal(t, image.DiskSizePattern, os.Properties["rawConfiguration"].Properties["minimumDiskSize"].Pattern)
+
+	users := os.Properties["users"]
+	assert.Equal(t, typeArray, users.Type)
+	assert.Equal(t, []string{"username"}, users.Items.Required)
+	assert.Equal(t, typeArray, users.Items.Properties["sshKeys"].Type)
+	assert.Equal(t, typeString, users.Items.Properties["sshKeys"].Items.Type)
+
+	priority := os.Properties["packages"].Properties["additionalRepos"].Items.Properties["priority"]
+	assert.Equal(t, typeInteger, priority.Type)
+	assert.Equal(t, 0, *priority.Minimum)
+	assert.Equal(t, 99, *priority.Maximum)
+
+	nodes := s.Properties["kubernetes"].Properties["nodes"]
+	assert.Equal(t, []any{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}, nodes.Items.Properties["type"].Enum)
+}
+
+func TestGenerate_VersionFields(t *testing.T) {
+	s, err := Generate("1.0")
+	require.NoError(t, err)
+
+	assert.NotContains(t, s.Properties, "extends")
+	assert.NotContains(t, s.Properties["operatingSystem"].Properties, "enableFIPS")
+	assert.NotContains(t, s.Properties["embeddedArtifactRegistry"].Properties, "registries")
+	assert.NotContains(t, s.Properties["operatingSystem"].Properties["packages"].Properties["additionalRepos"].Items.Properties, "priority")
+	assert.Contains(t, s.Properties["operatingSystem"].Properties["packages"].Properties["additionalRepos"].Items.Properties, "url")
+
+	s, err = Generate("1.3")
+	require.NoError(t, err)
+
+	assert.Equal(t, []any{image.TypeISO, image.TypeRAW}, s.Properties["image"].Properties["imageType"].Enum)
+	assert.Equal(t, image.DiskSizePattern, s.Properties["operatingSystem"].Properties["rawConfiguration"].Properties["diskSize"].Pattern)
+	assert.NotContains(t, s.Properties, "extends")
+	assert.Contains(t, s.Properties["operatingSystem"].Properties, "enableFIPS")
+	assert.Contains(t, s.Properties["embeddedArtifactRegistry"].Properties, "registries")
+}
+
+func TestGenerate_AllSupportedVersions(t *testing.T) {
+	for _, v := range version.SupportedSchemaVersions {
+		s, err := Generate(v)
+		require.NoError(t, err, v)
+
+		data, err := json.Marshal(s)
+		require.NoError(t, err, v)
+		assert.True(t, json.Valid(data), v)
+	}
+}
+
+func TestGenerate_UnsupportedVersion(t *testing.T) {
+	_, err := Generate("0.9")
+	require.EqualError(t, err, "unsupported schema version '0.9'")
+}
@@ -0,0 +1,272 @@
+package image
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	SecretsDir = "secrets"
+
+	secretProviderEnv  = "env"
+	secretProviderFile = "file"
+	secretProviderSops = "sops"
+
+	sopsExec = "sops"
+)
+
+// Secret references take the form of '${<provider>:<reference>}' where the provider is one of:
+//   - env  - the name of an environment variable, e.g. '${env:REGISTRY_PASSWORD}'
+//   - file - a file under the secrets directory, e.g. '${file:registry-password}'
+//   - sops - a key within a sops encrypted file under the secrets directory, e.g. '${sops:credentials.yaml#registry.password}'
+var secretReferenceRegexp = regexp.MustCompile(`^\$\{(env|file|sops):([^}]+)}$`)
+
+var ErrorUnresolvedSecret = errors.New("unresolved secret reference")
+
+// IsSecretReference returns whether the given value is a reference to a secret rather than a plaintext value.
+func IsSecretReference(value string) bool {
+	return secretReferenceRegexp.MatchString(value)
+}
+
+type secretResolver struct {
+	secretsDir string
+	// decryptedFiles caches the contents of already decrypted sops files.
+	decryptedFiles map[string]map[string]any
+	decrypt        func(path string) ([]byte, error)
+}
+
+func newSecretResolver(configDir string) *secretResolver {
+	return &secretResolver{
+		secretsDir:     filepath.Join(configDir, SecretsDir),
+		decryptedFiles: map[string]map[string]any{},
+		decrypt:        sopsDecrypt,
+	}
+}
+
+type secretField struct {
+	path  string
+	value *string
+	// identity marks fields such as usernames which may be resolved from secrets but are not sensitive on their own.
+	identity bool
+}
+
+// Secrets returns the values of all sensitive credential fields in the definition.
+func (d *Definition) Secrets() []string {
+	var values []string
+
+	for _, field := range secretFields(d) {
+		if !field.identity && *field.value != "" {
+			values = append(values, *field.value)
+		}
+	}
+
+	return values
+}
+
+// resolveSecrets replaces all secret references in the fields holding credentials with their actual values.
+func resolveSecrets(definition *Definition, configDir string) error {
+	resolver := newSecretResolver(configDir)
+
+	for _, field := range secretFields(definition) {
+		if err := resolver.resolve(field.value); err != nil {
+			return fmt.Errorf("resolving secret for '%s': %w", field.path, err)
+		}
+	}
+
+	return nil
+}
+
+// secretFields returns all fields in the definition which hold credentials.
+func secretFields(definition *Definition) []secretField {
+	fields := []secretField{
+		{path: "operatingSystem.packages.sccRegistrationCode", value: &definition.OperatingSystem.Packages.RegCode},
+		{path: "operatingSystem.suma.activationKey", value: &definition.OperatingSystem.Suma.ActivationKey},
+		{path: "operatingSystem.rawConfiguration.luksKey", value: &definition.OperatingSystem.RawConfiguration.LUKSKey},
+	}
+
+	for i := range definition.OperatingSystem.RawConfiguration.Partitions {
+		partition := &definition.OperatingSystem.RawConfiguration.Partitions[i]
+		path := fmt.Sprintf("operatingSystem.rawConfiguration.partitions[%d].luksKey", i)
+
+		fields = append(fields, secretField{path: path, value: &partition.LUKSKey})
+	}
+
+	for i := range definition.EmbeddedArtifactRegistry.Registries {
+		auth := &definition.EmbeddedArtifactRegistry.Registries[i].Authentication
+		path := fmt.Sprintf("embeddedArtifactRegistry.registries[%d].authentication", i)
+
+		fields = append(fields,
+			secretField{path: path + ".username", value: &auth.Username, identity: true},
+			secretField{path: path + ".password", value: &auth.Password})
+	}
+
+	for i := range definition.Kubernetes.Helm.Repositories {
+		auth := &definition.Kubernetes.Helm.Repositories[i].Authentication
+		path := fmt.Sprintf("kubernetes.helm.repositories[%d].authentication", i)
+
+		fields = append(fields,
+			secretField{path: path + ".username", value: &auth.Username, identity: true},
+			secretField{path: path + ".password", value: &auth.Password})
+	}
+
+	return fields
+}
+
+func (r *secretResolver) resolve(value *string) error {
+	matches := secretReferenceRegexp.FindStringSubmatch(*value)
+	if matches == nil {
+		return nil
+	}
+
+	provider, reference := matches[1], matches[2]
+
+	var (
+		secret string
+		err    error
+	)
+
+	switch provider {
+	case secretProviderEnv:
+		secret, err = resolveEnvSecret(reference)
+	case secretProviderFile:
+		secret, err = r.resolveFileSecret(reference)
+	case secretProviderSops:
+		secret, err = r.resolveSopsSecret(reference)
+	default:
+		err = fmt.Errorf("unknown secret provider '%s'", provider)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*value = secret
+	return nil
+}
+
+func resolveEnvSecret(name string) (string, error) {
+	secret, ok := os.LookupEnv(name)
+	if !ok || secret == "" {
+		return "", fmt.Errorf("%w: environment variable '%s' is not set", ErrorUnresolvedSecret, name)
+	}
+
+	return secret, nil
+}
+
+func (r *secretResolver) resolveFileSecret(name string) (string, error) {
+	path, err := r.secretPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading secret file '%s': %w", ErrorUnresolvedSecret, name, err)
+	}
+
+	secret := strings.TrimRight(string(data), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("%w: secret file '%s' is empty", ErrorUnresolvedSecret, name)
+	}
+
+	return secret, nil
+}
+
+func (r *secretResolver) resolveSopsSecret(reference string) (string, error) {
+	name, key, found := strings.Cut(reference, "#")
+	if !found || key == "" {
+		return "", fmt.Errorf("%w: sops reference '%s' must be in the form '<file>#<key>'", ErrorUnresolvedSecret, reference)
+	}
+
+	contents, ok := r.decryptedFiles[name]
+	if !ok {
+		path, err := r.secretPath(name)
+		if err != nil {
+			return "", err
+		}
+
+		data, err := r.decrypt(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: decrypting secret file '%s': %w", ErrorUnresolvedSecret, name, err)
+		}
+
+		// Numbers are kept in their literal form, e.g. long numeric tokens are not turned into an exponent
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		if err = decoder.Decode(&contents); err != nil {
+			return "", fmt.Errorf("%w: decoding decrypted secret file '%s': %w", ErrorUnresolvedSecret, name, err)
+		}
+
+		r.decryptedFiles[name] = contents
+	}
+
+	secret, err := lookupSecretKey(contents, key)
+	if err != nil {
+		return "", fmt.Errorf("%w: looking up key '%s' in secret file '%s': %w", ErrorUnresolvedSecret, key, name, err)
+	}
+
+	return secret, nil
+}
+
+func (r *secretResolver) secretPath(name string) (string, error) {
+	path := filepath.Join(r.secretsDir, name)
+
+	rel, err := filepath.Rel(r.secretsDir, path)
+	if err != nil || filepath.IsAbs(name) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: secret file '%s' must be located within the '%s' directory", ErrorUnresolvedSecret, name, SecretsDir)
+	}
+
+	return path, nil
+}
+
+// lookupSecretKey traverses the decrypted contents following a dot separated key.
+func lookupSecretKey(contents map[string]any, key string) (string, error) {
+	var current any = contents
+
+	for _, k := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("'%s' is not a map", k)
+		}
+
+		if current, ok = m[k]; !ok {
+			return "", fmt.Errorf("key '%s' not found", k)
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("value is not a scalar")
+	}
+}
+
+// sopsDecrypt decrypts the given file through the sops binary. The decryption keys are
+// discovered by sops itself, e.g. through the SOPS_AGE_KEY_FILE environment variable.
+func sopsDecrypt(path string) ([]byte, error) {
+	cmd := exec.Command(sopsExec, "--decrypt", "--output-type", "json", path)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running sops: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
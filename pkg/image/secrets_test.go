@@ -0,0 +1,147 @@
+package image
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefinition_SecretReferences(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "eib-secrets-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(configDir)
+	}()
+
+	secretsDir := filepath.Join(configDir, SecretsDir)
+	require.NoError(t, os.MkdirAll(secretsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(secretsDir, "luks-key"), []byte("luks-secret\n"), 0o600))
+
+	t.Setenv("EIB_TEST_REGISTRY_PASSWORD", "registry-secret")
+
+	data := []byte(`
+apiVersion: 1.4
+operatingSystem:
+  rawConfiguration:
+    luksKey: ${file:luks-key}
+  packages:
+    sccRegistrationCode: plaintext-code
+embeddedArtifactRegistry:
+  registries:
+    - uri: docker.io
+      authentication:
+        username: user
+        password: ${env:EIB_TEST_REGISTRY_PASSWORD}
+`)
+
+	definition, err := ParseDefinition(data, configDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "luks-secret", definition.OperatingSystem.RawConfiguration.LUKSKey)
+	assert.Equal(t, "plaintext-code", definition.OperatingSystem.Packages.RegCode)
+	assert.Equal(t, "user", definition.EmbeddedArtifactRegistry.Registries[0].Authentication.Username)
+	assert.Equal(t, "registry-secret", definition.EmbeddedArtifactRegistry.Registries[0].Authentication.Password)
+
+	assert.ElementsMatch(t, []string{"luks-secret", "plaintext-code", "registry-secret"}, definition.Secrets())
+}
+
+func TestParseDefinition_UnresolvedSecret(t *testing.T) {
+	data := []byte(`
+apiVersion: 1.4
+operatingSystem:
+  suma:
+    host: suma.edge.suse.com
+    activationKey: ${env:EIB_TEST_MISSING_VARIABLE}
+`)
+
+	_, err := ParseDefinition(data, "")
+	require.ErrorIs(t, err, ErrorUnresolvedSecret)
+	assert.ErrorContains(t, err, "resolving secret for 'operatingSystem.suma.activationKey'")
+	assert.ErrorContains(t, err, "environment variable 'EIB_TEST_MISSING_VARIABLE' is not set")
+}
+
+func TestSecretResolver_Resolve(t *testing.T) {
+	decryptCalls := 0
+
+	resolver := newSecretResolver("/eib")
+	resolver.decrypt = func(path string) ([]byte, error) {
+		decryptCalls++
+
+		switch path {
+		case "/eib/secrets/credentials.yaml":
+			return []byte(`{"registry": {"password": "sops-secret", "port": 5000, "token": 12345678, "insecure": false}}`), nil
+		default:
+			return nil, fmt.Errorf("no such file")
+		}
+	}
+
+	tests := map[string]struct {
+		value         string
+		expectedValue string
+		expectedError string
+	}{
+		`plaintext value`: {
+			value:         "plaintext",
+			expectedValue: "plaintext",
+		},
+		`malformed reference`: {
+			value:         "${vault:secret}",
+			expectedValue: "${vault:secret}",
+		},
+		`sops value`: {
+			value:         "${sops:credentials.yaml#registry.password}",
+			expectedValue: "sops-secret",
+		},
+		`sops numeric value`: {
+			value:         "${sops:credentials.yaml#registry.port}",
+			expectedValue: "5000",
+		},
+		`sops large numeric value`: {
+			value:         "${sops:credentials.yaml#registry.token}",
+			expectedValue: "12345678",
+		},
+		`sops boolean value`: {
+			value:         "${sops:credentials.yaml#registry.insecure}",
+			expectedValue: "false",
+		},
+		`sops missing key`: {
+			value:         "${sops:credentials.yaml#registry.username}",
+			expectedError: "looking up key 'registry.username' in secret file 'credentials.yaml': key 'username' not found",
+		},
+		`sops without key`: {
+			value:         "${sops:credentials.yaml}",
+			expectedError: "sops reference 'credentials.yaml' must be in the form '<file>#<key>'",
+		},
+		`sops decryption failure`: {
+			value:         "${sops:missing.yaml#password}",
+			expectedError: "decrypting secret file 'missing.yaml': no such file",
+		},
+		`file outside of secrets dir`: {
+			value:         "${file:../definition.yaml}",
+			expectedError: "secret file '../definition.yaml' must be located within the 'secrets' directory",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value := test.value
+
+			err := resolver.resolve(&value)
+			if test.expectedError != "" {
+				require.ErrorIs(t, err, ErrorUnresolvedSecret)
+				assert.ErrorContains(t, err, test.expectedError)
+				assert.Equal(t, test.value, value)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedValue, value)
+		})
+	}
+
+	assert.Equal(t, 2, decryptCalls, "decrypted files should be cached")
+}
@@ -4,54 +4,93 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"slices"
 	"strings"
 
-	"github.com/suse-edge/edge-image-builder/pkg/log"
-
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
 )
 
 const (
 	imageComponent = "Image"
 )
 
+var containerDiskTagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+var validImageTypes = []string{image.TypeISO, image.TypeRAW, image.TypeQCOW2, image.TypePXE}
+
+// imageTypeAPIVersions holds the API versions image types were introduced in after the initial release.
+var imageTypeAPIVersions = map[string]string{
+	image.TypeQCOW2: "1.4",
+	image.TypePXE:   "1.4",
+}
+
+// hostArch is the architecture of the host running the build
+var hostArch = runtime.GOARCH
+
+// ImageTypes returns the image types which are available in the given API version.
+func ImageTypes(apiVersion string) []string {
+	var types []string
+
+	for _, imageType := range validImageTypes {
+		if v, ok := imageTypeAPIVersions[imageType]; ok && strings.Compare(apiVersion, v) < 0 {
+			continue
+		}
+
+		types = append(types, imageType)
+	}
+
+	return types
+}
+
 func validateImage(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
-	validImageTypes := []string{image.TypeISO, image.TypeRAW}
-
 	var failures []FailedValidation
 
 	// Omit checking everything if it's a config drive build
 	if ctx.IsConfigDrive {
 		failures = append(failures, validateImageConfigDrive(def)...)
+		failures = append(failures, validateCompression(def, true)...)
 		return failures
 	}
 
 	failures = append(failures, validateArch(def)...)
+	failures = append(failures, validateContainerDisk(def)...)
+	failures = append(failures, validateCompression(def, false)...)
 
 	if def.Image.OutputImageName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'outputImageName' field is required in the 'image' section.",
+			Field:       "image.outputImageName",
 		})
 	}
 
 	if def.Image.ImageType == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'imageType' field is required in the 'image' section.",
+			Field:       "image.imageType",
 		})
 	} else if !slices.Contains(validImageTypes, def.Image.ImageType) {
 		msg := fmt.Sprintf("The 'imageType' field must be one of: %s", strings.Join(validImageTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "image.imageType",
+		})
+	} else if apiVersion, ok := imageTypeAPIVersions[def.Image.ImageType]; ok && strings.Compare(def.APIVersion, apiVersion) < 0 {
+		msg := fmt.Sprintf("The '%s' image type is only available in API version >= %s", def.Image.ImageType, apiVersion)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.imageType",
 		})
 	}
 
 	if def.Image.BaseImage == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'baseImage' field is required in the 'image' section.",
+			Field:       "image.baseImage",
 		})
 	} else {
 		baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
@@ -61,12 +100,14 @@ func validateImage(ctx *image.Context) []FailedValidation {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be found.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Field:       "image.baseImage",
 				})
 			} else {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be read. See the logs for more information.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
 					Error:       err,
+					Field:       "image.baseImage",
 				})
 			}
 		}
@@ -82,6 +123,7 @@ func validateArch(def *image.Definition) []FailedValidation {
 	if def.Image.Arch == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'arch' field is required in the 'image' section.",
+			Field:       "image.arch",
 		})
 
 		return failures
@@ -89,14 +131,92 @@ func validateArch(def *image.Definition) []FailedValidation {
 		msg := fmt.Sprintf("The 'arch' field must be one of: %s", strings.Join(validArchTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "image.arch",
 		})
 
 		return failures
 	}
 
-	if runtime.GOARCH != def.Image.Arch.Short() {
-		log.Auditf("Image build may fail as host architecture does not match the defined architecture of the "+
-			"output image.\nDetected: %s, Defined: %s", runtime.GOARCH, def.Image.Arch)
+	if hostArch != def.Image.Arch.Short() {
+		msg := fmt.Sprintf("Image build may fail as host architecture does not match the defined architecture of the "+
+			"output image. Detected: %s, Defined: %s", hostArch, def.Image.Arch)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Field:       "image.arch",
+		})
+	}
+
+	return failures
+}
+
+func validateContainerDisk(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	containerDisk := def.Image.ContainerDisk
+	if containerDisk == (image.ContainerDisk{}) {
+		return nil
+	}
+
+	validFormats := []string{image.ContainerDiskFormatOCI, image.ContainerDiskFormatOCIArchive}
+	if !slices.Contains(validFormats, containerDisk.Format) {
+		msg := fmt.Sprintf("The 'format' field in the 'containerDisk' section must be one of: %s", strings.Join(validFormats, ", "))
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.containerDisk.format",
+		})
+	}
+
+	if containerDisk.Tag != "" && !containerDiskTagRegexp.MatchString(containerDisk.Tag) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'tag' field in the 'containerDisk' section must be a valid container image tag.",
+			Field:       "image.containerDisk.tag",
+		})
+	}
+
+	if def.Image.ImageType != image.TypeRAW && def.Image.ImageType != image.TypeQCOW2 {
+		msg := fmt.Sprintf("The 'containerDisk' section can only be used when 'imageType' is '%s' or '%s'.", image.TypeRAW, image.TypeQCOW2)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.containerDisk",
+		})
+	}
+
+	return failures
+}
+
+func validateCompression(def *image.Definition, isConfigDrive bool) []FailedValidation {
+	var failures []FailedValidation
+
+	compression := def.Image.Compression
+	if compression == (image.Compression{}) {
+		return nil
+	}
+
+	validTypes := []string{image.CompressionGzip, image.CompressionXZ, image.CompressionZstd}
+	if !slices.Contains(validTypes, compression.Type) {
+		msg := fmt.Sprintf("The 'type' field in the 'compression' section must be one of: %s", strings.Join(validTypes, ", "))
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.compression.type",
+		})
+	} else if minimum, maximum := output.LevelRange(compression.Type); compression.Level != 0 &&
+		(compression.Level < minimum || compression.Level > maximum) {
+		msg := fmt.Sprintf("The 'level' field in the 'compression' section must be between %d and %d for '%s'.",
+			minimum, maximum, compression.Type)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.compression.level",
+		})
+	}
+
+	// Config drives may be compressed regardless of the output type
+	if !isConfigDrive && def.Image.ImageType != image.TypeISO && def.Image.ImageType != image.TypeRAW {
+		msg := fmt.Sprintf("The 'compression' section can only be used when 'imageType' is '%s' or '%s'.", image.TypeISO, image.TypeRAW)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "image.compression",
+		})
 	}
 
 	return failures
@@ -109,6 +229,7 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.outputImageName' field is not valid for generating config drives. The name of the output " +
 				"file should be defined through the '--output' argument.",
+			Field: "image.outputImageName",
 		})
 	}
 
@@ -116,6 +237,7 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.imageType' field is not valid for generating config drives. The output type " +
 				"should be defined through the '--output-type' argument.",
+			Field: "image.imageType",
 		})
 	}
 
@@ -123,12 +245,21 @@ func validateImageConfigDrive(def *image.Definition) []FailedValidation {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.arch' field is not valid for generating config drives. The architecture of the generated " +
 				"config drive should be defined through the '--arch' argument.",
+			Field: "image.arch",
 		})
 	}
 
 	if def.Image.BaseImage != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'image.baseImage' field is not valid for generating config drives.",
+			Field:       "image.baseImage",
+		})
+	}
+
+	if def.Image.ContainerDisk != (image.ContainerDisk{}) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'image.containerDisk' section is not valid for generating config drives.",
+			Field:       "image.containerDisk",
 		})
 	}
 
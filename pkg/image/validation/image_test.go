@@ -10,6 +10,11 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+func TestImageTypes(t *testing.T) {
+	assert.Equal(t, []string{image.TypeISO, image.TypeRAW}, ImageTypes("1.3"))
+	assert.Equal(t, []string{image.TypeISO, image.TypeRAW, image.TypeQCOW2, image.TypePXE}, ImageTypes("1.4"))
+}
+
 func TestValidateImage(t *testing.T) {
 	imageConfigDir, err := os.MkdirTemp("", "eib-image-tests-")
 	require.NoError(t, err)
@@ -25,6 +30,12 @@ func TestValidateImage(t *testing.T) {
 	_, err = os.Create(testBaseImageFilename)
 	require.NoError(t, err)
 
+	defaultHostArch := hostArch
+	hostArch = "amd64"
+	defer func() {
+		hostArch = defaultHostArch
+	}()
+
 	tests := map[string]struct {
 		ImageDefinition        image.Definition
 		ExpectedFailedMessages []string
@@ -40,6 +51,134 @@ func TestValidateImage(t *testing.T) {
 				},
 			},
 		},
+		`valid qcow2 definition`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType:       image.TypeQCOW2,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.qcow2",
+				},
+			},
+		},
+		`valid pxe definition`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType:       image.TypePXE,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-pxe",
+				},
+			},
+		},
+		`valid containerDisk`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.raw",
+					ContainerDisk: image.ContainerDisk{
+						Format: image.ContainerDiskFormatOCIArchive,
+						Tag:    "v1.0.0",
+					},
+				},
+			},
+		},
+		`invalid containerDisk`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.iso",
+					ContainerDisk: image.ContainerDisk{
+						Format: "docker",
+						Tag:    "-invalid:tag",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'format' field in the 'containerDisk' section must be one of: oci, oci-archive",
+				"The 'tag' field in the 'containerDisk' section must be a valid container image tag.",
+				"The 'containerDisk' section can only be used when 'imageType' is 'raw' or 'qcow2'.",
+			},
+		},
+		`valid compression`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.raw",
+					Compression: image.Compression{
+						Type:  image.CompressionZstd,
+						Level: 19,
+					},
+				},
+			},
+		},
+		`invalid compression level`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType:       image.TypeQCOW2,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.qcow2",
+					Compression: image.Compression{
+						Type:  image.CompressionXZ,
+						Level: 19,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'level' field in the 'compression' section must be between 1 and 9 for 'xz'.",
+				"The 'compression' section can only be used when 'imageType' is 'iso' or 'raw'.",
+			},
+		},
+		`invalid compression type`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.iso",
+					Compression: image.Compression{
+						Level: 5,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'type' field in the 'compression' section must be one of: gzip, xz, zstd",
+			},
+		},
+		`compressed config drive`: {
+			IsConfigDrive: true,
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					Compression: image.Compression{
+						Type: image.CompressionGzip,
+					},
+				},
+			},
+		},
+		`qcow2 with unsupported API version`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.3",
+				Image: image.Image{
+					ImageType:       image.TypeQCOW2,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.qcow2",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'qcow2' image type is only available in API version >= 1.4",
+			},
+		},
 		`missing all fields`: {
 			ImageDefinition: image.Definition{
 				Image: image.Image{},
@@ -61,10 +200,23 @@ func TestValidateImage(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'imageType' field must be one of: iso, raw",
+				"The 'imageType' field must be one of: iso, raw, qcow2, pxe",
 				"The 'arch' field must be one of: aarch64, x86_64",
 			},
 		},
+		`host architecture mismatch`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeARM,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.raw",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Image build may fail as host architecture does not match the defined architecture of the output image. Detected: amd64, Defined: aarch64",
+			},
+		},
 		`base image not found`: {
 			ImageDefinition: image.Definition{
 				Image: image.Image{
@@ -86,6 +238,9 @@ func TestValidateImage(t *testing.T) {
 					Arch:            image.ArchTypeX86,
 					BaseImage:       "base-image.iso",
 					OutputImageName: "eib-created.iso",
+					ContainerDisk: image.ContainerDisk{
+						Format: image.ContainerDiskFormatOCI,
+					},
 				},
 			},
 			ExpectedFailedMessages: []string{
@@ -93,6 +248,7 @@ func TestValidateImage(t *testing.T) {
 				"The 'image.imageType' field is not valid for generating config drives. The output type should be defined through the '--output-type' argument.",
 				"The 'image.arch' field is not valid for generating config drives. The architecture of the generated config drive should be defined through the '--arch' argument.",
 				"The 'image.baseImage' field is not valid for generating config drives.",
+				"The 'image.containerDisk' section is not valid for generating config drives.",
 			},
 		},
 		`valid config drive definition`: {
@@ -37,6 +37,7 @@ func validateKubernetes(ctx *image.Context) []FailedValidation {
 
 	failures = append(failures, validateNetworkingConfig(&def.Kubernetes, combustion.KubernetesConfigPath(ctx))...)
 	failures = append(failures, validateNetwork(&def.Kubernetes)...)
+	failures = append(failures, validateK3sIngress(&def.Kubernetes)...)
 	failures = append(failures, validateNodes(&def.Kubernetes)...)
 	failures = append(failures, validateManifestURLs(&def.Kubernetes)...)
 	failures = append(failures, validateHelm(&def.Kubernetes, combustion.HelmValuesPath(ctx), combustion.HelmCertsPath(ctx))...)
@@ -61,10 +62,13 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	var nodeNames []string
 	var initialisers []*image.Node
 
-	for _, node := range k8s.Nodes {
+	for i, node := range k8s.Nodes {
+		field := fmt.Sprintf("kubernetes.nodes[%d]", i)
+
 		if node.Hostname == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'hostname' field is required for entries in the 'nodes' section.",
+				Field:       field + ".hostname",
 			})
 		}
 
@@ -73,6 +77,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", options)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field + ".type",
 			})
 		}
 
@@ -84,6 +89,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 				msg := fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Field:       field + ".type",
 				})
 			}
 		}
@@ -97,6 +103,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("The 'nodes' section contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
@@ -104,12 +111,22 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
 	if len(initialisers) > 1 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Only one node may be specified as the cluster initializer.",
+			Field:       "kubernetes.nodes",
+		})
+	}
+
+	if kubernetes.ServersCount(k8s.Nodes) == 2 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
+			Severity:    SeverityWarning,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
@@ -123,6 +140,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 		if len(k8s.Nodes) > 1 {
 			failures = append(failures, FailedValidation{
 				UserMessage: "At least one of the (`apiVIP`, `apiVIP6`) fields is required in the 'network' section for multi node clusters.",
+				Field:       "kubernetes.network",
 			})
 		}
 
@@ -135,6 +153,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Invalid address value %q for field 'apiVIP'.", k8s.Network.APIVIP4),
 				Error:       err,
+				Field:       "kubernetes.network.apiVIP",
 			})
 
 			return failures
@@ -143,6 +162,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 		if !ip4.Is4() {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Only IPv4 addresses are valid for field 'apiVIP'.",
+				Field:       "kubernetes.network.apiVIP",
 			})
 		}
 
@@ -150,6 +170,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("Non-unicast cluster API address (%s) for field 'apiVIP' is invalid.", k8s.Network.APIVIP4)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "kubernetes.network.apiVIP",
 			})
 		}
 	}
@@ -160,6 +181,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Invalid address value %q for field 'apiVIP6'.", k8s.Network.APIVIP6),
 				Error:       err,
+				Field:       "kubernetes.network.apiVIP6",
 			})
 
 			return failures
@@ -168,6 +190,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 		if !ip6.Is6() {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Only IPv6 addresses are valid for field 'apiVIP6'.",
+				Field:       "kubernetes.network.apiVIP6",
 			})
 		}
 
@@ -175,6 +198,7 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("Non-unicast cluster API address (%s) for field 'apiVIP6' is invalid.", k8s.Network.APIVIP6)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "kubernetes.network.apiVIP6",
 			})
 		}
 	}
@@ -182,6 +206,31 @@ func validateNetwork(k8s *image.Kubernetes) []FailedValidation {
 	return failures
 }
 
+func validateK3sIngress(k8s *image.Kubernetes) []FailedValidation {
+	if !strings.Contains(k8s.Version, image.KubernetesDistroK3S) {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if len(k8s.Nodes) > 1 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "An external IP address for the Ingress Controller (Traefik) must be manually configured in multi node clusters.",
+			Severity:    SeverityWarning,
+			Field:       "kubernetes.network",
+		})
+	} else if k8s.Network.APIVIP4 != "" || k8s.Network.APIVIP6 != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: "Virtual IP address(es) for the k3s cluster provided. " +
+				"An external IP address for the Ingress Controller (Traefik) must be manually configured.",
+			Severity: SeverityWarning,
+			Field:    "kubernetes.network",
+		})
+	}
+
+	return failures
+}
+
 func validateNetworkingConfig(k8s *image.Kubernetes, kubernetesConfigPath string) []FailedValidation {
 	var failures []FailedValidation
 
@@ -380,10 +429,13 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	}
 
 	seenManifests := make(map[string]bool)
-	for _, manifest := range k8s.Manifests.URLs {
+	for i, manifest := range k8s.Manifests.URLs {
+		field := fmt.Sprintf("kubernetes.manifests.urls[%d]", i)
+
 		if !strings.HasPrefix(manifest, "http") {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Entries in 'urls' must begin with either 'http://' or 'https://'.",
+				Field:       field,
 			})
 		}
 
@@ -391,6 +443,7 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'urls' field contains duplicate entries: %s", manifest)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field,
 			})
 		}
 
@@ -410,6 +463,7 @@ func validateHelm(k8s *image.Kubernetes, valuesDir, certsDir string) []FailedVal
 	if len(k8s.Helm.Repositories) == 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm charts defined with no Helm repositories defined.",
+			Field:       "kubernetes.helm.repositories",
 		})
 
 		return failures
@@ -424,56 +478,63 @@ func validateHelm(k8s *image.Kubernetes, valuesDir, certsDir string) []FailedVal
 
 	seenHelmRepos := make(map[string]bool)
 	for i := range k8s.Helm.Charts {
-		failures = append(failures, validateChart(&k8s.Helm.Charts[i], helmRepositoryNames, valuesDir)...)
+		field := fmt.Sprintf("kubernetes.helm.charts[%d]", i)
+		failures = append(failures, validateChart(&k8s.Helm.Charts[i], field, helmRepositoryNames, valuesDir)...)
 
 		seenHelmRepos[k8s.Helm.Charts[i].RepositoryName] = true
 	}
 
-	for _, repo := range k8s.Helm.Repositories {
+	for i, repo := range k8s.Helm.Repositories {
 		r := repo
-		failures = append(failures, validateRepo(&r, seenHelmRepos, certsDir)...)
+		field := fmt.Sprintf("kubernetes.helm.repositories[%d]", i)
+		failures = append(failures, validateRepo(&r, field, seenHelmRepos, certsDir)...)
 	}
 
 	return failures
 }
 
-func validateChart(chart *image.HelmChart, repositoryNames []string, valuesDir string) []FailedValidation {
+func validateChart(chart *image.HelmChart, field string, repositoryNames []string, valuesDir string) []FailedValidation {
 	var failures []FailedValidation
 
 	if chart.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm chart 'name' field must be defined.",
+			Field:       field + ".name",
 		})
 	}
 
 	if chart.RepositoryName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' field for %q must be defined.", chart.Name),
+			Field:       field + ".repositoryName",
 		})
 	} else if !slices.Contains(repositoryNames, chart.RepositoryName) {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' %q for Helm chart %q does not match the name of any defined repository.", chart.RepositoryName, chart.Name),
+			Field:       field + ".repositoryName",
 		})
 	}
 
 	if chart.Version == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'version' field for %q field must be defined.", chart.Name),
+			Field:       field + ".version",
 		})
 	}
 
 	if chart.CreateNamespace && chart.TargetNamespace == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'createNamespace' field for %q cannot be true without 'targetNamespace' being defined.", chart.Name),
+			Field:       field + ".createNamespace",
 		})
 	}
 
-	failures = append(failures, validateHelmChartValues(chart.Name, chart.ValuesFile, valuesDir)...)
+	failures = append(failures, validateHelmChartValues(chart.Name, chart.ValuesFile, field+".valuesFile", valuesDir)...)
 
 	return failures
 }
 
-func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, certsDir string) []FailedValidation {
+func validateRepo(repo *image.HelmRepository, field string, seenHelmRepos map[string]bool, certsDir string) []FailedValidation {
 	var failures []FailedValidation
 
 	parsedURL, err := url.Parse(repo.URL)
@@ -481,119 +542,133 @@ func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, cer
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository URL '%s' could not be parsed.", repo.URL),
 			Error:       err,
+			Field:       field + ".url",
 		})
 
 		return failures
 	}
 
-	failures = append(failures, validateHelmRepoName(repo, seenHelmRepos)...)
-	failures = append(failures, validateHelmRepoURL(parsedURL, repo)...)
-	failures = append(failures, validateHelmRepoAuth(repo)...)
-	failures = append(failures, validateHelmRepoArgs(parsedURL, repo)...)
-	failures = append(failures, validateHelmRepoCert(repo.Name, repo.CAFile, certsDir)...)
+	failures = append(failures, validateHelmRepoName(repo, field, seenHelmRepos)...)
+	failures = append(failures, validateHelmRepoURL(parsedURL, repo, field)...)
+	failures = append(failures, validateHelmRepoAuth(repo, field)...)
+	failures = append(failures, validateHelmRepoArgs(parsedURL, repo, field)...)
+	failures = append(failures, validateHelmRepoCert(repo.Name, repo.CAFile, field+".caFile", certsDir)...)
 
 	return failures
 }
 
-func validateHelmRepoName(repo *image.HelmRepository, seenHelmRepos map[string]bool) []FailedValidation {
+func validateHelmRepoName(repo *image.HelmRepository, field string, seenHelmRepos map[string]bool) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm repository 'name' field must be defined.",
+			Field:       field + ".name",
 		})
 	} else if !seenHelmRepos[repo.Name] {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'name' field for %q must match the 'repositoryName' field in at least one defined Helm chart.", repo.Name),
+			Field:       field + ".name",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.URL == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must be defined.", repo.Name),
+			Field:       field + ".url",
 		})
 	} else if parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme && parsedURL.Scheme != ociScheme {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must begin with either 'oci://', 'http://', or 'https://'.", repo.Name),
+			Field:       field + ".url",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoAuth(repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoAuth(repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.Authentication.Username != "" && repo.Authentication.Password == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'password' field not defined for %q.", repo.Name),
+			Field:       field + ".authentication.password",
 		})
 	}
 
 	if repo.Authentication.Username == "" && repo.Authentication.Password != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'username' field not defined for %q.", repo.Name),
+			Field:       field + ".authentication.username",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.SkipTLSVerify && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'plainHTTP' and 'skipTLSVerify' fields for %q cannot both be true.", repo.Name),
+			Field:       field + ".skipTLSVerify",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && !repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'plainHTTP' field is false.", repo.Name),
+			Field:       field + ".plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpsScheme && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'https://' but 'plainHTTP' field is true.", repo.Name),
+			Field:       field + ".plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.SkipTLSVerify {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'skipTLSVerify' field is true.", repo.Name),
+			Field:       field + ".skipTLSVerify",
 		})
 	}
 
 	if repo.SkipTLSVerify && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'skipTLSVerify' is true.", repo.Name),
+			Field:       field + ".caFile",
 		})
 	}
 
 	if repo.PlainHTTP && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'plainHTTP' is true.", repo.Name),
+			Field:       field + ".caFile",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'caFile' field is defined.", repo.Name),
+			Field:       field + ".caFile",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidation {
+func validateHelmRepoCert(repoName, certFile, field, certsDir string) []FailedValidation {
 	if certFile == "" {
 		return nil
 	}
@@ -605,6 +680,7 @@ func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidatio
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'caFile' field for %q must be the name of a valid cert file/bundle with one of the following extensions: %s",
 				repoName, strings.Join(validExtensions, ", ")),
+			Field: field,
 		})
 		return failures
 	}
@@ -615,11 +691,13 @@ func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidatio
 		if errors.Is(err, os.ErrNotExist) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm repo cert file/bundle '%s' could not be found at '%s'.", certFile, certFilePath),
+				Field:       field,
 			})
 		} else {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm repo cert file/bundle '%s' could not be read", certFile),
 				Error:       err,
+				Field:       field,
 			})
 		}
 	}
@@ -627,7 +705,7 @@ func validateHelmRepoCert(repoName, certFile, certsDir string) []FailedValidatio
 	return failures
 }
 
-func validateHelmChartValues(chartName, valuesFile, valuesDir string) []FailedValidation {
+func validateHelmChartValues(chartName, valuesFile, field, valuesDir string) []FailedValidation {
 	if valuesFile == "" {
 		return nil
 	}
@@ -637,6 +715,7 @@ func validateHelmChartValues(chartName, valuesFile, valuesDir string) []FailedVa
 	if filepath.Ext(valuesFile) != ".yaml" && filepath.Ext(valuesFile) != ".yml" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'valuesFile' field for %q must be the name of a valid yaml file ending in '.yaml' or '.yml'.", chartName),
+			Field:       field,
 		})
 		return failures
 	}
@@ -647,11 +726,13 @@ func validateHelmChartValues(chartName, valuesFile, valuesDir string) []FailedVa
 		if errors.Is(err, os.ErrNotExist) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm chart values file '%s' could not be found at '%s'.", valuesFile, valuesFilePath),
+				Field:       field,
 			})
 		} else {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm chart values file '%s' could not be read.", valuesFile),
 				Error:       err,
+				Field:       field,
 			})
 		}
 	}
@@ -675,6 +756,7 @@ func validateHelmChartDuplicates(charts []image.HelmChart) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Helm charts with the same 'name' require a unique 'releaseName'. "+
 					"Duplicate found:\n"+"Name: '%s', Release name: '%s'", chart.Name, chart.ReleaseName),
+				Field: fmt.Sprintf("kubernetes.helm.charts[%d]", i),
 			})
 		}
 
@@ -704,11 +786,13 @@ func validateAdditionalArtifacts(ctx *image.Context) []FailedValidation {
 	if len(ctx.ImageDefinition.Kubernetes.Helm.Charts) != 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes version must be defined when Helm charts are specified",
+			Field:       "kubernetes.helm.charts",
 		})
 	}
 	if len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Kubernetes version must be defined when manifest URLs are specified",
+			Field:       "kubernetes.manifests.urls",
 		})
 	}
 
@@ -43,7 +43,7 @@ func TestValidateKubernetes(t *testing.T) {
 		},
 		`all valid`: {
 			K8s: image.Kubernetes{
-				Version: "v1.30.3+k3s1",
+				Version: "v1.30.3+rke2r1",
 				Network: validNetwork,
 				Nodes: []image.Node{
 					{
@@ -209,6 +209,7 @@ func TestValidateNodes(t *testing.T) {
 			},
 			ExpectedFailedMessages: []string{
 				"The 'hostname' field is required for entries in the 'nodes' section.",
+				"Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
 			},
 		},
 		`missing type`: {
@@ -328,6 +329,7 @@ func TestValidateNodes(t *testing.T) {
 			},
 			ExpectedFailedMessages: []string{
 				"Only one node may be specified as the cluster initializer.",
+				"Kubernetes clusters consisting of two server nodes cannot form a highly available architecture.",
 			},
 		},
 	}
@@ -1371,6 +1373,64 @@ func TestValidateNetwork(t *testing.T) {
 	}
 }
 
+func TestValidateK3sIngress(t *testing.T) {
+	tests := map[string]struct {
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`rke2 multi node`: {
+			K8s: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{Hostname: "server", Type: image.KubernetesNodeTypeServer},
+					{Hostname: "agent", Type: image.KubernetesNodeTypeAgent},
+				},
+			},
+		},
+		`k3s single node without VIP`: {
+			K8s: image.Kubernetes{
+				Version: "v1.30.3+k3s1",
+			},
+		},
+		`k3s single node with VIP`: {
+			K8s: image.Kubernetes{
+				Version: "v1.30.3+k3s1",
+				Network: validNetwork,
+			},
+			ExpectedFailedMessages: []string{
+				"Virtual IP address(es) for the k3s cluster provided. An external IP address for the Ingress Controller (Traefik) must be manually configured.",
+			},
+		},
+		`k3s multi node`: {
+			K8s: image.Kubernetes{
+				Version: "v1.30.3+k3s1",
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{Hostname: "server", Type: image.KubernetesNodeTypeServer},
+					{Hostname: "agent", Type: image.KubernetesNodeTypeAgent},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"An external IP address for the Ingress Controller (Traefik) must be manually configured in multi node clusters.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := test.K8s
+			failures := validateK3sIngress(&k)
+			require.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			for i, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Equal(t, expectedMessage, failures[i].UserMessage)
+				assert.True(t, failures[i].IsWarning())
+			}
+		})
+	}
+}
+
 func TestValidateConfigInvalidServerConfigNotConfigured(t *testing.T) {
 	k8s := image.Kubernetes{Network: image.Network{
 		APIVIP4: "192.168.1.1",
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
@@ -11,6 +12,8 @@ import (
 
 const (
 	osComponent = "Operating System"
+	// autoDiskSizeAPIVersion is the API version the 'auto' disk size was introduced in.
+	autoDiskSizeAPIVersion = "1.4"
 )
 
 func validateOperatingSystem(ctx *image.Context) []FailedValidation {
@@ -41,8 +44,9 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenKeys := make(map[string]bool)
-	for _, arg := range os.KernelArgs {
+	for i, arg := range os.KernelArgs {
 		key := arg
+		field := fmt.Sprintf("operatingSystem.kernelArgs[%d]", i)
 
 		parts := strings.SplitN(arg, "=", 2)
 		if len(parts) == 2 {
@@ -51,12 +55,14 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 			if key == "" || value == "" {
 				failures = append(failures, FailedValidation{
 					UserMessage: "Kernel arguments must be specified as 'key=value'.",
+					Field:       field,
 				})
 			}
 
 			if (key == "fips" && value == "1") && !os.EnableFIPS {
 				failures = append(failures, FailedValidation{
 					UserMessage: "FIPS mode has been specified via kernel arguments, please use the 'enableFIPS: true' option instead.",
+					Field:       field,
 				})
 			}
 		}
@@ -64,6 +70,7 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 		if _, exists := seenKeys[key]; exists {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Duplicate kernel argument found: %s", key),
+				Field:       field,
 			})
 		}
 		seenKeys[key] = true
@@ -80,6 +87,7 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd enable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.systemd.enable",
 		})
 	}
 
@@ -88,15 +96,17 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd disable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.systemd.disable",
 		})
 	}
 
 	for _, enableItem := range os.Systemd.Enable {
-		for _, disableItem := range os.Systemd.Disable {
+		for i, disableItem := range os.Systemd.Disable {
 			if enableItem == disableItem {
 				msg := fmt.Sprintf("Systemd conflict found, '%s' is both enabled and disabled.", enableItem)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Field:       fmt.Sprintf("operatingSystem.systemd.disable[%d]", i),
 				})
 			}
 		}
@@ -111,10 +121,13 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 	// The script is idempotent and will not fail on creating a duplicate group,
 	// but for consistency validate that duplicates aren't in the definition.
 	seenGroupNames := make(map[string]bool)
-	for _, group := range os.Groups {
+	for i, group := range os.Groups {
+		field := fmt.Sprintf("operatingSystem.groups[%d]", i)
+
 		if group.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for all entries under 'groups'.",
+				Field:       field + ".name",
 			})
 		}
 
@@ -122,6 +135,7 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate group name found: %s", group.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field + ".name",
 			})
 		}
 		seenGroupNames[group.Name] = true
@@ -134,10 +148,13 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenUsernames := make(map[string]bool)
-	for _, user := range os.Users {
+	for i, user := range os.Users {
+		field := fmt.Sprintf("operatingSystem.users[%d]", i)
+
 		if user.Username == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'username' field is required for all entries under 'users'.",
+				Field:       field + ".username",
 			})
 		}
 
@@ -145,12 +162,14 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("User '%s' must have either a password or at least one SSH key.", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field,
 			})
 		}
 
 		if !user.CreateHomeDir && len(user.SSHKeys) > 0 {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
+				Field:       field + ".createHomeDir",
 			})
 		}
 
@@ -158,6 +177,7 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate username found: %s", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field + ".username",
 			})
 		}
 		seenUsernames[user.Username] = true
@@ -175,16 +195,19 @@ func validateSuma(os *image.OperatingSystem) []FailedValidation {
 	if os.Suma.Host == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'host' field is required for the 'suma' section.",
+			Field:       "operatingSystem.suma.host",
 		})
 	}
 	if strings.HasPrefix(os.Suma.Host, "http") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The suma 'host' field may not contain 'http://' or 'https://'",
+			Field:       "operatingSystem.suma.host",
 		})
 	}
 	if os.Suma.ActivationKey == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'activationKey' field is required for the 'suma' section.",
+			Field:       "operatingSystem.suma.activationKey",
 		})
 	}
 
@@ -197,6 +220,7 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	if slices.Contains(os.Packages.PKGList, "") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'packageList' field cannot contain empty values.",
+			Field:       "operatingSystem.packages.packageList",
 		})
 	}
 
@@ -205,6 +229,7 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("The 'packageList' field contains duplicate packages: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.packages.packageList",
 		})
 	}
 
@@ -213,11 +238,14 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	if len(os.Packages.AdditionalRepos) > 0 {
 		var repoURLs []string
 
-		for _, repo := range os.Packages.AdditionalRepos {
+		for i, repo := range os.Packages.AdditionalRepos {
+			field := fmt.Sprintf("operatingSystem.packages.additionalRepos[%d]", i)
+
 			if repo.URL == "" {
 				msg := "The 'url' field is required for all entries under 'additionalRepos'."
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Field:       field + ".url",
 				})
 			}
 
@@ -225,6 +253,7 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 				msg := "The 'priority' field for 'additionalRepos' must be a value between 0 and 99."
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Field:       field + ".priority",
 				})
 			}
 
@@ -236,6 +265,7 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("The 'additionalRepos' field contains duplicate repos: %s", duplicateValues)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "operatingSystem.packages.additionalRepos",
 			})
 		}
 	}
@@ -246,38 +276,66 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 func validateIsoConfig(def *image.Definition) []FailedValidation {
 	var failures []FailedValidation
 
-	if !strings.EqualFold(def.Image.ImageType, image.TypeISO) && def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
-		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO)
+	if !isInstallerImage(def) && def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
+		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s' or '%s'.", image.TypeISO, image.TypePXE)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.isoConfiguration.installDevice",
 		})
 	}
 
 	return failures
 }
 
+// isInstallerImage reports whether the image is built from the SelfInstall ISO, installing the
+// operating system onto a disk instead of being written to it directly.
+func isInstallerImage(def *image.Definition) bool {
+	return strings.EqualFold(def.Image.ImageType, image.TypeISO) || strings.EqualFold(def.Image.ImageType, image.TypePXE)
+}
+
 func validateRawConfig(def *image.Definition) []FailedValidation {
 	var failures []FailedValidation
 
-	if strings.EqualFold(def.Image.ImageType, image.TypeISO) {
+	failures = append(failures, validateAutoDiskSize(def.APIVersion, &def.OperatingSystem.RawConfiguration)...)
+
+	if isInstallerImage(def) {
 		if def.OperatingSystem.RawConfiguration.LUKSKey != "" {
-			msg := fmt.Sprintf("The 'luksKey' field should only be defined for '%s' encrypted images.", image.TypeRAW)
+			msg := fmt.Sprintf("The 'luksKey' field should only be defined for '%s' or '%s' encrypted images.", image.TypeRAW, image.TypeQCOW2)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "operatingSystem.rawConfiguration.luksKey",
 			})
 		}
 
 		if def.OperatingSystem.RawConfiguration.ExpandEncryptedPartition {
-			msg := fmt.Sprintf("The 'expandEncryptedPartition' field can only be defined for '%s' encrypted images.", image.TypeRAW)
+			msg := fmt.Sprintf("The 'expandEncryptedPartition' field can only be defined for '%s' or '%s' encrypted images.", image.TypeRAW, image.TypeQCOW2)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "operatingSystem.rawConfiguration.expandEncryptedPartition",
 			})
 		}
 
 		if def.OperatingSystem.RawConfiguration.DiskSize != "" {
-			msg := fmt.Sprintf("The 'diskSize' field can only be defined for '%s' images.", image.TypeRAW)
+			msg := fmt.Sprintf("The 'diskSize' field can only be defined for '%s' or '%s' images.", image.TypeRAW, image.TypeQCOW2)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       "operatingSystem.rawConfiguration.diskSize",
+			})
+		}
+
+		if def.OperatingSystem.RawConfiguration.RootSize != "" {
+			msg := fmt.Sprintf("The 'rootSize' field can only be defined for '%s' or '%s' images.", image.TypeRAW, image.TypeQCOW2)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Field:       "operatingSystem.rawConfiguration.rootSize",
+			})
+		}
+
+		if len(def.OperatingSystem.RawConfiguration.Partitions) != 0 {
+			msg := fmt.Sprintf("The 'partitions' field can only be defined for '%s' or '%s' images.", image.TypeRAW, image.TypeQCOW2)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Field:       "operatingSystem.rawConfiguration.partitions",
 			})
 		}
 
@@ -288,13 +346,192 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 		msg := "The 'expandEncryptedPartition' field cannot be 'true' when 'luksKey' is not defined."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.rawConfiguration.expandEncryptedPartition",
 		})
 	}
 
-	if def.OperatingSystem.RawConfiguration.DiskSize != "" && !def.OperatingSystem.RawConfiguration.DiskSize.IsValid() {
-		msg := "The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'."
+	diskSize := def.OperatingSystem.RawConfiguration.DiskSize
+	if diskSize != "" && !diskSize.IsAuto() && !diskSize.IsValid() {
+		msg := "The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.rawConfiguration.diskSize",
+		})
+	}
+
+	failures = append(failures, validateRawPartitions(&def.OperatingSystem.RawConfiguration)...)
+
+	return failures
+}
+
+// AutoDiskSizeAvailable returns whether the 'auto' disk size is available in the given API version.
+func AutoDiskSizeAvailable(apiVersion string) bool {
+	return strings.Compare(apiVersion, autoDiskSizeAPIVersion) >= 0
+}
+
+func validateAutoDiskSize(apiVersion string, raw *image.RawConfiguration) []FailedValidation {
+	var failures []FailedValidation
+
+	if !raw.DiskSize.IsAuto() {
+		if raw.DiskSizeHeadroom != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'diskSizeHeadroom' field can only be defined when 'diskSize' is 'auto'.",
+				Field:       "operatingSystem.rawConfiguration.diskSizeHeadroom",
+			})
+		}
+
+		if raw.MinimumDiskSize != "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'minimumDiskSize' field can only be defined when 'diskSize' is 'auto'.",
+				Field:       "operatingSystem.rawConfiguration.minimumDiskSize",
+			})
+		}
+
+		return failures
+	}
+
+	if !AutoDiskSizeAvailable(apiVersion) {
+		msg := fmt.Sprintf("The 'auto' disk size is only available in API version >= %s", autoDiskSizeAPIVersion)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       "operatingSystem.rawConfiguration.diskSize",
+		})
+	}
+
+	if raw.DiskSizeHeadroom != nil && (*raw.DiskSizeHeadroom < 0 || *raw.DiskSizeHeadroom > 100) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'diskSizeHeadroom' field must be a percentage between 0 and 100.",
+			Field:       "operatingSystem.rawConfiguration.diskSizeHeadroom",
+		})
+	}
+
+	if raw.MinimumDiskSize != "" && !raw.MinimumDiskSize.IsValid() {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'minimumDiskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			Field:       "operatingSystem.rawConfiguration.minimumDiskSize",
+		})
+	}
+
+	// The layout of the additional partitions is relative to a known disk size
+	if raw.RootSize != "" || len(raw.Partitions) != 0 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'diskSize' field cannot be 'auto' when 'rootSize' or 'partitions' are defined.",
+			Field:       "operatingSystem.rawConfiguration.diskSize",
+		})
+	}
+
+	return failures
+}
+
+func validateRawPartitions(raw *image.RawConfiguration) []FailedValidation {
+	var failures []FailedValidation
+
+	if raw.RootSize != "" && !raw.RootSize.IsValid() {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'rootSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			Field:       "operatingSystem.rawConfiguration.rootSize",
+		})
+	}
+
+	if raw.DiskSize == "" && (raw.RootSize != "" || len(raw.Partitions) != 0) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'diskSize' field must be defined when 'rootSize' or 'partitions' are defined.",
+			Field:       "operatingSystem.rawConfiguration.diskSize",
+		})
+	}
+
+	if len(raw.Partitions) == 0 {
+		return failures
+	}
+
+	if raw.RootSize == "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'rootSize' field must be defined when 'partitions' are defined.",
+			Field:       "operatingSystem.rawConfiguration.rootSize",
+		})
+	}
+
+	sizesValid := raw.DiskSize.IsValid() && raw.RootSize.IsValid()
+	labels := map[string]bool{}
+	mountPoints := map[string]bool{}
+	filesystems := []string{image.FilesystemExt4, image.FilesystemXFS, image.FilesystemBtrfs}
+
+	for i, partition := range raw.Partitions {
+		field := fmt.Sprintf("operatingSystem.rawConfiguration.partitions[%d]", i)
+
+		if !image.IsValidPartitionLabel(partition.Label) {
+			failures = append(failures, FailedValidation{
+				UserMessage: "Partition 'label' must be 1 to 12 characters long and may only contain letters, digits, '-' and '_'.",
+				Field:       field + ".label",
+			})
+		} else if labels[partition.Label] {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Duplicate partition label '%s' found.", partition.Label),
+				Field:       field + ".label",
+			})
+		}
+		labels[partition.Label] = true
+
+		if partition.Size == "" && i != len(raw.Partitions)-1 {
+			failures = append(failures, FailedValidation{
+				UserMessage: "Partition 'size' may only be omitted for the last partition.",
+				Field:       field + ".size",
+			})
+		} else if partition.Size != "" && !partition.Size.IsValid() {
+			sizesValid = false
+			failures = append(failures, FailedValidation{
+				UserMessage: "Partition 'size' must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				Field:       field + ".size",
+			})
+		}
+
+		if !slices.Contains(filesystems, partition.Filesystem) {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Partition 'filesystem' must be one of: %s.", strings.Join(filesystems, ", ")),
+				Field:       field + ".filesystem",
+			})
+		}
+
+		switch {
+		case !filepath.IsAbs(partition.MountPoint) || filepath.Clean(partition.MountPoint) == "/" ||
+			strings.ContainsAny(partition.MountPoint, " \t\n'\"\\"):
+			failures = append(failures, FailedValidation{
+				UserMessage: "Partition 'mountPoint' must be an absolute path other than '/' without whitespace or quotes.",
+				Field:       field + ".mountPoint",
+			})
+		case mountPoints[filepath.Clean(partition.MountPoint)]:
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Duplicate partition mount point '%s' found.", partition.MountPoint),
+				Field:       field + ".mountPoint",
+			})
+		}
+		mountPoints[filepath.Clean(partition.MountPoint)] = true
+
+		// The key is stored on the root filesystem to unlock the partition at boot, which must be encrypted itself
+		if partition.LUKSKey != "" && raw.LUKSKey == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "Partition 'luksKey' can only be defined for encrypted images, as the key is stored on the " +
+					"root filesystem. Please define the 'luksKey' field of the encrypted image.",
+				Field: field + ".luksKey",
+			})
+		}
+	}
+
+	if !sizesValid {
+		return failures
+	}
+
+	// The remaining space holds the boot partitions of the base image as well as the last partition if it has no size
+	total := raw.RootSize.ToMB()
+	for _, partition := range raw.Partitions {
+		total += partition.Size.ToMB()
+	}
+
+	if total >= raw.DiskSize.ToMB() {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The combined size of 'rootSize' and all partitions (%dM) must be less than 'diskSize' (%dM).",
+				total, raw.DiskSize.ToMB()),
+			Field: "operatingSystem.rawConfiguration.partitions",
 		})
 	}
 
@@ -312,6 +549,7 @@ func validateTimeSync(os *image.OperatingSystem) []FailedValidation {
 		msg := "If you're wanting to wait for NTP synchronization at boot, please ensure that you provide at least one NTP time source."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.time.ntp.forceWait",
 		})
 	}
 
@@ -329,6 +567,14 @@ func validateFIPS(os *image.OperatingSystem) []FailedValidation {
 		msg := "To enable FIPS you must either provide an SCC registration code or link an additional repository that contains the `patterns-base-fips` package."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Field:       "operatingSystem.enableFIPS",
+		})
+	} else if os.Packages.RegCode == "" {
+		msg := "FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Field:       "operatingSystem.enableFIPS",
 		})
 	}
 
@@ -351,6 +597,7 @@ func validateOperatingSystemConfigDrive(def *image.Definition) []FailedValidatio
 		if isValueNonZero(rootValue, field.Chain) {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("The '%s' field is not valid for generating config drives.", field.Key),
+				Field:       field.Key,
 			})
 		}
 	}
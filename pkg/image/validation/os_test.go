@@ -127,8 +127,8 @@ func TestValidateOperatingSystem(t *testing.T) {
 				"Duplicate group name found: dupeGroup",
 				"User 'danny' must have either a password or at least one SSH key.",
 				"The 'host' field is required for the 'suma' section.",
-				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO),
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s' or '%s'.", image.TypeISO, image.TypePXE),
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 				"The 'priority' field for 'additionalRepos' must be a value between 0 and 99.",
 			},
 		},
@@ -676,6 +676,18 @@ func TestValidateUnattended(t *testing.T) {
 				},
 			},
 		},
+		`pxe install device specified`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypePXE,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+				},
+			},
+		},
 		`not iso install device`: {
 			Definition: image.Definition{
 				Image: image.Image{
@@ -688,7 +700,7 @@ func TestValidateUnattended(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO),
+				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s' or '%s'.", image.TypeISO, image.TypePXE),
 			},
 		},
 	}
@@ -712,6 +724,8 @@ func TestValidateUnattended(t *testing.T) {
 }
 
 func TestValidateRawConfiguration(t *testing.T) {
+	headroom, noHeadroom, invalidHeadroom := 25, 0, 150
+
 	tests := map[string]struct {
 		Definition             image.Definition
 		ExpectedFailedMessages []string
@@ -743,7 +757,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`diskSize invalid as zero`: {
@@ -758,7 +772,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`diskSize invalid as lowercase character`: {
@@ -773,7 +787,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`diskSize invalid as negative number`: {
@@ -788,7 +802,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`diskSize invalid as no number provided`: {
@@ -803,7 +817,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`luksKey defined image type RAW`: {
@@ -830,7 +844,7 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The 'luksKey' field should only be defined for '%s' encrypted images.", image.TypeRAW),
+				fmt.Sprintf("The 'luksKey' field should only be defined for '%s' or '%s' encrypted images.", image.TypeRAW, image.TypeQCOW2),
 			},
 		},
 		`luksKey defined with expandEncryptedPartition true image type RAW`: {
@@ -846,6 +860,50 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 		},
+		`luksKey and diskSize defined with image type qcow2`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeQCOW2,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						LUKSKey:                  "1234",
+						ExpandEncryptedPartition: true,
+						DiskSize:                 "32G",
+					},
+				},
+			},
+		},
+		`invalid diskSize with image type qcow2`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeQCOW2,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "32",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSize' field must be 'auto' or an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			},
+		},
+		`diskSize defined with image type pxe`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypePXE,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "32G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSize' field can only be defined for 'raw' or 'qcow2' images.",
+			},
+		},
 		`luksKey not defined with expandEncryptedPartition true image type RAW`: {
 			Definition: image.Definition{
 				Image: image.Image{
@@ -874,8 +932,291 @@ func TestValidateRawConfiguration(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'luksKey' field should only be defined for 'raw' encrypted images.",
-				"The 'expandEncryptedPartition' field can only be defined for 'raw' encrypted images.",
+				"The 'luksKey' field should only be defined for 'raw' or 'qcow2' encrypted images.",
+				"The 'expandEncryptedPartition' field can only be defined for 'raw' or 'qcow2' encrypted images.",
+			},
+		},
+		`partitions valid`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+						RootSize: "20G",
+						LUKSKey:  "5678",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "rancher",
+								Size:       "30G",
+								Filesystem: image.FilesystemXFS,
+								MountPoint: "/var/lib/rancher",
+								LUKSKey:    "1234",
+							},
+							{
+								Label:      "data",
+								Filesystem: image.FilesystemExt4,
+								MountPoint: "/data",
+							},
+						},
+					},
+				},
+			},
+		},
+		`encrypted partition on unencrypted image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+						RootSize: "20G",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "rancher",
+								Filesystem: image.FilesystemXFS,
+								MountPoint: "/var/lib/rancher",
+								LUKSKey:    "1234",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Partition 'luksKey' can only be defined for encrypted images, as the key is stored on the root filesystem. " +
+					"Please define the 'luksKey' field of the encrypted image.",
+			},
+		},
+		`partitions without diskSize and rootSize`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeQCOW2,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						Partitions: []image.RawPartition{
+							{
+								Label:      "data",
+								Filesystem: image.FilesystemExt4,
+								MountPoint: "/data",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSize' field must be defined when 'rootSize' or 'partitions' are defined.",
+				"The 'rootSize' field must be defined when 'partitions' are defined.",
+			},
+		},
+		`partitions invalid`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+						RootSize: "20",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "data partition",
+								Filesystem: "ntfs",
+								MountPoint: "/",
+							},
+							{
+								Label:      "data",
+								Size:       "10B",
+								Filesystem: image.FilesystemBtrfs,
+								MountPoint: "data",
+							},
+							{
+								Label:      "data",
+								Size:       "10G",
+								Filesystem: image.FilesystemBtrfs,
+								MountPoint: "/data/",
+							},
+							{
+								Label:      "quoted",
+								Size:       "1G",
+								Filesystem: image.FilesystemExt4,
+								MountPoint: "/var/'data'",
+							},
+							{
+								Label:      "other",
+								Size:       "10G",
+								Filesystem: image.FilesystemBtrfs,
+								MountPoint: "/data",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rootSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"Partition 'label' must be 1 to 12 characters long and may only contain letters, digits, '-' and '_'.",
+				"Partition 'size' may only be omitted for the last partition.",
+				"Partition 'filesystem' must be one of: ext4, xfs, btrfs.",
+				"Partition 'mountPoint' must be an absolute path other than '/' without whitespace or quotes.",
+				"Partition 'size' must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"Partition 'mountPoint' must be an absolute path other than '/' without whitespace or quotes.",
+				"Duplicate partition label 'data' found.",
+				"Duplicate partition mount point '/data' found.",
+				"Partition 'mountPoint' must be an absolute path other than '/' without whitespace or quotes.",
+			},
+		},
+		`partitions exceeding diskSize`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "32G",
+						RootSize: "20G",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "rancher",
+								Size:       "12G",
+								Filesystem: image.FilesystemXFS,
+								MountPoint: "/var/lib/rancher",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The combined size of 'rootSize' and all partitions (32768M) must be less than 'diskSize' (32768M).",
+			},
+		},
+		`partitions with image type ISO`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						RootSize: "20G",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "data",
+								Filesystem: image.FilesystemExt4,
+								MountPoint: "/data",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rootSize' field can only be defined for 'raw' or 'qcow2' images.",
+				"The 'partitions' field can only be defined for 'raw' or 'qcow2' images.",
+			},
+		},
+		`diskSize auto with headroom and minimum`: {
+			Definition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType: image.TypeQCOW2,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize:         image.DiskSizeAuto,
+						DiskSizeHeadroom: &headroom,
+						MinimumDiskSize:  "16G",
+					},
+				},
+			},
+		},
+		`diskSize auto without headroom`: {
+			Definition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize:         image.DiskSizeAuto,
+						DiskSizeHeadroom: &noHeadroom,
+					},
+				},
+			},
+		},
+		`diskSize auto with invalid headroom and minimum`: {
+			Definition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize:         image.DiskSizeAuto,
+						DiskSizeHeadroom: &invalidHeadroom,
+						MinimumDiskSize:  "16g",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSizeHeadroom' field must be a percentage between 0 and 100.",
+				"The 'minimumDiskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			},
+		},
+		`diskSize auto with partitions`: {
+			Definition: image.Definition{
+				APIVersion: "1.4",
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: image.DiskSizeAuto,
+						RootSize: "20G",
+						Partitions: []image.RawPartition{
+							{
+								Label:      "data",
+								Filesystem: image.FilesystemExt4,
+								MountPoint: "/data",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSize' field cannot be 'auto' when 'rootSize' or 'partitions' are defined.",
+			},
+		},
+		`diskSize auto before API version 1.4`: {
+			Definition: image.Definition{
+				APIVersion: "1.3",
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: image.DiskSizeAuto,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'auto' disk size is only available in API version >= 1.4",
+			},
+		},
+		`headroom and minimum without diskSize auto`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSizeHeadroom: &noHeadroom,
+						MinimumDiskSize:  "16G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'diskSizeHeadroom' field can only be defined when 'diskSize' is 'auto'.",
+				"The 'minimumDiskSize' field can only be defined when 'diskSize' is 'auto'.",
 			},
 		},
 	}
@@ -994,6 +1335,9 @@ func TestValidateFIPS(t *testing.T) {
 					},
 				},
 			},
+			ExpectedFailedMessages: []string{
+				"FIPS enabled with no SUSE registration code provided. Package resolution may fail if additional repositories do not contain the `patterns-base-fips` package.",
+			},
 		},
 	}
 
@@ -24,10 +24,13 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 	var failures []FailedValidation
 
 	seenContainerImages := make(map[string]bool)
-	for _, cImage := range ear.ContainerImages {
+	for i, cImage := range ear.ContainerImages {
+		field := fmt.Sprintf("embeddedArtifactRegistry.images[%d]", i)
+
 		if cImage.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for each entry in 'images'.",
+				Field:       field + ".name",
 			})
 		}
 
@@ -35,6 +38,7 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 			msg := fmt.Sprintf("Duplicate image name '%s' found in the 'images' section.", cImage.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field + ".name",
 			})
 		}
 		seenContainerImages[cImage.Name] = true
@@ -56,10 +60,13 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 	var failures []FailedValidation
 
 	seenRegistryURLs := make(map[string]bool)
-	for _, registry := range ear.Registries {
+	for i, registry := range ear.Registries {
+		field := fmt.Sprintf("embeddedArtifactRegistry.registries[%d].uri", i)
+
 		if registry.URI == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'uri' field is required for each entry in 'embeddedArtifactRegistry.registries'.",
+				Field:       field,
 			})
 		}
 
@@ -68,6 +75,7 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Embedded artifact registry URI '%s' could not be parsed.", registry.URI),
 				Error:       err,
+				Field:       field,
 			})
 
 			continue
@@ -77,6 +85,7 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate registry URI '%s' found in the 'embeddedArtifactRegistry.registries' section.", registry.URI)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Field:       field,
 			})
 		}
 
@@ -89,16 +98,20 @@ func validateURLs(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 func validateCredentials(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 	var failures []FailedValidation
 
-	for _, registry := range ear.Registries {
+	for i, registry := range ear.Registries {
+		field := fmt.Sprintf("embeddedArtifactRegistry.registries[%d].authentication", i)
+
 		if registry.Authentication.Username == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'username' field is required for each entry in 'embeddedArtifactRegistry.registries.credentials'.",
+				Field:       field + ".username",
 			})
 		}
 
 		if registry.Authentication.Password == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'password' field is required for each entry in 'embeddedArtifactRegistry.registries.credentials'.",
+				Field:       field + ".password",
 			})
 		}
 	}
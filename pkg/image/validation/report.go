@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+const (
+	junitSuiteName = "eib-validate"
+)
+
+// Report is the machine-readable result of validating an image definition.
+type Report struct {
+	Valid          bool     `json:"valid"`
+	DefinitionFile string   `json:"definitionFile"`
+	Components     []string `json:"-"`
+	// Strict is set when warnings are treated as errors.
+	Strict   bool            `json:"strict"`
+	Failures []ReportFailure `json:"failures"`
+}
+
+type ReportFailure struct {
+	Component string   `json:"component"`
+	Message   string   `json:"message"`
+	Severity  Severity `json:"severity"`
+	Field     string   `json:"field,omitempty"`
+	File      string   `json:"file,omitempty"`
+	Line      int      `json:"line,omitempty"`
+	Column    int      `json:"column,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// NewReport flattens the validation failures of all components into a report.
+// Failures located in base definitions reference their own file, all others the given definition file.
+// The definition is only considered invalid because of warnings when strict is set.
+func NewReport(definitionFile string, components []string, failures map[string][]FailedValidation, strict bool) *Report {
+	report := &Report{
+		Valid:          !HasErrors(failures, strict),
+		DefinitionFile: definitionFile,
+		Components:     components,
+		Strict:         strict,
+		Failures:       []ReportFailure{},
+	}
+
+	for _, component := range components {
+		for _, f := range failures[component] {
+			failure := ReportFailure{
+				Component: component,
+				Message:   log.Redact(f.UserMessage),
+				Severity:  SeverityError,
+				Field:     f.Field,
+			}
+
+			if f.IsWarning() {
+				failure.Severity = SeverityWarning
+			}
+
+			if f.Position.IsKnown() {
+				failure.File = definitionFile
+				if f.Position.File != "" {
+					failure.File = f.Position.File
+				}
+
+				failure.Line = f.Position.Line
+				failure.Column = f.Position.Column
+			}
+
+			if f.Error != nil {
+				failure.Error = log.Redact(f.Error.Error())
+			}
+
+			report.Failures = append(report.Failures, failure)
+		}
+	}
+
+	return report
+}
+
+// UnknownFieldFailures converts the unknown fields found while parsing a definition into validation failures.
+func UnknownFieldFailures(err *image.UnknownFieldsError) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, f := range err.Fields {
+		msg := fmt.Sprintf("Unknown field '%s'.", f.Path)
+		if f.Suggestion != "" {
+			msg = fmt.Sprintf("Unknown field '%s', did you mean '%s'?", f.Path, f.Suggestion)
+		}
+
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Field:       f.Path,
+			Position:    f.Position,
+		})
+	}
+
+	return failures
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("encoding json report: %w", err)
+	}
+
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string   `xml:"message,attr"`
+	Type    Severity `xml:"type,attr"`
+	Text    string   `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML with a test suite for each validated component.
+// Components without failures are reported through a single passing test case. Warnings are
+// reported as passing test cases carrying the warning as output unless the report is strict.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: junitSuiteName}
+
+	for _, component := range r.Components {
+		suite := junitTestSuite{Name: component}
+
+		for _, f := range r.Failures {
+			if f.Component != component {
+				continue
+			}
+
+			name := f.Field
+			if name == "" {
+				name = f.Message
+			}
+
+			text := f.Message
+			if f.Line > 0 {
+				text = fmt.Sprintf("%s\n%s:%d:%d", text, f.File, f.Line, f.Column)
+			}
+			if f.Error != "" {
+				text = fmt.Sprintf("%s\n%s", text, f.Error)
+			}
+
+			testCase := junitTestCase{
+				Name:      name,
+				ClassName: component,
+			}
+
+			if f.Severity == SeverityWarning && !r.Strict {
+				testCase.SystemOut = fmt.Sprintf("%s: %s", SeverityWarning, text)
+			} else {
+				testCase.Failure = &junitFailure{
+					Message: f.Message,
+					Type:    f.Severity,
+					Text:    text,
+				}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: component, ClassName: component})
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("encoding junit report: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	return nil
+}
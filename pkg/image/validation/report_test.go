@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestNewReport(t *testing.T) {
+	failures := map[string][]FailedValidation{
+		imageComponent: {
+			{
+				UserMessage: "The 'arch' field is required in the 'image' section.",
+				Field:       "image.arch",
+				Position:    image.Position{Line: 2, Column: 1},
+			},
+		},
+		k8sComponent: {
+			{
+				UserMessage: "Helm chart values file 'values.yaml' could not be read.",
+				Error:       fmt.Errorf("permission denied"),
+				Field:       "kubernetes.helm.charts[0].valuesFile",
+				Position:    image.Position{File: "base.yaml", Line: 12, Column: 9},
+			},
+			{
+				UserMessage: "Kubernetes server config could not be read",
+			},
+		},
+	}
+
+	report := NewReport("definition.yaml", []string{imageComponent, k8sComponent, osComponent}, failures, false)
+
+	assert.False(t, report.Valid)
+	assert.Equal(t, []ReportFailure{
+		{
+			Component: imageComponent,
+			Message:   "The 'arch' field is required in the 'image' section.",
+			Severity:  SeverityError,
+			Field:     "image.arch",
+			File:      "definition.yaml",
+			Line:      2,
+			Column:    1,
+		},
+		{
+			Component: k8sComponent,
+			Message:   "Helm chart values file 'values.yaml' could not be read.",
+			Severity:  SeverityError,
+			Field:     "kubernetes.helm.charts[0].valuesFile",
+			File:      "base.yaml",
+			Line:      12,
+			Column:    9,
+			Error:     "permission denied",
+		},
+		{
+			Component: k8sComponent,
+			Message:   "Kubernetes server config could not be read",
+			Severity:  SeverityError,
+		},
+	}, report.Failures)
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	report := NewReport("definition.yaml", []string{imageComponent}, nil, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+
+	assert.Equal(t, true, decoded["valid"])
+	assert.Equal(t, "definition.yaml", decoded["definitionFile"])
+	assert.Equal(t, []any{}, decoded["failures"])
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	failures := map[string][]FailedValidation{
+		osComponent: {
+			{
+				UserMessage: "Duplicate username found: alpha",
+				Field:       "operatingSystem.users[1].username",
+				Position:    image.Position{Line: 11, Column: 7},
+			},
+		},
+	}
+
+	report := NewReport("definition.yaml", []string{imageComponent, osComponent}, failures, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="eib-validate" tests="2" failures="1">
+  <testsuite name="Image" tests="1" failures="0">
+    <testcase name="Image" classname="Image"></testcase>
+  </testsuite>
+  <testsuite name="Operating System" tests="1" failures="1">
+    <testcase name="operatingSystem.users[1].username" classname="Operating System">
+      <failure message="Duplicate username found: alpha" type="error">Duplicate username found: alpha&#xA;definition.yaml:11:7</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestReport_Warnings(t *testing.T) {
+	failures := map[string][]FailedValidation{
+		imageComponent: {
+			{
+				UserMessage: "Image build may fail as host architecture does not match the defined architecture of the output image.",
+				Severity:    SeverityWarning,
+				Field:       "image.arch",
+				Position:    image.Position{Line: 4, Column: 3},
+			},
+		},
+	}
+
+	report := NewReport("definition.yaml", []string{imageComponent}, failures, false)
+	assert.True(t, report.Valid)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, SeverityWarning, report.Failures[0].Severity)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf))
+	assert.Contains(t, buf.String(), `<testsuites name="eib-validate" tests="1" failures="0">`)
+	assert.Contains(t, buf.String(), "<system-out>warning: Image build may fail")
+
+	strictReport := NewReport("definition.yaml", []string{imageComponent}, failures, true)
+	assert.False(t, strictReport.Valid)
+
+	buf.Reset()
+	require.NoError(t, strictReport.WriteJUnit(&buf))
+	assert.Contains(t, buf.String(), `<testsuites name="eib-validate" tests="1" failures="1">`)
+	assert.Contains(t, buf.String(), `type="warning"`)
+}
+
+func TestUnknownFieldFailures(t *testing.T) {
+	err := &image.UnknownFieldsError{
+		Fields: []image.UnknownField{
+			{Path: "image.imagetype", Position: image.Position{Line: 3, Column: 3}, Suggestion: "imageType"},
+			{Path: "image.foo", Position: image.Position{Line: 4, Column: 3}},
+		},
+	}
+
+	failures := UnknownFieldFailures(err)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "Unknown field 'image.imagetype', did you mean 'imageType'?", failures[0].UserMessage)
+	assert.Equal(t, "image.imagetype", failures[0].Field)
+	assert.Equal(t, image.Position{Line: 3, Column: 3}, failures[0].Position)
+	assert.Equal(t, "Unknown field 'image.foo'.", failures[1].UserMessage)
+}
@@ -1,20 +1,53 @@
 package validation
 
 import (
+	"slices"
+
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
 type FailedValidation struct {
 	UserMessage string
 	Error       error
+	// Severity indicates whether the failure is an error or an advisory warning. Failures without
+	// an explicit severity are errors.
+	Severity Severity
+	// Field is the path of the offending field in the definition, e.g. 'kubernetes.helm.charts[2].valuesFile'.
+	Field string
+	// Position is the location of the field in the definition source, populated from the field path.
+	Position image.Position
 }
 
-type validateComponent func(ctx *image.Context) []FailedValidation
+// IsWarning returns whether the failure is advisory and does not prevent building the image.
+func (f FailedValidation) IsWarning() bool {
+	return f.Severity == SeverityWarning
+}
 
-func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
-	failures := map[string][]FailedValidation{}
+// HasErrors returns whether any of the validation failures is an error. Warnings are also
+// considered errors when strict is set.
+func HasErrors(failures map[string][]FailedValidation, strict bool) bool {
+	for _, componentFailures := range failures {
+		for _, f := range componentFailures {
+			if strict || !f.IsWarning() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type validateComponent func(ctx *image.Context) []FailedValidation
 
-	validations := map[string]validateComponent{
+func componentValidations() map[string]validateComponent {
+	return map[string]validateComponent{
 		versionComponent:   validateVersion,
 		imageComponent:     validateImage,
 		osComponent:        validateOperatingSystem,
@@ -22,9 +55,35 @@ func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
 		k8sComponent:       validateKubernetes,
 		elementalComponent: validateElemental,
 	}
-	for componentName, v := range validations {
+}
+
+// ComponentNames returns the sorted names of all components validated in the definition.
+func ComponentNames() []string {
+	var names []string
+	for name := range componentValidations() {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
+	failures := map[string][]FailedValidation{}
+
+	for componentName, v := range componentValidations() {
 		componentFailures := v(ctx)
 
+		for i := range componentFailures {
+			if componentFailures[i].Severity == "" {
+				componentFailures[i].Severity = SeverityError
+			}
+
+			if componentFailures[i].Field != "" {
+				componentFailures[i].Position = ctx.ImageDefinition.Position(componentFailures[i].Field)
+			}
+		}
+
 		if len(componentFailures) > 0 {
 			failures[componentName] = componentFailures
 		}
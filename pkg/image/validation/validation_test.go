@@ -25,6 +25,12 @@ func TestValidateDefinition(t *testing.T) {
 	_, err = os.Create(filepath.Join(testImagesDir, fakeBaseImageName))
 	require.NoError(t, err)
 
+	defaultHostArch := hostArch
+	hostArch = "amd64"
+	defer func() {
+		hostArch = defaultHostArch
+	}()
+
 	tests := map[string]struct {
 		Definition image.Definition
 		Expected   map[string][]string
@@ -116,3 +122,114 @@ func TestValidateDefinition(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDefinition_FieldPositions(t *testing.T) {
+	data := []byte(`apiVersion: 1.4
+image:
+  imageType: iso
+  arch: x86_64
+  baseImage: missing.iso
+  outputImageName: output.iso
+operatingSystem:
+  users:
+    - username: alpha
+      encryptedPassword: password
+    - username: alpha
+      encryptedPassword: password
+`)
+
+	definition, err := image.ParseDefinition(data, "")
+	require.NoError(t, err)
+
+	defaultHostArch := hostArch
+	hostArch = "amd64"
+	defer func() {
+		hostArch = defaultHostArch
+	}()
+
+	ctx := &image.Context{
+		ImageConfigDir:  "",
+		ImageDefinition: definition,
+	}
+
+	failures := ValidateDefinition(ctx)
+
+	require.Len(t, failures[imageComponent], 1)
+	baseImageFailure := failures[imageComponent][0]
+	assert.Equal(t, "image.baseImage", baseImageFailure.Field)
+	assert.Equal(t, image.Position{Line: 5, Column: 3}, baseImageFailure.Position)
+
+	require.Len(t, failures[osComponent], 1)
+	usernameFailure := failures[osComponent][0]
+	assert.Equal(t, "Duplicate username found: alpha", usernameFailure.UserMessage)
+	assert.Equal(t, "operatingSystem.users[1].username", usernameFailure.Field)
+	assert.Equal(t, image.Position{Line: 11, Column: 7}, usernameFailure.Position)
+}
+
+func TestValidateDefinition_Severity(t *testing.T) {
+	defaultHostArch := hostArch
+	hostArch = "amd64"
+	defer func() {
+		hostArch = defaultHostArch
+	}()
+
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			APIVersion: "1.4",
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeARM,
+				BaseImage:       "missing.raw",
+				OutputImageName: "output.raw",
+			},
+		},
+	}
+
+	failures := ValidateDefinition(ctx)
+
+	require.Len(t, failures[imageComponent], 2)
+	assert.Equal(t, SeverityWarning, failures[imageComponent][0].Severity)
+	assert.Equal(t, "image.arch", failures[imageComponent][0].Field)
+	assert.Equal(t, SeverityError, failures[imageComponent][1].Severity)
+	assert.Equal(t, "image.baseImage", failures[imageComponent][1].Field)
+}
+
+func TestHasErrors(t *testing.T) {
+	warning := FailedValidation{UserMessage: "warning", Severity: SeverityWarning}
+	failure := FailedValidation{UserMessage: "error"}
+
+	tests := map[string]struct {
+		Failures       map[string][]FailedValidation
+		Strict         bool
+		ExpectedErrors bool
+	}{
+		`no failures`: {
+			Failures: map[string][]FailedValidation{},
+		},
+		`warnings only`: {
+			Failures: map[string][]FailedValidation{
+				imageComponent: {warning},
+			},
+		},
+		`warnings only in strict mode`: {
+			Failures: map[string][]FailedValidation{
+				imageComponent: {warning},
+			},
+			Strict:         true,
+			ExpectedErrors: true,
+		},
+		`errors and warnings`: {
+			Failures: map[string][]FailedValidation{
+				imageComponent: {warning},
+				osComponent:    {failure},
+			},
+			ExpectedErrors: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ExpectedErrors, HasErrors(test.Failures, test.Strict))
+		})
+	}
+}
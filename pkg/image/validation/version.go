@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
@@ -34,6 +35,15 @@ var definitionFields = map[string][]imageDefinitionField{
 		{Key: "embeddedArtifactRegistry.registries", Chain: []string{"EmbeddedArtifactRegistry", "Registries"}},
 	},
 	"1.3": {{Key: "operatingSystem.packages.additionalRepos.priority", Chain: []string{"OperatingSystem", "Packages", "AdditionalRepos", "Priority"}}},
+	"1.4": {
+		{Key: "extends", Chain: []string{"Extends"}},
+		{Key: "image.containerDisk", Chain: []string{"Image", "ContainerDisk"}},
+		{Key: "image.compression", Chain: []string{"Image", "Compression"}},
+		{Key: "operatingSystem.rawConfiguration.diskSizeHeadroom", Chain: []string{"OperatingSystem", "RawConfiguration", "DiskSizeHeadroom"}},
+		{Key: "operatingSystem.rawConfiguration.minimumDiskSize", Chain: []string{"OperatingSystem", "RawConfiguration", "MinimumDiskSize"}},
+		{Key: "operatingSystem.rawConfiguration.rootSize", Chain: []string{"OperatingSystem", "RawConfiguration", "RootSize"}},
+		{Key: "operatingSystem.rawConfiguration.partitions", Chain: []string{"OperatingSystem", "RawConfiguration", "Partitions"}},
+	},
 }
 
 func validateVersion(ctx *image.Context) []FailedValidation {
@@ -49,6 +59,7 @@ func validateVersion(ctx *image.Context) []FailedValidation {
 			if isValueNonZero(rootValue, field.Chain) {
 				failures = append(failures, FailedValidation{
 					UserMessage: fmt.Sprintf("Field `%s` is only available in API version >= %s", field.Key, apiVersion),
+					Field:       field.Key,
 				})
 			}
 		}
@@ -57,6 +68,24 @@ func validateVersion(ctx *image.Context) []FailedValidation {
 	return failures
 }
 
+// UnavailableFields returns the keys of all definition fields which were introduced after the given API version.
+func UnavailableFields(apiVersion string) []string {
+	var keys []string
+
+	for v, fields := range definitionFields {
+		if strings.Compare(apiVersion, v) >= 0 {
+			continue
+		}
+
+		for _, field := range fields {
+			keys = append(keys, field.Key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
 // Check whether a value in a chain of fields is non-zero.
 func isValueNonZero(value reflect.Value, fieldChain []string) bool {
 	for i, name := range fieldChain {
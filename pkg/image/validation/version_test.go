@@ -167,6 +167,21 @@ func TestValidateVersion(t *testing.T) {
 				},
 			},
 		},
+		`invalid 1.3 definition`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.3",
+				Extends:    "base.yaml",
+			},
+			ExpectedFailedMessages: []string{
+				"Field `extends` is only available in API version >= 1.4",
+			},
+		},
+		`valid new fields for 1.4`: {
+			ImageDefinition: image.Definition{
+				APIVersion: "1.4",
+				Extends:    "base.yaml",
+			},
+		},
 	}
 
 	for name, test := range tests {
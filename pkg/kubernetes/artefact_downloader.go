@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/fs"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -44,7 +45,15 @@ type ArtefactDownloader struct {
 	K3sReleaseURL  string
 }
 
-func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error {
+// Artefact is a Kubernetes release artefact which is downloaded during the build.
+type Artefact struct {
+	Name string
+	URL  string
+	// CachedSize is the size of the artefact in bytes if it is already available in the cache.
+	CachedSize int64
+}
+
+func (d ArtefactDownloader) DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, ingressController string, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroRKE2) {
 		return fmt.Errorf("invalid RKE2 version: '%s'", version)
 	}
@@ -58,18 +67,34 @@ func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni
 		return fmt.Errorf("gathering RKE2 image artefacts: %w", err)
 	}
 
-	if err = d.downloadArtefacts(artefacts, d.Rke2ReleaseURL, version, imagesPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, d.Rke2ReleaseURL, version, imagesPath); err != nil {
 		return fmt.Errorf("downloading RKE2 image artefacts: %w", err)
 	}
 
 	artefacts = rke2InstallerArtefacts(arch)
-	if err = d.downloadArtefacts(artefacts, d.Rke2ReleaseURL, version, installPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, d.Rke2ReleaseURL, version, installPath); err != nil {
 		return fmt.Errorf("downloading RKE2 install artefacts: %w", err)
 	}
 
 	return nil
 }
 
+// RKE2Artefacts lists the RKE2 artefacts required for the given cluster configuration without downloading them.
+func (d ArtefactDownloader) RKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, ingressController string) ([]Artefact, error) {
+	if !strings.Contains(version, image.KubernetesDistroRKE2) {
+		return nil, fmt.Errorf("invalid RKE2 version: '%s'", version)
+	}
+
+	artefacts, err := rke2ImageArtefacts(cni, multusEnabled, ingressController, arch)
+	if err != nil {
+		return nil, fmt.Errorf("gathering RKE2 image artefacts: %w", err)
+	}
+
+	artefacts = append(artefacts, rke2InstallerArtefacts(arch)...)
+
+	return d.describeArtefacts(artefacts, d.Rke2ReleaseURL, version), nil
+}
+
 func rke2InstallerArtefacts(arch image.Arch) []string {
 	artefactArch := arch.Short()
 
@@ -111,24 +136,35 @@ func rke2ImageArtefacts(cni string, multusEnabled bool, ingressController string
 	return artefacts, nil
 }
 
-func (d ArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (d ArtefactDownloader) DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroK3S) {
 		return fmt.Errorf("invalid k3s version: '%s'", version)
 	}
 
 	artefacts := k3sImageArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, d.K3sReleaseURL, version, imagesPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, d.K3sReleaseURL, version, imagesPath); err != nil {
 		return fmt.Errorf("downloading k3s image artefacts: %w", err)
 	}
 
 	artefacts = k3sInstallerArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, d.K3sReleaseURL, version, installPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, d.K3sReleaseURL, version, installPath); err != nil {
 		return fmt.Errorf("downloading k3s install artefacts: %w", err)
 	}
 
 	return nil
 }
 
+// K3sArtefacts lists the k3s artefacts required for the given architecture without downloading them.
+func (d ArtefactDownloader) K3sArtefacts(arch image.Arch, version string) ([]Artefact, error) {
+	if !strings.Contains(version, image.KubernetesDistroK3S) {
+		return nil, fmt.Errorf("invalid k3s version: '%s'", version)
+	}
+
+	artefacts := append(k3sImageArtefacts(arch), k3sInstallerArtefacts(arch)...)
+
+	return d.describeArtefacts(artefacts, d.K3sReleaseURL, version), nil
+}
+
 func k3sInstallerArtefacts(arch image.Arch) []string {
 	artefactArch := arch.Short()
 
@@ -150,9 +186,36 @@ func k3sImageArtefacts(arch image.Arch) []string {
 	}
 }
 
-func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, version, destinationPath string) error {
+func (d ArtefactDownloader) describeArtefacts(artefacts []string, releaseURL, version string) []Artefact {
+	var described []Artefact
+
+	for _, artefact := range artefacts {
+		a := Artefact{
+			Name: artefact,
+			URL:  artefactURL(releaseURL, version, artefact),
+		}
+
+		if d.Cache != nil {
+			if path, err := d.Cache.Get(cacheIdentifier(version, artefact)); err == nil {
+				if info, err := os.Stat(path); err == nil {
+					a.CachedSize = info.Size()
+				}
+			}
+		}
+
+		described = append(described, a)
+	}
+
+	return described
+}
+
+func artefactURL(releaseURL, version, artefact string) string {
+	return fmt.Sprintf("%s/%s/%s", releaseURL, url.QueryEscape(version), url.QueryEscape(artefact))
+}
+
+func (d ArtefactDownloader) downloadArtefacts(ctx context.Context, artefacts []string, releaseURL, version, destinationPath string) error {
 	for _, artefact := range artefacts {
-		url := fmt.Sprintf("%s/%s/%s", releaseURL, url.QueryEscape(version), url.QueryEscape(artefact))
+		url := artefactURL(releaseURL, version, artefact)
 		path := filepath.Join(destinationPath, artefact)
 		cacheKey := cacheIdentifier(version, artefact)
 
@@ -161,8 +224,12 @@ func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, ve
 			return fmt.Errorf("retrieving artefact '%s' from cache: %w", artefact, err)
 		}
 
-		if !copied {
-			if err = d.downloadArtefact(url, path, cacheKey); err != nil {
+		if copied {
+			if err = http.RecordDownload(url, path); err != nil {
+				return fmt.Errorf("recording cached artefact '%s': %w", artefact, err)
+			}
+		} else {
+			if err = d.downloadArtefact(ctx, url, path, cacheKey); err != nil {
 				return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
 			}
 		}
@@ -194,16 +261,16 @@ func (d ArtefactDownloader) copyArtefactFromCache(cacheKey, destPath string) (bo
 	return true, nil
 }
 
-func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
+func (d ArtefactDownloader) downloadArtefact(ctx context.Context, url, path, cacheKey string) error {
 	if d.Cache == nil {
-		if err := http.DownloadFile(context.Background(), url, path, nil); err != nil {
+		if err := http.DownloadFile(ctx, url, path, nil); err != nil {
 			return fmt.Errorf("downloading artefact: %w", err)
 		}
 		return nil
 	}
 
 	reader, writer := io.Pipe()
-	errGroup, ctx := errgroup.WithContext(context.Background())
+	errGroup, ctx := errgroup.WithContext(ctx)
 	errGroup.Go(func() error {
 		defer func() {
 			if err := writer.Close(); err != nil {
@@ -1,6 +1,10 @@
 package kubernetes
 
 import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -199,3 +203,76 @@ func TestK3sImageArtefacts(t *testing.T) {
 	armArtefacts := []string{"k3s-airgap-images-arm64.tar.zst"}
 	assert.Equal(t, armArtefacts, k3sImageArtefacts(image.ArchTypeARM))
 }
+
+type mockCache struct {
+	paths map[string]string
+}
+
+func (c mockCache) Get(artefact string) (string, error) {
+	path, ok := c.paths[artefact]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	return path, nil
+}
+
+func (c mockCache) Put(string, io.Reader) error {
+	return nil
+}
+
+func TestArtefactDownloader_K3sArtefacts(t *testing.T) {
+	cachedBinary := filepath.Join(t.TempDir(), "k3s")
+	require.NoError(t, os.WriteFile(cachedBinary, []byte("binary"), 0o600))
+
+	downloader := ArtefactDownloader{
+		Cache: mockCache{
+			paths: map[string]string{"v1.30.3+k3s1/k3s": cachedBinary},
+		},
+		K3sReleaseURL: "https://github.com/k3s-io/k3s/releases/download",
+	}
+
+	artefacts, err := downloader.K3sArtefacts(image.ArchTypeX86, "v1.30.3+k3s1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Artefact{
+		{
+			Name: "k3s-airgap-images-amd64.tar.zst",
+			URL:  "https://github.com/k3s-io/k3s/releases/download/v1.30.3%2Bk3s1/k3s-airgap-images-amd64.tar.zst",
+		},
+		{
+			Name:       "k3s",
+			URL:        "https://github.com/k3s-io/k3s/releases/download/v1.30.3%2Bk3s1/k3s",
+			CachedSize: 6,
+		},
+	}, artefacts)
+
+	_, err = downloader.K3sArtefacts(image.ArchTypeX86, "v1.30.3+rke2r1")
+	assert.EqualError(t, err, "invalid k3s version: 'v1.30.3+rke2r1'")
+}
+
+func TestArtefactDownloader_RKE2Artefacts(t *testing.T) {
+	downloader := ArtefactDownloader{
+		Rke2ReleaseURL: "https://github.com/rancher/rke2/releases/download",
+	}
+
+	artefacts, err := downloader.RKE2Artefacts(image.ArchTypeX86, "v1.30.3+rke2r1", image.CNITypeCanal, true, "")
+	require.NoError(t, err)
+
+	var names []string
+	for _, a := range artefacts {
+		names = append(names, a.Name)
+		assert.Zero(t, a.CachedSize)
+	}
+
+	assert.Equal(t, []string{
+		"rke2-images-core.linux-amd64.tar.zst",
+		"rke2-images-canal.linux-amd64.tar.zst",
+		"rke2-images-multus.linux-amd64.tar.zst",
+		"rke2.linux-amd64.tar.gz",
+		"sha256sum-amd64.txt",
+	}, names)
+
+	_, err = downloader.RKE2Artefacts(image.ArchTypeX86, "v1.30.3+rke2r1", "", false, "")
+	assert.EqualError(t, err, "gathering RKE2 image artefacts: CNI not specified")
+}
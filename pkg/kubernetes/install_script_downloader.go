@@ -18,7 +18,7 @@ const (
 
 type ScriptDownloader struct{}
 
-func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
+func (d ScriptDownloader) DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error) {
 	var scriptURL string
 
 	switch distribution {
@@ -33,7 +33,7 @@ func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath st
 	installer := fmt.Sprintf("%s_installer.sh", distribution)
 	destinationPath = filepath.Join(destinationPath, installer)
 
-	if err := http.DownloadFile(context.Background(), scriptURL, destinationPath, nil); err != nil {
+	if err := http.DownloadFile(ctx, scriptURL, destinationPath, nil); err != nil {
 		return "", fmt.Errorf("downloading script: %w", err)
 	}
 
@@ -10,6 +10,9 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+// SELinuxSigningKeyName is the name of the downloaded key the SELinux policy packages are signed with.
+const SELinuxSigningKeyName = "rancher-public.key"
+
 func SELinuxPackage(version string, sources *image.ArtifactSources) (string, error) {
 
 	switch {
@@ -44,9 +47,9 @@ func SELinuxRepository(version string, sources *image.ArtifactSources) (image.Ad
 	}, nil
 }
 
-func DownloadSELinuxRPMsSigningKey(gpgKeysDir string) error {
+func DownloadSELinuxRPMsSigningKey(ctx context.Context, gpgKeysDir string) error {
 	const rancherSigningKeyURL = "https://rpm.rancher.io/public.key"
-	var signingKeyPath = filepath.Join(gpgKeysDir, "rancher-public.key")
+	var signingKeyPath = filepath.Join(gpgKeysDir, SELinuxSigningKeyName)
 
-	return http.DownloadFile(context.Background(), rancherSigningKeyURL, signingKeyPath, nil)
+	return http.DownloadFile(ctx, rancherSigningKeyURL, signingKeyPath, nil)
 }
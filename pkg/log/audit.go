@@ -2,6 +2,8 @@ package log
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"go.uber.org/zap"
@@ -15,8 +17,17 @@ const (
 	messageSuccess = "SUCCESS"
 	messageSkipped = "SKIPPED"
 	messageFailed  = "FAILED " // leave the trailing space for consistent lengths
+	messageReused  = "REUSED " // leave the trailing space for consistent lengths
 )
 
+var auditOutput io.Writer = os.Stdout
+
+// SetAuditOutput changes where user messages are displayed, e.g. to keep the standard output
+// free for machine-readable results.
+func SetAuditOutput(w io.Writer) {
+	auditOutput = w
+}
+
 // Audit displays a message to the user. This shouldn't be used for debug logging purposes; all
 // messages passed in here should be user-readable.
 func Audit(message string) {
@@ -56,8 +67,13 @@ func AuditComponentFailed(component string) {
 	Audit(message)
 }
 
+func AuditComponentReused(component string) {
+	message := formatComponentStatus(component, messageReused)
+	Audit(message)
+}
+
 func doAudit(message string, logFunc func(args ...any)) {
-	fmt.Println(message)
+	fmt.Fprintln(auditOutput, Redact(message))
 	if logFunc != nil {
 		logFunc(message)
 	}
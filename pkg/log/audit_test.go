@@ -32,6 +32,12 @@ func TestFormatComponentStatus(t *testing.T) {
 			status:    messageFailed,
 			expected:  "Mycomponent .................. [FAILED ]",
 		},
+		{
+			testName:  "Reused message",
+			component: "my component",
+			status:    messageReused,
+			expected:  "My Component ................. [REUSED ]",
+		},
 	}
 
 	// Run
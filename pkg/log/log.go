@@ -13,7 +13,12 @@ func ConfigureGlobalLogger(logFilename string) {
 	logConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	logConfig.OutputPaths = []string{logFilename}
 
-	logger := zap.Must(logConfig.Build())
+	// Make sure that no secrets end up in the log file
+	redact := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core}
+	})
+
+	logger := zap.Must(logConfig.Build(redact))
 
 	// Set our configured logger to be accessed globally by zap.L()
 	zap.ReplaceGlobals(logger)
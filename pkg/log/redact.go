@@ -0,0 +1,113 @@
+package log
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const redactedValue = "[REDACTED]"
+
+var (
+	secretsMutex sync.RWMutex
+	secrets      []string
+
+	// Command line arguments which are always followed by a sensitive value.
+	secretArgumentRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`(--(?:password|passwd|token|regcode|registration-code)(?:=|\s+))\S+`),
+		regexp.MustCompile(`(--key\s+\S+:key:)\S+`),
+	}
+)
+
+// RegisterSecrets adds values which must never be written to the user output or any of the log files.
+func RegisterSecrets(values ...string) {
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+
+	for _, v := range values {
+		if v == "" || slices.Contains(secrets, v) {
+			continue
+		}
+
+		secrets = append(secrets, v)
+	}
+
+	// Replace longer values first so that secrets containing other secrets are fully redacted
+	slices.SortFunc(secrets, func(a, b string) int {
+		return len(b) - len(a)
+	})
+}
+
+// Redact masks all registered secret values and the values of known sensitive command line arguments.
+func Redact(message string) string {
+	for _, r := range secretArgumentRegexps {
+		message = r.ReplaceAllString(message, "${1}"+redactedValue)
+	}
+
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+
+	for _, s := range secrets {
+		message = strings.ReplaceAll(message, s, redactedValue)
+	}
+
+	return message
+}
+
+type redactingWriter struct {
+	writer io.Writer
+}
+
+// NewRedactingWriter wraps a writer so that everything written through it is redacted first.
+// Redaction is applied to each write separately, which is sufficient for line based command output.
+func NewRedactingWriter(w io.Writer) io.Writer {
+	return &redactingWriter{writer: w}
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.writer, Redact(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// redactingCore redacts the messages and string fields of all entries before they are written by the wrapped core.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = Redact(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+
+	for i, f := range fields {
+		if f.Type == zapcore.StringType {
+			f.String = Redact(f.String)
+		}
+
+		redacted[i] = f
+	}
+
+	return redacted
+}
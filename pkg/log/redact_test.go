@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRedact(t *testing.T) {
+	RegisterSecrets("registry-secret", "", "scc-code")
+	RegisterSecrets("registry-secret")
+
+	tests := []struct {
+		testName string
+		message  string
+		expected string
+	}{
+		{
+			testName: "No secrets",
+			message:  "helm repo add suse-edge https://suse-edge.github.io/charts",
+			expected: "helm repo add suse-edge https://suse-edge.github.io/charts",
+		},
+		{
+			testName: "Registered secrets",
+			message:  "logging in with registry-secret and registering with scc-code",
+			expected: "logging in with [REDACTED] and registering with [REDACTED]",
+		},
+		{
+			testName: "Password argument",
+			message:  "helm registry login example.com --username user --password unregistered",
+			expected: "helm registry login example.com --username user --password [REDACTED]",
+		},
+		{
+			testName: "Password argument with equals sign",
+			message:  "hauler login example.com --password=unregistered",
+			expected: "hauler login example.com --password=[REDACTED]",
+		},
+		{
+			testName: "LUKS key argument",
+			message:  "guestfish -a image.raw --key all:key:1234 -i",
+			expected: "guestfish -a image.raw --key all:key:[REDACTED] -i",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.expected, Redact(test.message))
+		})
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	RegisterSecrets("writer-secret")
+
+	var buf bytes.Buffer
+	w := NewRedactingWriter(&buf)
+
+	n, err := w.Write([]byte("password is writer-secret\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("password is writer-secret\n"), n)
+	assert.Equal(t, "password is [REDACTED]\n", buf.String())
+}
+
+func TestRedactingCore(t *testing.T) {
+	RegisterSecrets("core-secret")
+
+	var buf bytes.Buffer
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = ""
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), zapcore.AddSync(&buf), zap.DebugLevel)
+
+	logger := zap.New(&redactingCore{Core: core}).Sugar()
+	logger.With("password", "core-secret").Infof("Logging in with %s", "core-secret")
+
+	assert.Contains(t, buf.String(), "Logging in with [REDACTED]")
+	assert.Contains(t, buf.String(), `{"password": "[REDACTED]"}`)
+	assert.NotContains(t, buf.String(), "core-secret")
+}
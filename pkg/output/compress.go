@@ -0,0 +1,135 @@
+package output
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	DefaultXZLevel   = 6
+	DefaultZstdLevel = 3
+	DefaultGzipLevel = 6
+)
+
+// LevelRange returns the lowest and highest supported level of the compression type.
+func LevelRange(compressionType string) (minimum, maximum int) {
+	switch compressionType {
+	case image.CompressionZstd:
+		return 1, 22
+	default:
+		return 1, 9
+	}
+}
+
+// Extension returns the file extension of outputs compressed with the given type.
+func Extension(compressionType string) string {
+	switch compressionType {
+	case image.CompressionXZ:
+		return ".xz"
+	case image.CompressionZstd:
+		return ".zst"
+	case image.CompressionGzip:
+		return ".gz"
+	default:
+		return ""
+	}
+}
+
+// Compress replaces the file at the given path with its compressed counterpart and returns its path.
+// Compressing is interrupted once the context is cancelled.
+func Compress(ctx context.Context, path string, compression image.Compression) (string, error) {
+	compressedPath := path + Extension(compression.Type)
+
+	source, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening output: %w", err)
+	}
+	defer source.Close()
+
+	destination, err := os.Create(compressedPath)
+	if err != nil {
+		return "", fmt.Errorf("creating compressed output: %w", err)
+	}
+	defer destination.Close()
+
+	writer, err := newWriter(destination, compression)
+	if err != nil {
+		return "", fmt.Errorf("initialising %s writer: %w", compression.Type, err)
+	}
+
+	if _, err = io.Copy(writer, fileio.ContextReader(ctx, source)); err != nil {
+		return "", fmt.Errorf("compressing output: %w", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", fmt.Errorf("finalising compressed output: %w", err)
+	}
+
+	if err = destination.Close(); err != nil {
+		return "", fmt.Errorf("closing compressed output: %w", err)
+	}
+
+	if err = os.Remove(path); err != nil {
+		return "", fmt.Errorf("removing uncompressed output: %w", err)
+	}
+
+	return compressedPath, nil
+}
+
+func newWriter(w io.Writer, compression image.Compression) (io.WriteCloser, error) {
+	level := compression.Level
+
+	switch compression.Type {
+	case image.CompressionXZ:
+		if level == 0 {
+			level = DefaultXZLevel
+		}
+
+		config := xz.WriterConfig{DictCap: xzDictCap(level)}
+		return config.NewWriter(w)
+	case image.CompressionZstd:
+		if level == 0 {
+			level = DefaultZstdLevel
+		}
+
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	case image.CompressionGzip:
+		if level == 0 {
+			level = DefaultGzipLevel
+		}
+
+		return gzip.NewWriterLevel(w, level)
+	default:
+		return nil, fmt.Errorf("unsupported compression type '%s'", compression.Type)
+	}
+}
+
+// xzDictCap returns the dictionary size of the xz utility presets for the given level.
+func xzDictCap(level int) int {
+	const mib = 1 << 20
+
+	switch {
+	case level <= 1:
+		return mib
+	case level == 2:
+		return 2 * mib
+	case level <= 4:
+		return 4 * mib
+	case level <= 6:
+		return 8 * mib
+	case level == 7:
+		return 16 * mib
+	case level == 8:
+		return 32 * mib
+	default:
+		return 64 * mib
+	}
+}
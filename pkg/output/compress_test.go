@@ -0,0 +1,106 @@
+package output
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/ulikunitz/xz"
+)
+
+func TestCompress(t *testing.T) {
+	contents := strings.Repeat("edge image builder ", 1024)
+
+	tests := map[string]struct {
+		compression       image.Compression
+		expectedExtension string
+		newReader         func(r io.Reader) (io.Reader, error)
+	}{
+		"xz": {
+			compression:       image.Compression{Type: image.CompressionXZ},
+			expectedExtension: ".xz",
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return xz.NewReader(r)
+			},
+		},
+		"zstd with level": {
+			compression:       image.Compression{Type: image.CompressionZstd, Level: 19},
+			expectedExtension: ".zst",
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return zstd.NewReader(r)
+			},
+		},
+		"gzip with level": {
+			compression:       image.Compression{Type: image.CompressionGzip, Level: 1},
+			expectedExtension: ".gz",
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "image.raw")
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+			compressedPath, err := Compress(context.Background(), path, test.compression)
+			require.NoError(t, err)
+
+			assert.Equal(t, path+test.expectedExtension, compressedPath)
+			assert.NoFileExists(t, path)
+
+			file, err := os.Open(compressedPath)
+			require.NoError(t, err)
+			defer file.Close()
+
+			reader, err := test.newReader(file)
+			require.NoError(t, err)
+
+			decompressed, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, contents, string(decompressed))
+		})
+	}
+}
+
+func TestCompress_UnsupportedType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.raw")
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+
+	_, err := Compress(context.Background(), path, image.Compression{Type: "bzip2"})
+	require.EqualError(t, err, "initialising bzip2 writer: unsupported compression type 'bzip2'")
+
+	assert.FileExists(t, path)
+}
+
+func TestCompress_Cancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.raw")
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Compress(ctx, path, image.Compression{Type: image.CompressionGzip})
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.FileExists(t, path)
+}
+
+func TestLevelRange(t *testing.T) {
+	minimum, maximum := LevelRange(image.CompressionZstd)
+	assert.Equal(t, 1, minimum)
+	assert.Equal(t, 22, maximum)
+
+	minimum, maximum = LevelRange(image.CompressionXZ)
+	assert.Equal(t, 1, minimum)
+	assert.Equal(t, 9, maximum)
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	ChecksumExtension = ".sha256"
+	MetadataExtension = ".metadata.json"
+)
+
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Metadata describes a built image or config drive.
+type Metadata struct {
+	Name             string `json:"name"`
+	Size             int64  `json:"size"`
+	SHA256           string `json:"sha256"`
+	Type             string `json:"type"`
+	Arch             string `json:"arch"`
+	Compression      string `json:"compression,omitempty"`
+	DiskSize         string `json:"diskSize,omitempty"`
+	EIBVersion       string `json:"eibVersion"`
+	DefinitionSHA256 string `json:"definitionSHA256"`
+}
+
+// WriteSidecars writes the checksum and metadata files next to the output at the given path.
+func WriteSidecars(ctx *image.Context, path string) (*Metadata, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading output info: %w", err)
+	}
+
+	checksum, err := FileSHA256(path)
+	if err != nil {
+		return nil, fmt.Errorf("calculating output checksum: %w", err)
+	}
+
+	definitionChecksum, err := definitionSHA256(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("calculating definition checksum: %w", err)
+	}
+
+	img := ctx.ImageDefinition.Image
+	metadata := &Metadata{
+		Name:             filepath.Base(path),
+		Size:             info.Size(),
+		SHA256:           checksum,
+		Type:             img.ImageType,
+		Arch:             string(img.Arch),
+		Compression:      img.Compression.Type,
+		DiskSize:         string(ctx.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize),
+		EIBVersion:       version.GetEibVersion(),
+		DefinitionSHA256: definitionChecksum,
+	}
+
+	// The format matches the output of sha256sum so that it can be verified with 'sha256sum -c'
+	checksumLine := fmt.Sprintf("%s  %s\n", checksum, metadata.Name)
+	if err = os.WriteFile(path+ChecksumExtension, []byte(checksumLine), fileio.NonExecutablePerms); err != nil {
+		return nil, fmt.Errorf("writing checksum file: %w", err)
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding metadata: %w", err)
+	}
+
+	if err = os.WriteFile(path+MetadataExtension, append(data, '\n'), fileio.NonExecutablePerms); err != nil {
+		return nil, fmt.Errorf("writing metadata file: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// definitionSHA256 returns the checksum of the definition file followed by the base definitions it extends,
+// so that it changes along with any of them. It equals the checksum of the definition file if it extends none.
+func definitionSHA256(ctx *image.Context) (string, error) {
+	files := []string{ctx.DefinitionFile}
+	for _, base := range ctx.ImageDefinition.BaseDefinitions() {
+		files = append(files, filepath.Join(ctx.ImageConfigDir, base))
+	}
+
+	hash := sha256.New()
+	for _, path := range files {
+		if err := hashFile(hash, path); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// FileSHA256 returns the hex encoded SHA256 checksum of the file at the given path.
+func FileSHA256(path string) (string, error) {
+	hash := sha256.New()
+	if err := hashFile(hash, path); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadMetadata reads the metadata sidecar of the output at the given path.
+func ReadMetadata(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path + MetadataExtension)
+	if err != nil {
+		return nil, fmt.Errorf("reading metadata file: %w", err)
+	}
+
+	var metadata Metadata
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// VerifyChecksum compares the checksum of the output at the given path against its checksum sidecar.
+func VerifyChecksum(path string) error {
+	data, err := os.ReadFile(path + ChecksumExtension)
+	if err != nil {
+		return fmt.Errorf("reading checksum file: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return fmt.Errorf("malformed checksum file, expected '<checksum>  <name>'")
+	}
+
+	expected, name := fields[0], strings.TrimPrefix(fields[1], "*")
+	if name != filepath.Base(path) {
+		return fmt.Errorf("checksum file describes '%s' instead of '%s'", name, filepath.Base(path))
+	}
+
+	checksum, err := FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("calculating output checksum: %w", err)
+	}
+
+	if checksum != expected {
+		return fmt.Errorf("%w: expected %s, found %s", ErrChecksumMismatch, expected, checksum)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+func TestWriteSidecars(t *testing.T) {
+	configDir := t.TempDir()
+
+	definitionFile := filepath.Join(configDir, "definition.yaml")
+	require.NoError(t, os.WriteFile(definitionFile, []byte("apiVersion: 1.4"), 0o600))
+
+	outputPath := filepath.Join(configDir, "image.raw.zst")
+	require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		DefinitionFile: definitionFile,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeX86,
+				OutputImageName: "image.raw",
+				Compression:     image.Compression{Type: image.CompressionZstd},
+			},
+			OperatingSystem: image.OperatingSystem{
+				RawConfiguration: image.RawConfiguration{
+					DiskSize: "32G",
+				},
+			},
+		},
+	}
+
+	metadata, err := WriteSidecars(ctx, outputPath)
+	require.NoError(t, err)
+
+	expectedMetadata := &Metadata{
+		Name:             "image.raw.zst",
+		Size:             5,
+		SHA256:           "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d",
+		Type:             image.TypeRAW,
+		Arch:             string(image.ArchTypeX86),
+		Compression:      image.CompressionZstd,
+		DiskSize:         "32G",
+		EIBVersion:       version.GetEibVersion(),
+		DefinitionSHA256: "a634e745bbcd441116eb8c50cd0b89636cc1caa96286cf97ca7a2aa653b32b4b",
+	}
+
+	assert.Equal(t, expectedMetadata, metadata)
+
+	checksum, err := os.ReadFile(outputPath + ChecksumExtension)
+	require.NoError(t, err)
+	assert.Equal(t, "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d  image.raw.zst\n", string(checksum))
+
+	data, err := os.ReadFile(outputPath + MetadataExtension)
+	require.NoError(t, err)
+
+	var written Metadata
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, *expectedMetadata, written)
+
+	read, err := ReadMetadata(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMetadata, read)
+}
+
+func TestWriteSidecars_BaseDefinitions(t *testing.T) {
+	configDir := t.TempDir()
+
+	definitionFile := filepath.Join(configDir, "definition.yaml")
+	definition := []byte("apiVersion: 1.4\nextends: common/base.yaml\n")
+	require.NoError(t, os.WriteFile(definitionFile, definition, 0o600))
+
+	baseFile := filepath.Join(configDir, "common", "base.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(baseFile), 0o700))
+	require.NoError(t, os.WriteFile(baseFile, []byte("apiVersion: 1.4\nimage:\n  imageType: raw\n"), 0o600))
+
+	outputPath := filepath.Join(configDir, "image.raw")
+	require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+
+	parsed, err := image.ParseDefinition(definition, configDir)
+	require.NoError(t, err)
+
+	ctx := &image.Context{
+		ImageConfigDir:  configDir,
+		DefinitionFile:  definitionFile,
+		ImageDefinition: parsed,
+	}
+
+	metadata, err := WriteSidecars(ctx, outputPath)
+	require.NoError(t, err)
+
+	definitionChecksum, err := FileSHA256(definitionFile)
+	require.NoError(t, err)
+
+	// cat definition.yaml common/base.yaml | sha256sum
+	assert.Equal(t, "1aa75f51578b20cbeb4a499246d144aecd44e4c32e1dab4911e0ce1f3eeaa782", metadata.DefinitionSHA256)
+	assert.NotEqual(t, definitionChecksum, metadata.DefinitionSHA256)
+
+	// Changes to the base definition are reflected in the checksum
+	require.NoError(t, os.WriteFile(baseFile, []byte("apiVersion: 1.4\nimage:\n  imageType: qcow2\n"), 0o600))
+
+	updated, err := WriteSidecars(ctx, outputPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, metadata.DefinitionSHA256, updated.DefinitionSHA256)
+}
+
+func TestFileSHA256_MissingFile(t *testing.T) {
+	_, err := FileSHA256(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "opening file")
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tests := map[string]struct {
+		checksumFile  string
+		expectedError string
+	}{
+		"Valid": {
+			checksumFile: "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d  image.raw\n",
+		},
+		"Binary Mode": {
+			checksumFile: "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d *image.raw\n",
+		},
+		"Mismatch": {
+			checksumFile:  "0000000000000000000000000000000000000000000000000000000000000000  image.raw\n",
+			expectedError: "checksum mismatch: expected 0000000000000000000000000000000000000000000000000000000000000000, found 6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d",
+		},
+		"Different File": {
+			checksumFile:  "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d  other.raw\n",
+			expectedError: "checksum file describes 'other.raw' instead of 'image.raw'",
+		},
+		"Malformed": {
+			checksumFile:  "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d\n",
+			expectedError: "malformed checksum file, expected '<checksum>  <name>'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "image.raw")
+			require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+			require.NoError(t, os.WriteFile(outputPath+ChecksumExtension, []byte(test.checksumFile), 0o600))
+
+			err := VerifyChecksum(outputPath)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum_MissingChecksumFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "image.raw")
+	require.NoError(t, os.WriteFile(outputPath, []byte("image"), 0o600))
+
+	err := VerifyChecksum(outputPath)
+	assert.ErrorContains(t, err, "reading checksum file")
+}
@@ -1,6 +1,7 @@
 package podman
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"go.uber.org/zap"
 )
 
@@ -22,7 +24,8 @@ const (
 
 // creates a listening service that answers API calls for Podman (https://docs.podman.io/en/v4.8.3/markdown/podman-system-service.1.html)
 // only way to start the service from within a container - https://github.com/containers/podman/tree/v4.8.3/pkg/bindings#starting-the-service-manually
-func setupAPIListener(out string) error {
+// The service, along with the builds it runs, is terminated once the context is cancelled.
+func setupAPIListener(ctx context.Context, out string) error {
 	log.AuditInfo("Setting up Podman API listener...")
 
 	logFile, err := os.Create(filepath.Join(out, podmanListenerLogFile))
@@ -32,25 +35,25 @@ func setupAPIListener(out string) error {
 
 	defer logFile.Close()
 
-	cmd := preparePodmanCommand(logFile)
+	cmd := preparePodmanCommand(ctx, logFile)
 	err = cmd.Start()
 	if err != nil {
 		return fmt.Errorf("error running podman system service: %w", err)
 	}
 
-	return waitForPodmanSock()
+	return waitForPodmanSock(ctx)
 }
 
-func preparePodmanCommand(out io.Writer) *exec.Cmd {
+func preparePodmanCommand(ctx context.Context, out io.Writer) *exec.Cmd {
 	args := strings.Split(podmanArgsBase, " ")
-	cmd := exec.Command(podmanExec, args...)
+	cmd := process.Command(ctx, podmanExec, args...)
 	cmd.Stdout = out
 	cmd.Stderr = out
 
 	return cmd
 }
 
-func waitForPodmanSock() error {
+func waitForPodmanSock(ctx context.Context) error {
 	const (
 		retries      = 12
 		sleepSeconds = 5
@@ -64,7 +67,12 @@ func waitForPodmanSock() error {
 		}
 
 		zap.S().Infof("'%s' file is not yet created, retrying in %d seconds", podmanSocketPath, sleepSeconds)
-		time.Sleep(sleepSeconds * time.Second)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleepSeconds * time.Second):
+		}
 	}
 
 	return fmt.Errorf("'%s' file was not created in the expected time", podmanSocketPath)
@@ -37,13 +37,14 @@ type Podman struct {
 // New setups a podman listening service and returns a connected podman client.
 //
 // Parameters:
+//   - ctx - context whose cancellation stops the listening service and aborts pending API calls
 //   - out - location for podman to output any logs created as a result of podman commands
-func New(out string) (*Podman, error) {
-	if err := setupAPIListener(out); err != nil {
+func New(ctx context.Context, out string) (*Podman, error) {
+	if err := setupAPIListener(ctx, out); err != nil {
 		return nil, fmt.Errorf("creating new podman instance: %w", err)
 	}
 
-	conn, err := bindings.NewConnection(context.Background(), fmt.Sprintf(podmanSocketURI, podmanSocketPath))
+	conn, err := bindings.NewConnection(ctx, fmt.Sprintf(podmanSocketURI, podmanSocketPath))
 	if err != nil {
 		return nil, fmt.Errorf("creating new podman connection: %w", err)
 	}
@@ -76,9 +77,16 @@ func (p *Podman) Import(tarball, ref string) error {
 // Build looks for a 'Dockerfile' in the given context and build a podman image
 // from it.
 func (p *Podman) Build(imageContext, imageName string) error {
+	return p.BuildWithVolumes(imageContext, imageName, nil)
+}
+
+// BuildWithVolumes builds a podman image like Build, bind mounting the given
+// volumes in the 'host-dir:container-dir[:options]' format into the build containers.
+func (p *Podman) BuildWithVolumes(imageContext, imageName string, volumes []string) error {
 	zap.S().Infof("Building image %s...", imageName)
 
-	logFile, err := os.Create(filepath.Join(p.out, podmanBuildLogFile))
+	// The log is shared between all images built in the same build directory
+	logFile, err := os.OpenFile(filepath.Join(p.out, podmanBuildLogFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileio.NonExecutablePerms)
 	if err != nil {
 		return fmt.Errorf("generating podman build log file: %w", err)
 	}
@@ -92,6 +100,7 @@ func (p *Podman) Build(imageContext, imageName string) error {
 			Err:              logFile,
 			CommonBuildOpts: &define.CommonBuildOptions{
 				HTTPProxy: true,
+				Volumes:   volumes,
 			},
 		},
 	}
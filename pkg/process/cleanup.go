@@ -0,0 +1,59 @@
+package process
+
+import "sync"
+
+// cleanups holds the registered cleanups which are yet to run.
+var cleanups struct {
+	sync.Mutex
+	registered []*Cleanup
+}
+
+// Cleanup is a function which reverts changes outside of the build directory, such as the Podman mount
+// configuration. It is run by the code which registered it, or by RunCleanups if the build exits without
+// unwinding, e.g. on a second signal.
+type Cleanup struct {
+	once sync.Once
+	fn   func()
+}
+
+// RegisterCleanup registers the given function until it is run or released.
+func RegisterCleanup(fn func()) *Cleanup {
+	c := &Cleanup{fn: fn}
+
+	cleanups.Lock()
+	defer cleanups.Unlock()
+
+	cleanups.registered = append(cleanups.registered, c)
+	return c
+}
+
+// Run runs the cleanup unless it has already run, and removes its registration.
+func (c *Cleanup) Run() {
+	c.Release()
+	c.once.Do(c.fn)
+}
+
+// Release removes the registration of the cleanup without running it.
+func (c *Cleanup) Release() {
+	cleanups.Lock()
+	defer cleanups.Unlock()
+
+	for i, registered := range cleanups.registered {
+		if registered == c {
+			cleanups.registered = append(cleanups.registered[:i], cleanups.registered[i+1:]...)
+			return
+		}
+	}
+}
+
+// RunCleanups runs all registered cleanups in the reverse order of their registration.
+func RunCleanups() {
+	cleanups.Lock()
+	registered := cleanups.registered
+	cleanups.registered = nil
+	cleanups.Unlock()
+
+	for i := len(registered) - 1; i >= 0; i-- {
+		registered[i].once.Do(registered[i].fn)
+	}
+}
@@ -0,0 +1,28 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCleanups(t *testing.T) {
+	var calls []string
+
+	first := RegisterCleanup(func() { calls = append(calls, "first") })
+	second := RegisterCleanup(func() { calls = append(calls, "second") })
+	released := RegisterCleanup(func() { calls = append(calls, "released") })
+	third := RegisterCleanup(func() { calls = append(calls, "third") })
+
+	released.Release()
+	second.Run()
+
+	RunCleanups()
+
+	// Cleanups only run once, regardless of whether they are run directly or through RunCleanups
+	first.Run()
+	third.Run()
+	RunCleanups()
+
+	assert.Equal(t, []string{"second", "third", "first"}, calls)
+}
@@ -0,0 +1,74 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// waitDelay is the time given to a cancelled command to exit before it is killed.
+const waitDelay = 10 * time.Second
+
+// cancelled holds the process groups of the commands which were terminated by cancelling their context.
+var cancelled struct {
+	sync.Mutex
+	groups []int
+}
+
+// Command prepares a command which is terminated along with all of its children once the context is cancelled.
+//
+// The command is started in its own process group, so that tools spawned by scripts (e.g. guestfish,
+// virt-resize or the processes of the Podman service) do not outlive the build. The whole group receives a
+// SIGTERM and the command itself is killed if it has not exited after waitDelay. The remaining processes of
+// the group can be killed right away with KillCancelled.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		trackCancelled(cmd)
+		return signalGroup(cmd, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = waitDelay
+
+	return cmd
+}
+
+// KillCancelled sends SIGKILL to the process groups of all cancelled commands,
+// e.g. when the build is to exit without waiting for them to terminate.
+func KillCancelled() {
+	cancelled.Lock()
+	defer cancelled.Unlock()
+
+	for _, pgid := range cancelled.groups {
+		// The group no longer exists if all of its processes have already exited
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	}
+}
+
+func trackCancelled(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	cancelled.Lock()
+	defer cancelled.Unlock()
+
+	cancelled.groups = append(cancelled.groups, cmd.Process.Pid)
+}
+
+func signalGroup(cmd *exec.Cmd, signal syscall.Signal) error {
+	if cmd.Process == nil {
+		return nil
+	}
+
+	err := syscall.Kill(-cmd.Process.Pid, signal)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+
+	return err
+}
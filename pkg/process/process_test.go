@@ -0,0 +1,90 @@
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out, err := Command(ctx, "echo", "hello").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(out))
+
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	// The shell waits for a child which must be terminated along with it
+	cmd := Command(ctx, "sh", "-c", "sleep 60 & echo $! > "+pidFile+"; wait")
+	require.NoError(t, cmd.Start())
+
+	var pid int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil || !strings.HasSuffix(string(data), "\n") {
+			return false
+		}
+
+		pid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	cancel()
+
+	require.Error(t, cmd.Wait())
+	assert.Less(t, time.Since(start), waitDelay)
+
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(pid, 0) != nil
+	}, 5*time.Second, 10*time.Millisecond, "child process was not terminated")
+}
+
+func TestCommandNotStarted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Command(ctx, "echo", "hello").Run()
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestKillCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	// The shell and its child ignore SIGTERM, so they keep running after the cancellation
+	cmd := Command(ctx, "sh", "-c", "trap '' TERM; sleep 60 & echo $! > "+pidFile+"; wait")
+	require.NoError(t, cmd.Start())
+
+	var pid int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil || !strings.HasSuffix(string(data), "\n") {
+			return false
+		}
+
+		pid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		KillCancelled()
+		return syscall.Kill(pid, 0) != nil
+	}, 5*time.Second, 10*time.Millisecond, "child process was not killed")
+
+	require.Error(t, cmd.Wait())
+	assert.Less(t, time.Since(start), waitDelay)
+}
@@ -0,0 +1,114 @@
+package provenance
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/signing"
+)
+
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope wrapping the statement. It does not hold any signatures
+// unless the statement was signed.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+type Signature struct {
+	KeyID     string `json:"keyid,omitempty"`
+	Signature string `json:"sig"`
+}
+
+// NewEnvelope wraps the statement into a DSSE envelope, signed with the given key if it is not nil.
+func NewEnvelope(statement *Statement, key crypto.Signer) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("encoding statement: %w", err)
+	}
+
+	envelope := &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}
+
+	if key == nil {
+		return envelope, nil
+	}
+
+	signature, err := signing.Sign(key, pae(PayloadType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("signing statement: %w", err)
+	}
+
+	keyID, err := signing.KeyID(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("identifying key: %w", err)
+	}
+
+	envelope.Signatures = append(envelope.Signatures, Signature{
+		KeyID:     keyID,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	})
+
+	return envelope, nil
+}
+
+// Write stores the envelope as a single line of JSON.
+func (e *Envelope) Write(path string) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding envelope: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing envelope: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks that the envelope is signed by the given key and returns the statement it holds.
+func (e *Envelope) Verify(key crypto.PublicKey) (*Statement, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+
+	verified := false
+	for _, s := range e.Signatures {
+		signature, err := base64.StdEncoding.DecodeString(s.Signature)
+		if err != nil {
+			continue
+		}
+
+		if signing.Verify(key, pae(e.PayloadType, payload), signature) == nil {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, errors.New("no valid signature found for the given key")
+	}
+
+	var statement Statement
+	if err = json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("decoding statement: %w", err)
+	}
+
+	return &statement, nil
+}
+
+// pae returns the DSSE pre-authentication encoding of the payload, which is the message being signed.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
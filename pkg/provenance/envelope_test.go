@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope_SignAndVerify(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	statement := &Statement{
+		Type:          StatementType,
+		Subject:       []ResourceDescriptor{{Name: "image.raw", Digest: map[string]string{"sha256": imageDigest}}},
+		PredicateType: PredicateType,
+	}
+
+	envelope, err := NewEnvelope(statement, private)
+	require.NoError(t, err)
+
+	assert.Equal(t, PayloadType, envelope.PayloadType)
+	require.Len(t, envelope.Signatures, 1)
+	assert.NotEmpty(t, envelope.Signatures[0].KeyID)
+
+	path := filepath.Join(t.TempDir(), "image.raw"+Extension)
+	require.NoError(t, envelope.Write(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var written Envelope
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	verified, err := written.Verify(public)
+	require.NoError(t, err)
+	assert.Equal(t, statement.Subject, verified.Subject)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = written.Verify(other)
+	assert.EqualError(t, err, "no valid signature found for the given key")
+}
+
+func TestEnvelope_Unsigned(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	envelope, err := NewEnvelope(&Statement{Type: StatementType}, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, envelope.Signatures)
+
+	_, err = envelope.Verify(public)
+	assert.EqualError(t, err, "no valid signature found for the given key")
+}
@@ -0,0 +1,295 @@
+package provenance
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/output"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	Extension = ".intoto.jsonl"
+
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://slsa.dev/provenance/v1"
+
+	BuildType = "https://github.com/suse-edge/edge-image-builder/buildtypes/image@v1"
+	BuilderID = "https://github.com/suse-edge/edge-image-builder"
+)
+
+// Statement is an in-toto attestation statement with a SLSA provenance predicate.
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies"`
+}
+
+// ExternalParameters are the user controlled inputs of the build.
+type ExternalParameters struct {
+	DefinitionFile  string `json:"definitionFile"`
+	ImageType       string `json:"imageType"`
+	Arch            string `json:"arch,omitempty"`
+	OutputImageName string `json:"outputImageName"`
+	ConfigDrive     bool   `json:"configDrive,omitempty"`
+}
+
+// InternalParameters are the inputs of the build controlled by the EIB installation.
+type InternalParameters struct {
+	ArtifactSources *image.ArtifactSources `json:"artifactSources,omitempty"`
+}
+
+type RunDetails struct {
+	Builder  Builder  `json:"builder"`
+	Metadata Metadata `json:"metadata"`
+}
+
+type Builder struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version"`
+}
+
+type Metadata struct {
+	InvocationID string `json:"invocationId"`
+	StartedOn    string `json:"startedOn"`
+	FinishedOn   string `json:"finishedOn"`
+}
+
+// NewStatement assembles the provenance of the given output. The inputs of the build are the definition file
+// along with the base definitions it extends, the contents of the image configuration directory, the artifact
+// sources file, every downloaded file and the container images and Helm charts recorded in the SBOM of the image.
+func NewStatement(ctx *image.Context, outputPath string, startedOn, finishedOn time.Time) (*Statement, error) {
+	subjects, err := Subjects(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("describing output: %w", err)
+	}
+
+	dependencies, err := resolvedDependencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("describing build inputs: %w", err)
+	}
+
+	def := ctx.ImageDefinition
+
+	return &Statement{
+		Type:          StatementType,
+		Subject:       subjects,
+		PredicateType: PredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType: BuildType,
+				ExternalParameters: ExternalParameters{
+					DefinitionFile:  filepath.Base(ctx.DefinitionFile),
+					ImageType:       def.Image.ImageType,
+					Arch:            string(def.Image.Arch),
+					OutputImageName: def.Image.OutputImageName,
+					ConfigDrive:     ctx.IsConfigDrive,
+				},
+				InternalParameters: InternalParameters{
+					ArtifactSources: ctx.ArtifactSources,
+				},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					ID:      BuilderID,
+					Version: map[string]string{"edge-image-builder": version.GetEibVersion()},
+				},
+				Metadata: Metadata{
+					InvocationID: filepath.Base(ctx.BuildDir),
+					StartedOn:    startedOn.UTC().Format(time.RFC3339),
+					FinishedOn:   finishedOn.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+	}, nil
+}
+
+// Subjects describes the output at the given path. Outputs written as directories,
+// such as PXE artefacts, are described by each of the files they contain.
+func Subjects(outputPath string) ([]ResourceDescriptor, error) {
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading output info: %w", err)
+	}
+
+	if !info.IsDir() {
+		subject, err := fileDescriptor(outputPath, filepath.Base(outputPath))
+		if err != nil {
+			return nil, err
+		}
+
+		return []ResourceDescriptor{subject}, nil
+	}
+
+	return dirDescriptors(outputPath, filepath.Base(outputPath))
+}
+
+func resolvedDependencies(ctx *image.Context) ([]ResourceDescriptor, error) {
+	var dependencies []ResourceDescriptor
+
+	definition, err := fileDescriptor(ctx.DefinitionFile, filepath.Base(ctx.DefinitionFile))
+	if err != nil {
+		return nil, fmt.Errorf("describing definition file: %w", err)
+	}
+	dependencies = append(dependencies, definition)
+
+	for _, base := range ctx.ImageDefinition.BaseDefinitions() {
+		// Base definitions in subdirectories are described along with the rest of the configuration directory
+		if filepath.Dir(filepath.Clean(base)) != "." {
+			continue
+		}
+
+		descriptor, err := fileDescriptor(filepath.Join(ctx.ImageConfigDir, base), filepath.Clean(base))
+		if err != nil {
+			return nil, fmt.Errorf("describing base definition file: %w", err)
+		}
+		dependencies = append(dependencies, descriptor)
+	}
+
+	configFiles, err := configDirDescriptors(ctx.ImageConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("describing configuration directory: %w", err)
+	}
+	dependencies = append(dependencies, configFiles...)
+
+	if ctx.ArtifactSourcesFile != "" {
+		artifactSources, err := fileDescriptor(ctx.ArtifactSourcesFile, filepath.Base(ctx.ArtifactSourcesFile))
+		if err != nil {
+			return nil, fmt.Errorf("describing artifact sources file: %w", err)
+		}
+		dependencies = append(dependencies, artifactSources)
+	}
+
+	for _, download := range http.Downloads() {
+		dependencies = append(dependencies, ResourceDescriptor{
+			URI:    download.URL,
+			Digest: map[string]string{"sha256": download.SHA256},
+		})
+	}
+
+	for _, component := range ctx.SBOM.Components() {
+		switch {
+		case component.Type == sbom.TypeContainerImage && component.Digest != "":
+			algorithm, digest, _ := strings.Cut(component.Digest, ":")
+
+			uri := "docker://" + component.Name
+			if component.Version != "" {
+				uri += ":" + component.Version
+			}
+
+			dependencies = append(dependencies, ResourceDescriptor{
+				URI:    uri,
+				Digest: map[string]string{algorithm: digest},
+			})
+		case component.Type == sbom.TypeHelmChart:
+			dependencies = append(dependencies, ResourceDescriptor{
+				Name:        component.Name,
+				URI:         component.Source,
+				Digest:      map[string]string{"sha256": component.SHA256},
+				Annotations: map[string]string{"version": component.Version},
+			})
+		}
+	}
+
+	return dependencies, nil
+}
+
+// configDirDescriptors describes the files in the subdirectories of the image configuration directory.
+// Files in the root of the directory are either definition files or the output of previous runs and are
+// skipped along with the build directories. Secrets are skipped so that their digests are not disclosed.
+func configDirDescriptors(configDir string) ([]ResourceDescriptor, error) {
+	entries, err := os.ReadDir(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration directory: %w", err)
+	}
+
+	var descriptors []ResourceDescriptor
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == image.SecretsDir || strings.HasPrefix(entry.Name(), "_") {
+			continue
+		}
+
+		d, err := dirDescriptors(filepath.Join(configDir, entry.Name()), entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		descriptors = append(descriptors, d...)
+	}
+
+	return descriptors, nil
+}
+
+// dirDescriptors describes all regular files in the directory, named by their path prefixed with the given name.
+func dirDescriptors(dir, name string) ([]ResourceDescriptor, error) {
+	var descriptors []ResourceDescriptor
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		descriptor, err := fileDescriptor(path, filepath.Join(name, relativePath))
+		if err != nil {
+			return err
+		}
+
+		descriptors = append(descriptors, descriptor)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking directory %s: %w", dir, err)
+	}
+
+	return descriptors, nil
+}
+
+func fileDescriptor(path, name string) (ResourceDescriptor, error) {
+	checksum, err := output.FileSHA256(path)
+	if err != nil {
+		return ResourceDescriptor{}, fmt.Errorf("calculating checksum of %s: %w", name, err)
+	}
+
+	return ResourceDescriptor{
+		Name:   name,
+		Digest: map[string]string{"sha256": checksum},
+	}, nil
+}
@@ -0,0 +1,169 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/sbom"
+)
+
+// sha256 of "image"
+const imageDigest = "6105d6cc76af400325e94d588ce511be5bfdbb73b437dc51eca43917d7a43e3d"
+
+func writeFile(t *testing.T, path string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+}
+
+func setupContext(t *testing.T) *image.Context {
+	configDir := t.TempDir()
+
+	writeFile(t, filepath.Join(configDir, "definition.yaml"))
+	writeFile(t, filepath.Join(configDir, "base-images", "base.raw"))
+	writeFile(t, filepath.Join(configDir, "network", "node1.yaml"))
+	writeFile(t, filepath.Join(configDir, image.SecretsDir, "password"))
+	writeFile(t, filepath.Join(configDir, "_build", "build-Oct18_12-00-00", "eib-build.log"))
+	writeFile(t, filepath.Join(configDir, "image.raw"))
+
+	artifactSources := filepath.Join(t.TempDir(), "artifacts.yaml")
+	writeFile(t, artifactSources)
+
+	ctx := &image.Context{
+		ImageConfigDir:      configDir,
+		BuildDir:            filepath.Join(configDir, "_build", "build-Oct18_12-00-00"),
+		DefinitionFile:      filepath.Join(configDir, "definition.yaml"),
+		ArtifactSourcesFile: artifactSources,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeX86,
+				OutputImageName: "image.raw",
+			},
+		},
+	}
+
+	ctx.SBOM.Add(
+		sbom.Component{Type: sbom.TypePackage, Name: "git", Version: "2.43.0-1.1", SHA256: "c1"},
+		sbom.Component{Type: sbom.TypeContainerImage, Name: "docker.io/library/hello-world", Version: "latest", Digest: "sha256:abc123"},
+		sbom.Component{Type: sbom.TypeContainerImage, Name: "quay.io/podman/hello"},
+		sbom.Component{Type: sbom.TypeHelmChart, Name: "apache", Version: "10.7.0", SHA256: "c2", Source: "oci://registry-1.docker.io/bitnamicharts"},
+	)
+
+	return ctx
+}
+
+func TestNewStatement(t *testing.T) {
+	ctx := setupContext(t)
+
+	startedOn := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	finishedOn := startedOn.Add(time.Hour)
+
+	statement, err := NewStatement(ctx, ctx.OutputPath(), startedOn, finishedOn)
+	require.NoError(t, err)
+
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, PredicateType, statement.PredicateType)
+	assert.Equal(t, []ResourceDescriptor{
+		{Name: "image.raw", Digest: map[string]string{"sha256": imageDigest}},
+	}, statement.Subject)
+
+	buildDefinition := statement.Predicate.BuildDefinition
+	assert.Equal(t, ExternalParameters{
+		DefinitionFile:  "definition.yaml",
+		ImageType:       image.TypeRAW,
+		Arch:            string(image.ArchTypeX86),
+		OutputImageName: "image.raw",
+	}, buildDefinition.ExternalParameters)
+
+	dependencies := buildDefinition.ResolvedDependencies
+	assert.Equal(t, []ResourceDescriptor{
+		{Name: "definition.yaml", Digest: map[string]string{"sha256": imageDigest}},
+		{Name: "base-images/base.raw", Digest: map[string]string{"sha256": imageDigest}},
+		{Name: "network/node1.yaml", Digest: map[string]string{"sha256": imageDigest}},
+		{Name: "artifacts.yaml", Digest: map[string]string{"sha256": imageDigest}},
+	}, dependencies[:4])
+
+	// Downloads recorded by other tests of the process may precede the SBOM entries
+	assert.Equal(t, []ResourceDescriptor{
+		{
+			URI:    "docker://docker.io/library/hello-world:latest",
+			Digest: map[string]string{"sha256": "abc123"},
+		},
+		{
+			Name:        "apache",
+			URI:         "oci://registry-1.docker.io/bitnamicharts",
+			Digest:      map[string]string{"sha256": "c2"},
+			Annotations: map[string]string{"version": "10.7.0"},
+		},
+	}, dependencies[len(dependencies)-2:])
+
+	runDetails := statement.Predicate.RunDetails
+	assert.Equal(t, BuilderID, runDetails.Builder.ID)
+	assert.Contains(t, runDetails.Builder.Version, "edge-image-builder")
+	assert.Equal(t, Metadata{
+		InvocationID: "build-Oct18_12-00-00",
+		StartedOn:    "2026-10-18T12:00:00Z",
+		FinishedOn:   "2026-10-18T13:00:00Z",
+	}, runDetails.Metadata)
+}
+
+func TestNewStatement_MissingDefinition(t *testing.T) {
+	ctx := setupContext(t)
+	require.NoError(t, os.Remove(ctx.DefinitionFile))
+
+	_, err := NewStatement(ctx, ctx.OutputPath(), time.Now(), time.Now())
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "describing build inputs: describing definition file: ")
+}
+
+func TestNewStatement_BaseDefinitions(t *testing.T) {
+	ctx := setupContext(t)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(ctx.ImageConfigDir, "common"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "base.yaml"),
+		[]byte("extends: common/base.yaml\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "common", "base.yaml"),
+		[]byte("apiVersion: 1.4\nimage:\n  imageType: raw\n"), 0o600))
+
+	definition, err := image.ParseDefinition([]byte("extends: base.yaml\n"), ctx.ImageConfigDir)
+	require.NoError(t, err)
+	ctx.ImageDefinition = definition
+	ctx.ImageDefinition.Image.OutputImageName = "image.raw"
+
+	statement, err := NewStatement(ctx, ctx.OutputPath(), time.Now(), time.Now())
+	require.NoError(t, err)
+
+	dependencies := statement.Predicate.BuildDefinition.ResolvedDependencies
+	require.GreaterOrEqual(t, len(dependencies), 3)
+	assert.Equal(t, "definition.yaml", dependencies[0].Name)
+	assert.Equal(t, "base.yaml", dependencies[1].Name)
+	assert.Equal(t, "base-images/base.raw", dependencies[2].Name)
+
+	// Base definitions in subdirectories are only described once
+	var count int
+	for _, d := range dependencies {
+		if d.Name == "common/base.yaml" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+}
+
+func TestSubjects_Directory(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "pxe")
+	writeFile(t, filepath.Join(outputDir, "linux"))
+	writeFile(t, filepath.Join(outputDir, "initrd"))
+
+	subjects, err := Subjects(outputDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ResourceDescriptor{
+		{Name: "pxe/initrd", Digest: map[string]string{"sha256": imageDigest}},
+		{Name: "pxe/linux", Digest: map[string]string{"sha256": imageDigest}},
+	}, subjects)
+}
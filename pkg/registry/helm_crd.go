@@ -11,6 +11,8 @@ const (
 	helmChartKind       = "HelmChart"
 	helmChartSource     = "edge-image-builder"
 	helmBackoffLimit    = 20
+
+	repositoryURLAnnotation = "edge.suse.com/repository-url"
 )
 
 type HelmCRD struct {
@@ -50,8 +52,8 @@ func NewHelmCRD(chart *image.HelmChart, chartContent, valuesContent, repositoryU
 			Name:      name,
 			Namespace: chart.InstallationNamespace,
 			Annotations: map[string]string{
-				"edge.suse.com/source":         helmChartSource,
-				"edge.suse.com/repository-url": repositoryURL,
+				"edge.suse.com/source":  helmChartSource,
+				repositoryURLAnnotation: repositoryURL,
 			},
 		},
 		Spec: struct {
@@ -71,3 +73,8 @@ func NewHelmCRD(chart *image.HelmChart, chartContent, valuesContent, repositoryU
 		},
 	}
 }
+
+// RepositoryURL returns the URL of the repository the chart was pulled from.
+func (crd *HelmCRD) RepositoryURL() string {
+	return crd.Metadata.Annotations[repositoryURLAnnotation]
+}
@@ -13,9 +13,14 @@ import (
 )
 
 func (r *Registry) manifestImages() ([]string, error) {
+	return ManifestImages(r.manifestsDir)
+}
+
+// ManifestImages returns the container images referenced by the Kubernetes manifests in the given directory.
+func ManifestImages(manifestsDir string) ([]string, error) {
 	containerImages := make(map[string]bool)
 
-	entries, err := os.ReadDir(r.manifestsDir)
+	entries, err := os.ReadDir(manifestsDir)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
@@ -24,7 +29,7 @@ func (r *Registry) manifestImages() ([]string, error) {
 	}
 
 	for _, entry := range entries {
-		path := filepath.Join(r.manifestsDir, entry.Name())
+		path := filepath.Join(manifestsDir, entry.Name())
 
 		resources, err := readManifest(path)
 		if err != nil {
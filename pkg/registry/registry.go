@@ -1,7 +1,6 @@
 package registry
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -79,7 +78,7 @@ func storeManifests(ctx *image.Context, localManifestsDir string) (string, error
 		for index, manifestURL := range manifestURLs {
 			filePath := filepath.Join(manifestsDestDir, fmt.Sprintf("dl-manifest-%d.yaml", index+1))
 
-			if err := http.DownloadFile(context.Background(), manifestURL, filePath, nil); err != nil {
+			if err := http.DownloadFile(ctx.BuildContext(), manifestURL, filePath, nil); err != nil {
 				return "", fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
 			}
 		}
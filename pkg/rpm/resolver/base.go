@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -28,6 +30,8 @@ type ImageImporter interface {
 }
 
 type TarballImageBuilder struct {
+	// context whose cancellation terminates the preparation of the tarball
+	context context.Context
 	// dir from where the image builder will work
 	dir string
 	// path to the ISO/RAW file from which a tarball will be created
@@ -40,10 +44,13 @@ type TarballImageBuilder struct {
 	luksKey string
 	// imgImporter used to import the tarball archive as a container image
 	imgImporter ImageImporter
+	// helper property; reference of the imported image, set once it has been built
+	imgRef string
 }
 
-func NewTarballBuilder(workDir, imgPath, imgType, arch, luksKey string, importer ImageImporter) *TarballImageBuilder {
+func NewTarballBuilder(ctx context.Context, workDir, imgPath, imgType, arch, luksKey string, importer ImageImporter) *TarballImageBuilder {
 	return &TarballImageBuilder{
+		context:     ctx,
 		dir:         workDir,
 		imgPath:     imgPath,
 		imgType:     imgType,
@@ -53,7 +60,13 @@ func NewTarballBuilder(workDir, imgPath, imgType, arch, luksKey string, importer
 	}
 }
 
+// Build creates the tarball image and imports it. The image is only built once, so that it can be
+// shared between the RPM resolver and the combustion sandbox.
 func (t *TarballImageBuilder) Build() (string, error) {
+	if t.imgRef != "" {
+		return t.imgRef, nil
+	}
+
 	zap.L().Info("Building tarball image...")
 	defer os.RemoveAll(t.getTarballImgDir())
 
@@ -75,7 +88,8 @@ func (t *TarballImageBuilder) Build() (string, error) {
 	}
 
 	zap.L().Info("Tarball image build successful")
-	return tarballImgRef, nil
+	t.imgRef = tarballImgRef
+	return t.imgRef, nil
 }
 
 func (t *TarballImageBuilder) prepareTarball() error {
@@ -141,7 +155,7 @@ func (t *TarballImageBuilder) runTarballImageScript() error {
 
 func (t *TarballImageBuilder) prepareTarballImageCmd(log io.Writer) *exec.Cmd {
 	scriptPath := filepath.Join(t.dir, prepareTarballScriptName)
-	cmd := exec.Command(scriptPath)
+	cmd := process.Command(t.context, scriptPath)
 	cmd.Stdout = log
 	cmd.Stderr = log
 	return cmd
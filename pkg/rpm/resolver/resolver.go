@@ -10,6 +10,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/mount"
+	"github.com/suse-edge/edge-image-builder/pkg/process"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -88,11 +89,13 @@ func (r *Resolver) Resolve(packages *image.Packages, localRPMConfig *image.Local
 	if err != nil {
 		return "", nil, fmt.Errorf("temporary disabling automatic volume mounts: %w", err)
 	}
-	defer func() {
+	// The mounts are also reverted if the build exits on a second signal without unwinding
+	restoreMounts := process.RegisterCleanup(func() {
 		if revertErr := revert(); revertErr != nil {
 			zap.S().Warnf("failed to enable default mounts: %s", revertErr)
 		}
-	}()
+	})
+	defer restoreMounts.Run()
 
 	if r.baseImageRef, err = r.baseResolverImageBuilder.Build(); err != nil {
 		return "", nil, fmt.Errorf("building base resolver image: %w", err)
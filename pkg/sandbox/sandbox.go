@@ -0,0 +1,224 @@
+package sandbox
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	dockerfileName       = "Dockerfile"
+	runnerScriptName     = "run-combustion-scripts.sh"
+	combustionScriptName = "script"
+	sandboxImageRef      = "combustion-sandbox"
+
+	combustionMountDir = "/eib-sandbox/combustion-src"
+	artefactsMountDir  = "/mnt/artefacts"
+	resultsMountDir    = "/eib-sandbox/results"
+)
+
+//go:embed templates/Dockerfile.tpl
+var dockerfileTemplate string
+
+//go:embed templates/run-combustion-scripts.sh.tpl
+var runnerScriptTemplate string
+
+// Commands which require a booted system and can not be run in a container.
+var stubbedCommands = []string{
+	"mount", "umount", "systemctl", "udevadm", "modprobe", "sysctl", "setenforce",
+	"hostnamectl", "timedatectl", "localectl", "reboot",
+}
+
+// Scripts are run from the combustion script by their relative path at the start of a line.
+// Indented calls, such as the network configuration in the prepare phase, are skipped.
+var scriptCallRegexp = regexp.MustCompile(`^\./(\S+)$`)
+
+type Podman interface {
+	BuildWithVolumes(context, name string, volumes []string) error
+}
+
+type BaseImageBuilder interface {
+	Build() (string, error)
+}
+
+type Sandbox struct {
+	// dir from where the sandbox will work
+	dir string
+	// podman client which to use for building the sandbox image
+	podman Podman
+	// baseImageBuilder builder for the image of the base image filesystem the scripts are run in
+	baseImageBuilder BaseImageBuilder
+}
+
+type ScriptResult struct {
+	Name string
+	// ExitCode of the script, or -1 if the script did not run
+	ExitCode int
+	// LogPath is the path to the output of the script
+	LogPath string
+}
+
+func (r ScriptResult) Passed() bool {
+	return r.ExitCode == 0
+}
+
+func New(workDir string, podman Podman, baseImageBuilder BaseImageBuilder) *Sandbox {
+	return &Sandbox{
+		dir:              filepath.Join(workDir, "sandbox"),
+		podman:           podman,
+		baseImageBuilder: baseImageBuilder,
+	}
+}
+
+// Run executes the combustion scripts in a container built from the base image filesystem.
+// Commands requiring a booted system are stubbed. The result of every script is returned in the
+// order the scripts are run by combustion.
+func (s *Sandbox) Run(combustionDir, artefactsDir string) ([]ScriptResult, error) {
+	scripts, err := parseScripts(filepath.Join(combustionDir, combustionScriptName))
+	if err != nil {
+		return nil, fmt.Errorf("parsing combustion script: %w", err)
+	}
+
+	resultsDir := filepath.Join(s.dir, "results")
+	if err = os.MkdirAll(resultsDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating %s dir: %w", resultsDir, err)
+	}
+
+	baseImage, err := s.baseImageBuilder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("building base image: %w", err)
+	}
+
+	if err = s.writeDockerfile(baseImage); err != nil {
+		return nil, fmt.Errorf("writing dockerfile: %w", err)
+	}
+
+	if err = s.writeRunnerScript(scripts); err != nil {
+		return nil, fmt.Errorf("writing runner script: %w", err)
+	}
+
+	volumes := []string{
+		fmt.Sprintf("%s:%s:ro", combustionDir, combustionMountDir),
+		fmt.Sprintf("%s:%s:ro", artefactsDir, artefactsMountDir),
+		fmt.Sprintf("%s:%s", resultsDir, resultsMountDir),
+	}
+
+	zap.S().Infof("Running %d combustion scripts in the sandbox", len(scripts))
+
+	if err = s.podman.BuildWithVolumes(s.dir, sandboxImageRef, volumes); err != nil {
+		return nil, fmt.Errorf("building sandbox image: %w", err)
+	}
+
+	return readResults(resultsDir, scripts)
+}
+
+func parseScripts(scriptPath string) ([]string, error) {
+	file, err := os.Open(scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", scriptPath, err)
+	}
+	defer file.Close()
+
+	var scripts []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if m := scriptCallRegexp.FindStringSubmatch(scanner.Text()); m != nil {
+			scripts = append(scripts, m[1])
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", scriptPath, err)
+	}
+
+	return scripts, nil
+}
+
+func (s *Sandbox) writeDockerfile(baseImage string) error {
+	values := struct {
+		BaseImage        string
+		RunnerScriptName string
+	}{
+		BaseImage:        baseImage,
+		RunnerScriptName: runnerScriptName,
+	}
+
+	data, err := template.Parse(dockerfileName, dockerfileTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("parsing %s template: %w", dockerfileName, err)
+	}
+
+	filename := filepath.Join(s.dir, dockerfileName)
+	if err = os.WriteFile(filename, []byte(data), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+func (s *Sandbox) writeRunnerScript(scripts []string) error {
+	values := struct {
+		CombustionDir string
+		ArtefactsDir  string
+		ResultsDir    string
+		Scripts       []string
+		Stubs         []string
+	}{
+		CombustionDir: combustionMountDir,
+		ArtefactsDir:  artefactsMountDir,
+		ResultsDir:    resultsMountDir,
+		Scripts:       scripts,
+		Stubs:         stubbedCommands,
+	}
+
+	data, err := template.Parse(runnerScriptName, runnerScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("parsing %s template: %w", runnerScriptName, err)
+	}
+
+	filename := filepath.Join(s.dir, runnerScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+func readResults(resultsDir string, scripts []string) ([]ScriptResult, error) {
+	var results []ScriptResult
+
+	for _, script := range scripts {
+		result := ScriptResult{
+			Name:     script,
+			ExitCode: -1,
+			LogPath:  filepath.Join(resultsDir, script+".log"),
+		}
+
+		status, err := os.ReadFile(filepath.Join(resultsDir, script+".status"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading exit code of %s: %w", script, err)
+		}
+
+		if err == nil {
+			if result.ExitCode, err = strconv.Atoi(strings.TrimSpace(string(status))); err != nil {
+				return nil, fmt.Errorf("parsing exit code of %s: %w", script, err)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
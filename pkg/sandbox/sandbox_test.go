@@ -0,0 +1,148 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPodman struct {
+	buildFunc func(context, name string, volumes []string) error
+}
+
+func (m mockPodman) BuildWithVolumes(context, name string, volumes []string) error {
+	if m.buildFunc != nil {
+		return m.buildFunc(context, name, volumes)
+	}
+
+	panic("not implemented")
+}
+
+type mockBaseImageBuilder struct {
+	buildFunc func() (string, error)
+}
+
+func (m mockBaseImageBuilder) Build() (string, error) {
+	if m.buildFunc != nil {
+		return m.buildFunc()
+	}
+
+	panic("not implemented")
+}
+
+const combustionScript = `#!/bin/bash
+set -euo pipefail
+
+# combustion: prepare network
+
+if [ "${1-}" = "--prepare" ]; then
+    ./05-configure-network.sh
+    exit 0
+fi
+
+echo "Running 10-users.sh"
+./10-users.sh
+echo "Completed 10-users.sh"
+
+echo "Running 20-custom.sh"
+./20-custom.sh
+echo "Completed 20-custom.sh"
+
+echo "Running 30-cleanup.sh"
+./30-cleanup.sh
+echo "Completed 30-cleanup.sh"
+`
+
+func setupCombustionDir(t *testing.T) (combustionDir, artefactsDir string) {
+	combustionDir = filepath.Join(t.TempDir(), "combustion")
+	artefactsDir = filepath.Join(t.TempDir(), "artefacts")
+
+	require.NoError(t, os.MkdirAll(combustionDir, 0o700))
+	require.NoError(t, os.MkdirAll(artefactsDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(combustionDir, "script"), []byte(combustionScript), 0o700))
+
+	return combustionDir, artefactsDir
+}
+
+func TestParseScripts(t *testing.T) {
+	combustionDir, _ := setupCombustionDir(t)
+
+	scripts, err := parseScripts(filepath.Join(combustionDir, "script"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"10-users.sh", "20-custom.sh", "30-cleanup.sh"}, scripts)
+}
+
+func TestRun(t *testing.T) {
+	workDir := t.TempDir()
+	combustionDir, artefactsDir := setupCombustionDir(t)
+
+	podman := mockPodman{
+		buildFunc: func(context, name string, volumes []string) error {
+			assert.Equal(t, filepath.Join(workDir, "sandbox"), context)
+			assert.Equal(t, sandboxImageRef, name)
+
+			dockerfile, err := os.ReadFile(filepath.Join(context, dockerfileName))
+			require.NoError(t, err)
+			assert.Contains(t, string(dockerfile), "FROM resolver-base-tarball-image")
+			assert.Contains(t, string(dockerfile), "RUN /run-combustion-scripts.sh")
+
+			runner, err := os.ReadFile(filepath.Join(context, runnerScriptName))
+			require.NoError(t, err)
+			assert.Contains(t, string(runner), "for stub in mount umount systemctl")
+			assert.Contains(t, string(runner), "./20-custom.sh > /eib-sandbox/results/20-custom.sh.log 2>&1")
+			assert.NotContains(t, string(runner), "05-configure-network.sh")
+
+			resultsDir := filepath.Join(context, "results")
+			assert.Equal(t, []string{
+				combustionDir + ":/eib-sandbox/combustion-src:ro",
+				artefactsDir + ":/mnt/artefacts:ro",
+				resultsDir + ":/eib-sandbox/results",
+			}, volumes)
+
+			// Simulate the runner script, where the last script is never reached
+			for script, status := range map[string]int{"10-users.sh": 0, "20-custom.sh": 127} {
+				require.NoError(t, os.WriteFile(filepath.Join(resultsDir, script+".status"), []byte(fmt.Sprintf("%d\n", status)), 0o600))
+			}
+
+			return nil
+		},
+	}
+
+	baseBuilder := mockBaseImageBuilder{
+		buildFunc: func() (string, error) {
+			return "resolver-base-tarball-image", nil
+		},
+	}
+
+	results, err := New(workDir, podman, baseBuilder).Run(combustionDir, artefactsDir)
+	require.NoError(t, err)
+
+	resultsDir := filepath.Join(workDir, "sandbox", "results")
+	assert.Equal(t, []ScriptResult{
+		{Name: "10-users.sh", ExitCode: 0, LogPath: filepath.Join(resultsDir, "10-users.sh.log")},
+		{Name: "20-custom.sh", ExitCode: 127, LogPath: filepath.Join(resultsDir, "20-custom.sh.log")},
+		{Name: "30-cleanup.sh", ExitCode: -1, LogPath: filepath.Join(resultsDir, "30-cleanup.sh.log")},
+	}, results)
+
+	assert.True(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	assert.False(t, results[2].Passed())
+}
+
+func TestRun_BaseImageFailure(t *testing.T) {
+	combustionDir, artefactsDir := setupCombustionDir(t)
+
+	baseBuilder := mockBaseImageBuilder{
+		buildFunc: func() (string, error) {
+			return "", fmt.Errorf("guestfish failed")
+		},
+	}
+
+	_, err := New(t.TempDir(), mockPodman{}, baseBuilder).Run(combustionDir, artefactsDir)
+	assert.EqualError(t, err, "building base image: guestfish failed")
+}
@@ -0,0 +1,8 @@
+#  Template Fields
+#  BaseImage        - image built from the base image of the build
+#  RunnerScriptName - name of the script running the combustion scripts
+FROM {{ .BaseImage }}
+
+COPY {{ .RunnerScriptName }} /{{ .RunnerScriptName }}
+
+RUN /{{ .RunnerScriptName }}
@@ -0,0 +1,35 @@
+#!/bin/bash
+
+#  Template Fields
+#  CombustionDir - directory the combustion directory is mounted to
+#  ArtefactsDir  - directory the artefacts directory is mounted to
+#  ResultsDir    - directory the logs and exit codes of the scripts are written to
+#  Scripts       - combustion scripts in the order they are run
+#  Stubs         - commands requiring a booted system, which are replaced by no-ops
+
+# The scripts are not expected to succeed; failures are reported through the results directory
+set -uo pipefail
+
+STUBS_DIR=/eib-sandbox/stubs
+WORK_DIR=/eib-sandbox/combustion
+
+mkdir -p $STUBS_DIR
+for stub in {{ join .Stubs " " }}; do
+	printf '#!/bin/sh\necho "Skipping %s in the sandbox: $*"\n' $stub > $STUBS_DIR/$stub
+	chmod +x $STUBS_DIR/$stub
+done
+
+export PATH=$STUBS_DIR:$PATH
+export ARTEFACTS_DIR={{ .ArtefactsDir }}
+
+# Scripts may write to their own directory which is mounted read-only
+cp -a {{ .CombustionDir }} $WORK_DIR
+cd $WORK_DIR
+
+{{ range .Scripts -}}
+./{{ . }} > {{ $.ResultsDir }}/{{ . }}.log 2>&1
+echo $? > {{ $.ResultsDir }}/{{ . }}.status
+
+{{ end -}}
+
+exit 0
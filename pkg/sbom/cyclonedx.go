@@ -0,0 +1,141 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	cycloneDXSpecVersion = "1.5"
+	cycloneDXImageRef    = "image"
+)
+
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef             string              `json:"bom-ref,omitempty"`
+	Type               string              `json:"type"`
+	Name               string              `json:"name"`
+	Version            string              `json:"version,omitempty"`
+	Hashes             []cycloneDXHash     `json:"hashes,omitempty"`
+	PURL               string              `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExtRef   `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+var cycloneDXTypes = map[string]string{
+	TypeBaseImage:      "operating-system",
+	TypePackage:        "library",
+	TypeKubernetes:     "application",
+	TypeContainerImage: "container",
+	TypeHelmChart:      "application",
+}
+
+// CycloneDX serializes the document as CycloneDX 1.5 JSON.
+func CycloneDX(doc *Document) ([]byte, error) {
+	document := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: doc.Created.UTC().Format(time.RFC3339),
+			Tools: cycloneDXTools{
+				Components: []cycloneDXComponent{
+					{
+						Type:    "application",
+						Name:    toolName,
+						Version: doc.ToolVersion,
+					},
+				},
+			},
+			Component: cycloneDXComponent{
+				BOMRef: cycloneDXImageRef,
+				Type:   "operating-system",
+				Name:   doc.Name,
+			},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	dependency := cycloneDXDependency{Ref: cycloneDXImageRef, DependsOn: []string{}}
+
+	for i, component := range doc.Components {
+		ref := fmt.Sprintf("%s-%d", component.Type, i+1)
+
+		c := cycloneDXComponent{
+			BOMRef:  ref,
+			Type:    cycloneDXTypes[component.Type],
+			Name:    component.Name,
+			Version: component.Version,
+			PURL:    component.purl(),
+			Properties: []cycloneDXProperty{
+				{Name: "eib:type", Value: component.Type},
+			},
+		}
+
+		if component.SHA256 != "" {
+			c.Hashes = append(c.Hashes, cycloneDXHash{Algorithm: "SHA-256", Content: component.SHA256})
+		}
+
+		if component.Arch != "" {
+			c.Properties = append(c.Properties, cycloneDXProperty{Name: "eib:arch", Value: component.Arch})
+		}
+
+		if component.Digest != "" {
+			c.Properties = append(c.Properties, cycloneDXProperty{Name: "eib:digest", Value: component.Digest})
+		}
+
+		if component.Source != "" {
+			c.ExternalReferences = append(c.ExternalReferences, cycloneDXExtRef{Type: "distribution", URL: component.Source})
+		}
+
+		document.Components = append(document.Components, c)
+		dependency.DependsOn = append(dependency.DependsOn, ref)
+	}
+
+	document.Dependencies = []cycloneDXDependency{dependency}
+
+	return json.MarshalIndent(document, "", "  ")
+}
@@ -0,0 +1,43 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycloneDX(t *testing.T) {
+	data, err := CycloneDX(testDocument())
+	require.NoError(t, err)
+
+	var document cycloneDXDocument
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	assert.Equal(t, "CycloneDX", document.BOMFormat)
+	assert.Equal(t, "1.5", document.SpecVersion)
+	assert.Contains(t, document.SerialNumber, "urn:uuid:")
+	assert.Equal(t, "2026-10-18T12:00:00Z", document.Metadata.Timestamp)
+	assert.Equal(t, "eib-image.raw", document.Metadata.Component.Name)
+	assert.Equal(t, "1.4.0", document.Metadata.Tools.Components[0].Version)
+
+	require.Len(t, document.Components, 5)
+
+	baseImage := document.Components[0]
+	assert.Equal(t, "operating-system", baseImage.Type)
+	assert.Equal(t, []cycloneDXHash{{Algorithm: "SHA-256", Content: "c0"}}, baseImage.Hashes)
+
+	containerImage := document.Components[3]
+	assert.Equal(t, "container", containerImage.Type)
+	assert.Equal(t, "latest", containerImage.Version)
+	assert.Contains(t, containerImage.Properties, cycloneDXProperty{Name: "eib:digest", Value: "sha256:abc123"})
+	assert.NotEmpty(t, containerImage.PURL)
+
+	chart := document.Components[4]
+	assert.Equal(t, []cycloneDXExtRef{{Type: "distribution", URL: "oci://registry-1.docker.io/bitnamicharts"}}, chart.ExternalReferences)
+
+	require.Len(t, document.Dependencies, 1)
+	assert.Equal(t, cycloneDXImageRef, document.Dependencies[0].Ref)
+	assert.Len(t, document.Dependencies[0].DependsOn, 5)
+}
@@ -0,0 +1,141 @@
+package sbom
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+)
+
+const (
+	SPDXExtension      = ".spdx.json"
+	CycloneDXExtension = ".cdx.json"
+
+	toolName = "edge-image-builder"
+)
+
+const (
+	TypeBaseImage      = "base-image"
+	TypePackage        = "package"
+	TypeKubernetes     = "kubernetes"
+	TypeContainerImage = "container-image"
+	TypeHelmChart      = "helm-chart"
+)
+
+// typeOrder determines the order in which the components are listed in the documents.
+var typeOrder = []string{TypeBaseImage, TypePackage, TypeKubernetes, TypeContainerImage, TypeHelmChart}
+
+// Component is a single piece of software shipped with an image.
+type Component struct {
+	Type    string
+	Name    string
+	Version string
+	// Arch is the architecture RPM packages are built for.
+	Arch string
+	// SHA256 is the checksum of the file the component is shipped in.
+	SHA256 string
+	// Digest is the manifest digest of container images, including the algorithm prefix.
+	Digest string
+	// Source is the location the component was obtained from.
+	Source string
+}
+
+// Inventory collects the components shipped with an image while it is being configured.
+type Inventory struct {
+	components []Component
+}
+
+func (i *Inventory) Add(components ...Component) {
+	i.components = append(i.components, components...)
+}
+
+// Len returns the number of collected components.
+func (i *Inventory) Len() int {
+	return len(i.components)
+}
+
+// Since returns the components added after the first n, in the order they were added.
+func (i *Inventory) Since(n int) []Component {
+	return slices.Clone(i.components[n:])
+}
+
+// Components returns the collected components ordered by type, name and version.
+func (i *Inventory) Components() []Component {
+	components := slices.Clone(i.components)
+
+	slices.SortStableFunc(components, func(a, b Component) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(typeOrder, a.Type), slices.Index(typeOrder, b.Type)),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Version, b.Version),
+		)
+	})
+
+	return components
+}
+
+// Document describes the contents of a single image.
+type Document struct {
+	// Name is the name of the image the document describes.
+	Name        string
+	ToolVersion string
+	Created     time.Time
+	Components  []Component
+}
+
+// Write stores the document in both SPDX and CycloneDX formats next to the given output path.
+func Write(outputPath string, doc *Document) error {
+	formats := []struct {
+		extension string
+		marshal   func(*Document) ([]byte, error)
+	}{
+		{extension: SPDXExtension, marshal: SPDX},
+		{extension: CycloneDXExtension, marshal: CycloneDX},
+	}
+
+	for _, format := range formats {
+		data, err := format.marshal(doc)
+		if err != nil {
+			return fmt.Errorf("creating %s document: %w", format.extension, err)
+		}
+
+		if err = os.WriteFile(outputPath+format.extension, data, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("writing %s document: %w", format.extension, err)
+		}
+	}
+
+	return nil
+}
+
+// purl returns the package URL identifying RPM packages and container images, or an empty
+// string for components which do not have a package URL type.
+func (c *Component) purl() string {
+	switch c.Type {
+	case TypePackage:
+		purl := fmt.Sprintf("pkg:rpm/%s@%s", url.PathEscape(c.Name), url.PathEscape(c.Version))
+		if c.Arch != "" {
+			purl += "?arch=" + url.QueryEscape(c.Arch)
+		}
+		return purl
+	case TypeContainerImage:
+		if c.Digest == "" {
+			return ""
+		}
+
+		name := c.Name[strings.LastIndex(c.Name, "/")+1:]
+		query := url.Values{"repository_url": {c.Name}}
+		if c.Version != "" {
+			query.Set("tag", c.Version)
+		}
+
+		digest := strings.ReplaceAll(c.Digest, ":", "%3A")
+		return fmt.Sprintf("pkg:oci/%s@%s?%s", url.PathEscape(name), digest, query.Encode())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,95 @@
+package sbom
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() *Document {
+	inventory := &Inventory{}
+	inventory.Add(
+		Component{Type: TypeHelmChart, Name: "apache", Version: "10.7.0", SHA256: "c3", Source: "oci://registry-1.docker.io/bitnamicharts"},
+		Component{Type: TypeContainerImage, Name: "docker.io/library/hello-world", Version: "latest", Digest: "sha256:abc123"},
+		Component{Type: TypePackage, Name: "zsh", Version: "5.9-1.1", Arch: "x86_64", SHA256: "c2"},
+		Component{Type: TypePackage, Name: "git", Version: "2.43.0-1.1", Arch: "x86_64", SHA256: "c1"},
+		Component{Type: TypeBaseImage, Name: "SL-Micro.x86_64-6.0-Base-GM2.raw", SHA256: "c0"},
+	)
+
+	return &Document{
+		Name:        "eib-image.raw",
+		ToolVersion: "1.4.0",
+		Created:     time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Components:  inventory.Components(),
+	}
+}
+
+func TestInventoryComponents(t *testing.T) {
+	var names []string
+	for _, c := range testDocument().Components {
+		names = append(names, c.Name)
+	}
+
+	assert.Equal(t, []string{
+		"SL-Micro.x86_64-6.0-Base-GM2.raw",
+		"git",
+		"zsh",
+		"docker.io/library/hello-world",
+		"apache",
+	}, names)
+}
+
+func TestInventorySince(t *testing.T) {
+	var inventory Inventory
+	inventory.Add(Component{Type: TypePackage, Name: "zsh"})
+
+	n := inventory.Len()
+	inventory.Add(Component{Type: TypeHelmChart, Name: "apache"}, Component{Type: TypePackage, Name: "git"})
+
+	assert.Equal(t, 3, inventory.Len())
+	assert.Equal(t, []Component{
+		{Type: TypeHelmChart, Name: "apache"},
+		{Type: TypePackage, Name: "git"},
+	}, inventory.Since(n))
+	assert.Empty(t, inventory.Since(inventory.Len()))
+}
+
+func TestComponentPURL(t *testing.T) {
+	tests := map[string]struct {
+		component    Component
+		expectedPURL string
+	}{
+		"Package": {
+			component:    Component{Type: TypePackage, Name: "git", Version: "2.43.0-1.1", Arch: "x86_64"},
+			expectedPURL: "pkg:rpm/git@2.43.0-1.1?arch=x86_64",
+		},
+		"Container image": {
+			component:    Component{Type: TypeContainerImage, Name: "docker.io/library/hello-world", Version: "latest", Digest: "sha256:abc123"},
+			expectedPURL: "pkg:oci/hello-world@sha256%3Aabc123?repository_url=docker.io%2Flibrary%2Fhello-world&tag=latest",
+		},
+		"Container image without digest": {
+			component: Component{Type: TypeContainerImage, Name: "docker.io/library/hello-world", Version: "latest"},
+		},
+		"Helm chart": {
+			component: Component{Type: TypeHelmChart, Name: "apache", Version: "10.7.0"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedPURL, test.component.purl())
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "eib-image.raw")
+
+	require.NoError(t, Write(outputPath, testDocument()))
+
+	assert.FileExists(t, outputPath+SPDXExtension)
+	assert.FileExists(t, outputPath+CycloneDXExtension)
+}
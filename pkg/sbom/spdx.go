@@ -0,0 +1,143 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxNoAssertion = "NOASSERTION"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxImageID     = "SPDXRef-Image"
+	spdxNamespace   = "https://github.com/suse-edge/edge-image-builder/spdx"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+var spdxPurposes = map[string]string{
+	TypeBaseImage:      "OPERATING-SYSTEM",
+	TypePackage:        "LIBRARY",
+	TypeKubernetes:     "APPLICATION",
+	TypeContainerImage: "CONTAINER",
+	TypeHelmChart:      "APPLICATION",
+}
+
+// SPDX serializes the document as SPDX 2.3 JSON.
+func SPDX(doc *Document) ([]byte, error) {
+	document := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              doc.Name,
+		DocumentNamespace: fmt.Sprintf("%s/%s-%s", spdxNamespace, doc.Name, uuid.NewString()),
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.Created.UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: %s-%s", toolName, doc.ToolVersion)},
+		},
+		Packages: []spdxPackage{
+			{
+				Name:                  doc.Name,
+				SPDXID:                spdxImageID,
+				DownloadLocation:      spdxNoAssertion,
+				PrimaryPackagePurpose: "OPERATING-SYSTEM",
+			},
+		},
+		Relationships: []spdxRelationship{
+			{
+				SPDXElementID:      spdxDocumentID,
+				RelationshipType:   "DESCRIBES",
+				RelatedSPDXElement: spdxImageID,
+			},
+		},
+	}
+
+	for i, component := range doc.Components {
+		id := fmt.Sprintf("SPDXRef-%s-%d", component.Type, i+1)
+
+		pkg := spdxPackage{
+			Name:                  component.Name,
+			SPDXID:                id,
+			VersionInfo:           component.Version,
+			DownloadLocation:      spdxNoAssertion,
+			PrimaryPackagePurpose: spdxPurposes[component.Type],
+			Comment:               component.Type,
+		}
+
+		if component.Source != "" {
+			pkg.DownloadLocation = component.Source
+		}
+
+		if component.SHA256 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: component.SHA256})
+		}
+
+		if purl := component.purl(); purl != "" {
+			pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  purl,
+			})
+		}
+
+		relationship := "CONTAINS"
+		if component.Type == TypeBaseImage {
+			relationship = "DESCENDANT_OF"
+		}
+
+		document.Packages = append(document.Packages, pkg)
+		document.Relationships = append(document.Relationships, spdxRelationship{
+			SPDXElementID:      spdxImageID,
+			RelationshipType:   relationship,
+			RelatedSPDXElement: id,
+		})
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
@@ -0,0 +1,57 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSPDX(t *testing.T) {
+	data, err := SPDX(testDocument())
+	require.NoError(t, err)
+
+	var document spdxDocument
+	require.NoError(t, json.Unmarshal(data, &document))
+
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, "eib-image.raw", document.Name)
+	assert.Contains(t, document.DocumentNamespace, "https://github.com/suse-edge/edge-image-builder/spdx/eib-image.raw-")
+	assert.Equal(t, "2026-10-18T12:00:00Z", document.CreationInfo.Created)
+	assert.Equal(t, []string{"Tool: edge-image-builder-1.4.0"}, document.CreationInfo.Creators)
+
+	// The image itself followed by its components
+	require.Len(t, document.Packages, 6)
+	assert.Equal(t, spdxImageID, document.Packages[0].SPDXID)
+
+	git := document.Packages[2]
+	assert.Equal(t, "git", git.Name)
+	assert.Equal(t, "2.43.0-1.1", git.VersionInfo)
+	assert.Equal(t, "NOASSERTION", git.DownloadLocation)
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "c1"}}, git.Checksums)
+	assert.Equal(t, []spdxExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:rpm/git@2.43.0-1.1?arch=x86_64"},
+	}, git.ExternalRefs)
+
+	chart := document.Packages[5]
+	assert.Equal(t, "apache", chart.Name)
+	assert.Equal(t, "oci://registry-1.docker.io/bitnamicharts", chart.DownloadLocation)
+
+	require.Len(t, document.Relationships, 6)
+	assert.Equal(t, spdxRelationship{
+		SPDXElementID:      spdxDocumentID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: spdxImageID,
+	}, document.Relationships[0])
+	assert.Equal(t, spdxRelationship{
+		SPDXElementID:      spdxImageID,
+		RelationshipType:   "DESCENDANT_OF",
+		RelatedSPDXElement: document.Packages[1].SPDXID,
+	}, document.Relationships[1])
+	assert.Equal(t, spdxRelationship{
+		SPDXElementID:      spdxImageID,
+		RelationshipType:   "CONTAINS",
+		RelatedSPDXElement: git.SPDXID,
+	}, document.Relationships[2])
+}
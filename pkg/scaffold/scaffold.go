@@ -0,0 +1,252 @@
+package scaffold
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	PresetSingleNodeRKE2 = "single-node-rke2"
+	PresetMultiNodeK3s   = "multi-node-k3s"
+	PresetOSOnly         = "os-only"
+	PresetConfigDrive    = "config-drive"
+
+	baseImagesDir = "base-images"
+
+	defaultRKE2Version = "v1.30.3+rke2r1"
+	defaultK3sVersion  = "v1.30.3+k3s1"
+)
+
+var (
+	//go:embed templates/single-node-rke2.yaml.tpl
+	singleNodeRKE2Template string
+
+	//go:embed templates/multi-node-k3s.yaml.tpl
+	multiNodeK3sTemplate string
+
+	//go:embed templates/os-only.yaml.tpl
+	osOnlyTemplate string
+
+	//go:embed templates/config-drive.yaml.tpl
+	configDriveTemplate string
+
+	//go:embed templates/rke2-server.yaml
+	rke2ServerConfig string
+
+	//go:embed templates/k3s-server.yaml
+	k3sServerConfig string
+
+	//go:embed templates/k3s-agent.yaml
+	k3sAgentConfig string
+)
+
+type preset struct {
+	definitionTemplate string
+	imageType          string
+	// files are created relative to the configuration directory along with their parent directories
+	files map[string]string
+	dirs  []string
+}
+
+var presets = map[string]preset{
+	PresetSingleNodeRKE2: {
+		definitionTemplate: singleNodeRKE2Template,
+		imageType:          image.TypeISO,
+		files: map[string]string{
+			filepath.Join("kubernetes", "config", "server.yaml"): rke2ServerConfig,
+		},
+		dirs: []string{filepath.Join("kubernetes", "helm", "values")},
+	},
+	PresetMultiNodeK3s: {
+		definitionTemplate: multiNodeK3sTemplate,
+		imageType:          image.TypeISO,
+		files: map[string]string{
+			filepath.Join("kubernetes", "config", "server.yaml"): k3sServerConfig,
+			filepath.Join("kubernetes", "config", "agent.yaml"):  k3sAgentConfig,
+		},
+		dirs: []string{filepath.Join("kubernetes", "helm", "values")},
+	},
+	PresetOSOnly: {
+		definitionTemplate: osOnlyTemplate,
+		imageType:          image.TypeRAW,
+	},
+	PresetConfigDrive: {
+		definitionTemplate: configDriveTemplate,
+	},
+}
+
+// Presets returns the names of all supported presets.
+func Presets() []string {
+	return []string{PresetSingleNodeRKE2, PresetMultiNodeK3s, PresetOSOnly, PresetConfigDrive}
+}
+
+type Options struct {
+	Preset         string
+	DefinitionFile string
+	Arch           image.Arch
+	// BaseImage is either the path to an existing base image which is copied into the configuration directory,
+	// or the name of a base image which is already placed there. Required by all presets building an image.
+	BaseImage string
+}
+
+// Result describes the created configuration directory.
+type Result struct {
+	// Created lists the created files and directories relative to the configuration directory.
+	Created []string
+	// BaseImage is the name of the base image referenced by the definition.
+	BaseImage string
+}
+
+// RequiresBaseImage reports whether the preset builds an image and therefore needs a base image.
+func RequiresBaseImage(preset string) bool {
+	return presets[preset].imageType != ""
+}
+
+// Create populates the configuration directory with the layout and a minimal definition for the given preset.
+// Existing files are never overwritten.
+func Create(configDir string, opts *Options) (*Result, error) {
+	p, ok := presets[opts.Preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset '%s'", opts.Preset)
+	}
+
+	if !slices.Contains([]image.Arch{image.ArchTypeX86, image.ArchTypeARM}, opts.Arch) {
+		return nil, fmt.Errorf("unsupported architecture '%s'", opts.Arch)
+	}
+
+	// The definition would otherwise reference a base image which fails validation
+	if p.imageType != "" && opts.BaseImage == "" {
+		return nil, fmt.Errorf("preset '%s' requires a base image", opts.Preset)
+	}
+
+	definitionPath := filepath.Join(configDir, opts.DefinitionFile)
+	if _, err := os.Stat(definitionPath); err == nil {
+		return nil, fmt.Errorf("definition file '%s' already exists", definitionPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("checking definition file: %w", err)
+	}
+
+	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating configuration directory: %w", err)
+	}
+
+	result := &Result{}
+
+	if p.imageType != "" {
+		if err := setupBaseImage(configDir, opts, result); err != nil {
+			return nil, fmt.Errorf("setting up base image: %w", err)
+		}
+	}
+
+	for _, dir := range p.dirs {
+		if err := os.MkdirAll(filepath.Join(configDir, dir), os.ModePerm); err != nil {
+			return nil, fmt.Errorf("creating directory '%s': %w", dir, err)
+		}
+
+		result.Created = append(result.Created, dir+string(filepath.Separator))
+	}
+
+	for file, contents := range p.files {
+		created, err := writeFile(configDir, file, contents)
+		if err != nil {
+			return nil, err
+		}
+
+		if created {
+			result.Created = append(result.Created, file)
+		}
+	}
+
+	values := struct {
+		APIVersion  string
+		Arch        image.Arch
+		BaseImage   string
+		RKE2Version string
+		K3sVersion  string
+	}{
+		APIVersion:  version.SupportedSchemaVersions[len(version.SupportedSchemaVersions)-1],
+		Arch:        opts.Arch,
+		BaseImage:   result.BaseImage,
+		RKE2Version: defaultRKE2Version,
+		K3sVersion:  defaultK3sVersion,
+	}
+
+	definition, err := template.Parse(opts.Preset, p.definitionTemplate, &values)
+	if err != nil {
+		return nil, fmt.Errorf("parsing definition template: %w", err)
+	}
+
+	if _, err = writeFile(configDir, opts.DefinitionFile, definition); err != nil {
+		return nil, err
+	}
+
+	result.Created = append(result.Created, opts.DefinitionFile)
+	slices.Sort(result.Created)
+
+	return result, nil
+}
+
+func setupBaseImage(configDir string, opts *Options, result *Result) error {
+	imagesDir := filepath.Join(configDir, baseImagesDir)
+	result.BaseImage = filepath.Base(opts.BaseImage)
+
+	destination := filepath.Join(imagesDir, result.BaseImage)
+	if _, err := os.Stat(destination); err == nil {
+		return nil
+	}
+
+	info, err := os.Stat(opts.BaseImage)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("base image '%s' not found", opts.BaseImage)
+	} else if err != nil {
+		return fmt.Errorf("reading base image: %w", err)
+	} else if info.IsDir() {
+		return fmt.Errorf("base image '%s' is a directory", opts.BaseImage)
+	}
+
+	if err = os.MkdirAll(imagesDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	result.Created = append(result.Created, baseImagesDir+string(filepath.Separator))
+
+	// Base images are large, avoid copying them where possible
+	if err = os.Link(opts.BaseImage, destination); err != nil {
+		if err = fileio.CopyFile(opts.BaseImage, destination, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying base image: %w", err)
+		}
+	}
+
+	result.Created = append(result.Created, filepath.Join(baseImagesDir, result.BaseImage))
+
+	return nil
+}
+
+// writeFile creates the file unless it already exists and reports whether it was created.
+func writeFile(configDir, file, contents string) (bool, error) {
+	path := filepath.Join(configDir, file)
+
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return false, fmt.Errorf("creating directory for '%s': %w", file, err)
+	}
+
+	if err := os.WriteFile(path, []byte(contents), fileio.NonExecutablePerms); err != nil {
+		return false, fmt.Errorf("writing '%s': %w", file, err)
+	}
+
+	return true, nil
+}
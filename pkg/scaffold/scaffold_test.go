@@ -0,0 +1,168 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+)
+
+func TestCreate_PresetsPassValidation(t *testing.T) {
+	baseImage := filepath.Join(t.TempDir(), "base.img")
+	require.NoError(t, os.WriteFile(baseImage, []byte("image"), 0o600))
+
+	tests := map[string]struct {
+		expectedCreated []string
+		isConfigDrive   bool
+	}{
+		PresetSingleNodeRKE2: {
+			expectedCreated: []string{
+				"base-images/",
+				"base-images/base.img",
+				"definition.yaml",
+				"kubernetes/config/server.yaml",
+				"kubernetes/helm/values/",
+			},
+		},
+		PresetMultiNodeK3s: {
+			expectedCreated: []string{
+				"base-images/",
+				"base-images/base.img",
+				"definition.yaml",
+				"kubernetes/config/agent.yaml",
+				"kubernetes/config/server.yaml",
+				"kubernetes/helm/values/",
+			},
+		},
+		PresetOSOnly: {
+			expectedCreated: []string{
+				"base-images/",
+				"base-images/base.img",
+				"definition.yaml",
+			},
+		},
+		PresetConfigDrive: {
+			expectedCreated: []string{
+				"definition.yaml",
+			},
+			isConfigDrive: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+
+			result, err := Create(configDir, &Options{
+				Preset:         name,
+				DefinitionFile: "definition.yaml",
+				Arch:           image.ArchTypeX86,
+				BaseImage:      baseImage,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCreated, result.Created)
+
+			data, err := os.ReadFile(filepath.Join(configDir, "definition.yaml"))
+			require.NoError(t, err)
+
+			definition, err := image.ParseDefinition(data, configDir)
+			require.NoError(t, err)
+
+			ctx := &image.Context{
+				ImageConfigDir:  configDir,
+				ImageDefinition: definition,
+				IsConfigDrive:   test.isConfigDrive,
+			}
+
+			failures := validation.ValidateDefinition(ctx)
+			assert.False(t, validation.HasErrors(failures, false), "unexpected validation failures: %v", failures)
+		})
+	}
+}
+
+func TestCreate_MissingBaseImage(t *testing.T) {
+	for _, preset := range []string{PresetSingleNodeRKE2, PresetMultiNodeK3s, PresetOSOnly} {
+		t.Run(preset, func(t *testing.T) {
+			assert.True(t, RequiresBaseImage(preset))
+
+			configDir := t.TempDir()
+
+			_, err := Create(configDir, &Options{
+				Preset:         preset,
+				DefinitionFile: "definition.yaml",
+				Arch:           image.ArchTypeX86,
+			})
+			assert.EqualError(t, err, fmt.Sprintf("preset '%s' requires a base image", preset))
+
+			_, err = Create(configDir, &Options{
+				Preset:         preset,
+				DefinitionFile: "definition.yaml",
+				Arch:           image.ArchTypeX86,
+				BaseImage:      "SL-Micro.x86_64-6.0-Default-GM2.raw",
+			})
+			assert.EqualError(t, err, "setting up base image: base image 'SL-Micro.x86_64-6.0-Default-GM2.raw' not found")
+
+			assert.NoFileExists(t, filepath.Join(configDir, "definition.yaml"))
+		})
+	}
+
+	assert.False(t, RequiresBaseImage(PresetConfigDrive))
+}
+
+func TestCreate_ExistingBaseImage(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base-images", "base.raw"), []byte("image"), 0o600))
+
+	result, err := Create(configDir, &Options{
+		Preset:         PresetOSOnly,
+		DefinitionFile: "definition.yaml",
+		Arch:           image.ArchTypeARM,
+		BaseImage:      "base.raw",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"definition.yaml"}, result.Created)
+	assert.Equal(t, "base.raw", result.BaseImage)
+
+	data, err := os.ReadFile(filepath.Join(configDir, "definition.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "baseImage: base.raw")
+	assert.Contains(t, string(data), "arch: aarch64")
+
+	definition, err := image.ParseDefinition(data, configDir)
+	require.NoError(t, err)
+
+	failures := validation.ValidateDefinition(&image.Context{ImageConfigDir: configDir, ImageDefinition: definition})
+	assert.False(t, validation.HasErrors(failures, false), "unexpected validation failures: %v", failures)
+}
+
+func TestCreate_ExistingDefinition(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "definition.yaml"), []byte("apiVersion: 1.0"), 0o600))
+
+	_, err := Create(configDir, &Options{
+		Preset:         PresetConfigDrive,
+		DefinitionFile: "definition.yaml",
+		Arch:           image.ArchTypeX86,
+	})
+	require.ErrorContains(t, err, "definition.yaml' already exists")
+
+	data, err := os.ReadFile(filepath.Join(configDir, "definition.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: 1.0", string(data))
+}
+
+func TestCreate_InvalidOptions(t *testing.T) {
+	_, err := Create(t.TempDir(), &Options{Preset: "foo", Arch: image.ArchTypeX86})
+	assert.EqualError(t, err, "unknown preset 'foo'")
+
+	_, err = Create(t.TempDir(), &Options{Preset: PresetOSOnly, Arch: "riscv64"})
+	assert.EqualError(t, err, "unsupported architecture 'riscv64'")
+}
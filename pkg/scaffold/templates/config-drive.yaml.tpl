@@ -0,0 +1,7 @@
+apiVersion: {{ .APIVersion }}
+operatingSystem:
+  # Uncomment to configure users, the password hash can be generated with 'openssl passwd -6'
+  # users:
+  #   - username: root
+  #     encryptedPassword: <password hash>
+  keymap: us
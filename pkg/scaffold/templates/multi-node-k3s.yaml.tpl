@@ -0,0 +1,28 @@
+apiVersion: {{ .APIVersion }}
+image:
+  imageType: iso
+  arch: {{ .Arch }}
+  baseImage: {{ .BaseImage }}
+  outputImageName: eib-image.iso
+operatingSystem:
+  # Uncomment to configure users, the password hash can be generated with 'openssl passwd -6'
+  # users:
+  #   - username: root
+  #     encryptedPassword: <password hash>
+kubernetes:
+  version: {{ .K3sVersion }}
+  network:
+    apiVIP: 192.168.122.100
+    apiHost: api.cluster.local
+  nodes:
+    - hostname: node1.suse.com
+      type: server
+      initializer: true
+    - hostname: node2.suse.com
+      type: agent
+    - hostname: node3.suse.com
+      type: agent
+  # Helm charts values files are located under 'kubernetes/helm/values'
+  # helm:
+  #   charts: []
+  #   repositories: []
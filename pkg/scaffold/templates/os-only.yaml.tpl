@@ -0,0 +1,13 @@
+apiVersion: {{ .APIVersion }}
+image:
+  imageType: raw
+  arch: {{ .Arch }}
+  baseImage: {{ .BaseImage }}
+  outputImageName: eib-image.raw
+operatingSystem:
+  rawConfiguration:
+    diskSize: 32G
+  # Uncomment to configure users, the password hash can be generated with 'openssl passwd -6'
+  # users:
+  #   - username: root
+  #     encryptedPassword: <password hash>
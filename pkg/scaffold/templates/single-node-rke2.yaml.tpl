@@ -0,0 +1,17 @@
+apiVersion: {{ .APIVersion }}
+image:
+  imageType: iso
+  arch: {{ .Arch }}
+  baseImage: {{ .BaseImage }}
+  outputImageName: eib-image.iso
+operatingSystem:
+  # Uncomment to configure users, the password hash can be generated with 'openssl passwd -6'
+  # users:
+  #   - username: root
+  #     encryptedPassword: <password hash>
+kubernetes:
+  version: {{ .RKE2Version }}
+  # Helm charts values files are located under 'kubernetes/helm/values'
+  # helm:
+  #   charts: []
+  #   repositories: []
@@ -0,0 +1,138 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// LoadPrivateKey reads an unencrypted ed25519 or ECDSA private key from a PEM file.
+// Both PKCS #8 ("PRIVATE KEY") and SEC 1 ("EC PRIVATE KEY") encodings are supported.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing EC private key: %w", err)
+		}
+
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key: %w", err)
+		}
+
+		switch k := key.(type) {
+		case ed25519.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T, only ed25519 and ECDSA keys are supported", key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+}
+
+// LoadPublicKey reads an ed25519 or ECDSA public key from a PEM file.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, only ed25519 and ECDSA keys are supported", key)
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in '%s'", path)
+	}
+
+	return block, nil
+}
+
+// Sign signs the message with the given key. ECDSA signatures are ASN.1 encoded and computed
+// over the SHA-256 digest of the message, ed25519 signatures are computed over the message itself.
+func Sign(key crypto.Signer, message []byte) ([]byte, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, message), nil
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return ecdsa.SignASN1(rand.Reader, k, digest[:])
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// Verify checks the signature of the message created by Sign.
+func Verify(key crypto.PublicKey, message, signature []byte) error {
+	var valid bool
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, message, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		valid = ecdsa.VerifyASN1(k, digest[:], signature)
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// KeyID returns the hex encoded SHA-256 digest of the DER encoded public key.
+func KeyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("encoding public key: %w", err)
+	}
+
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,122 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+func TestSignAndVerify(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ed25519DER, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	require.NoError(t, err)
+
+	ecdsaPKCS8DER, err := x509.MarshalPKCS8PrivateKey(ecdsaKey)
+	require.NoError(t, err)
+
+	ecdsaSEC1DER, err := x509.MarshalECPrivateKey(ecdsaKey)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		blockType string
+		der       []byte
+		public    crypto.PublicKey
+	}{
+		"ed25519": {
+			blockType: "PRIVATE KEY",
+			der:       ed25519DER,
+			public:    ed25519Key.Public(),
+		},
+		"ECDSA PKCS #8": {
+			blockType: "PRIVATE KEY",
+			der:       ecdsaPKCS8DER,
+			public:    ecdsaKey.Public(),
+		},
+		"ECDSA SEC 1": {
+			blockType: "EC PRIVATE KEY",
+			der:       ecdsaSEC1DER,
+			public:    ecdsaKey.Public(),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := LoadPrivateKey(writePEM(t, test.blockType, test.der))
+			require.NoError(t, err)
+
+			publicDER, err := x509.MarshalPKIXPublicKey(test.public)
+			require.NoError(t, err)
+
+			public, err := LoadPublicKey(writePEM(t, "PUBLIC KEY", publicDER))
+			require.NoError(t, err)
+
+			signature, err := Sign(key, []byte("message"))
+			require.NoError(t, err)
+
+			assert.NoError(t, Verify(public, []byte("message"), signature))
+			assert.ErrorIs(t, Verify(public, []byte("tampered"), signature), ErrInvalidSignature)
+		})
+	}
+}
+
+func TestLoadPrivateKey_Errors(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+
+	noPEM := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(noPEM, []byte("not a key"), 0o600))
+
+	_, err = LoadPrivateKey(writePEM(t, "PRIVATE KEY", rsaDER))
+	assert.EqualError(t, err, "unsupported private key type *rsa.PrivateKey, only ed25519 and ECDSA keys are supported")
+
+	_, err = LoadPrivateKey(writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)))
+	assert.EqualError(t, err, "unsupported PEM block type 'RSA PRIVATE KEY'")
+
+	_, err = LoadPrivateKey(noPEM)
+	assert.EqualError(t, err, "no PEM data found in '"+noPEM+"'")
+
+	_, err = LoadPrivateKey(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, err, "reading key file: ")
+}
+
+func TestKeyID(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	id, err := KeyID(public)
+	require.NoError(t, err)
+	assert.Len(t, id, 64)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherID, err := KeyID(other)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, otherID)
+}